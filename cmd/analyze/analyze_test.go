@@ -2,13 +2,14 @@ package main
 
 import (
 	"encoding/gob"
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/tw93/mole/pkg/scan"
 )
 
 func resetOverviewSnapshotForTest() {
@@ -18,6 +19,17 @@ func resetOverviewSnapshotForTest() {
 	overviewSnapshotMu.Unlock()
 }
 
+func writeFileWithSize(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", path, err)
+	}
+	content := make([]byte, size)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
 func TestScanPathConcurrentBasic(t *testing.T) {
 	root := t.TempDir()
 
@@ -61,7 +73,7 @@ func TestScanPathConcurrentBasic(t *testing.T) {
 
 	expectedDirSize := int64(len("alpha") + len(strings.Repeat("b", 32)))
 	expectedRootFileSize := int64(len("root-data"))
-	expectedLinkSize := getActualFileSize(linkPath, linkInfo)
+	expectedLinkSize := scan.DiskUsage(linkInfo)
 	expectedTotal := expectedDirSize + expectedRootFileSize + expectedLinkSize
 
 	if result.TotalSize != expectedTotal {
@@ -279,6 +291,36 @@ func TestIsHandledByMoClean(t *testing.T) {
 }
 
 func TestIsCleanableDir(t *testing.T) {
+	base := t.TempDir()
+	mkdir := func(rel string) string {
+		t.Helper()
+		p := filepath.Join(base, rel)
+		if err := os.MkdirAll(p, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", p, err)
+		}
+		return p
+	}
+	touch := func(rel string) {
+		t.Helper()
+		p := filepath.Join(base, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", p, err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatalf("write %s: %v", p, err)
+		}
+	}
+
+	touch("web/package.json")
+	touch("rust/Cargo.toml")
+	touch("py/pyvenv-project/venv/pyvenv.cfg")
+	touch("android/build.gradle")
+	touch("gomod/go.mod")
+	touch("php/composer.json")
+	touch("infra/main.tf")
+	touch("ios/Podfile")
+	mkdir("Library/Developer/Xcode/DerivedData/ModuleCache.noindex")
+
 	tests := []struct {
 		name string
 		path string
@@ -287,21 +329,28 @@ func TestIsCleanableDir(t *testing.T) {
 		// Empty path.
 		{"empty string", "", false},
 
-		// Project dependencies (should be cleanable).
-		{"node_modules", "/Users/test/project/node_modules", true},
-		{"nested node_modules", "/Users/test/project/packages/app/node_modules", true},
-		{"venv", "/Users/test/project/venv", true},
-		{"dot venv", "/Users/test/project/.venv", true},
-		{"pycache", "/Users/test/project/src/__pycache__", true},
-		{"build dir", "/Users/test/project/build", true},
-		{"dist dir", "/Users/test/project/dist", true},
-		{"target dir", "/Users/test/project/target", true},
-		{"next.js cache", "/Users/test/project/.next", true},
-		{"DerivedData", "/Users/test/Library/Developer/Xcode/DerivedData", true},
-		{"Pods", "/Users/test/project/ios/Pods", true},
-		{"gradle cache", "/Users/test/project/.gradle", true},
-		{"coverage", "/Users/test/project/coverage", true},
-		{"terraform", "/Users/test/infra/.terraform", true},
+		// Project context present (should be cleanable).
+		{"node_modules with package.json", mkdir("web/node_modules"), true},
+		{"next.js cache", mkdir("web/.next"), true},
+		{"js dist", mkdir("web/dist"), true},
+		{"cargo target", mkdir("rust/target"), true},
+		{"venv with pyvenv.cfg", filepath.Join(base, "py/pyvenv-project/venv"), true},
+		{"pycache", mkdir("py/src/__pycache__"), true},
+		{"gradle cache", mkdir("android/.gradle"), true},
+		{"gradle build", mkdir("android/build"), true},
+		{"composer vendor", mkdir("php/vendor"), true},
+		{"terraform", mkdir("infra/.terraform"), true},
+		{"Pods", mkdir("ios/Pods"), true},
+		{"DerivedData", filepath.Join(base, "Library/Developer/Xcode/DerivedData"), true},
+
+		// Same names without project markers (should NOT be cleanable).
+		{"orphan node_modules", mkdir("loose/node_modules"), false},
+		{"orphan target", mkdir("loose/target"), false},
+		{"venv without pyvenv.cfg", mkdir("loose/venv"), false},
+		{"arbitrary out", mkdir("loose/out"), false},
+		{"arbitrary build", mkdir("loose/build"), false},
+		{"committed go vendor", mkdir("gomod/vendor"), false},
+		{"empty DerivedData", mkdir("loose/DerivedData"), false},
 
 		// Paths handled by mo clean (should NOT be cleanable).
 		{"user caches", "/Users/test/Library/Caches/com.example", false},
@@ -309,13 +358,9 @@ func TestIsCleanableDir(t *testing.T) {
 		{"trash", "/Users/test/.Trash/deleted", false},
 
 		// Not in projectDependencyDirs.
-		{"src dir", "/Users/test/project/src", false},
-		{"random dir", "/Users/test/project/random", false},
-		{"home dir", "/Users/test", false},
-		{".git dir", "/Users/test/project/.git", false},
-
-		// Edge cases.
-		{"just basename node_modules", "node_modules", true},
+		{"src dir", mkdir("web/src"), false},
+		{"home dir", base, false},
+		{".git dir", mkdir("web/.git"), false},
 		{"root path", "/", false},
 	}
 
@@ -329,6 +374,52 @@ func TestIsCleanableDir(t *testing.T) {
 	}
 }
 
+func TestMatchCleanableDirReportsReasonAndConfidence(t *testing.T) {
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "Cargo.toml"), nil, 0o644); err != nil {
+		t.Fatalf("write Cargo.toml: %v", err)
+	}
+	target := filepath.Join(base, "target")
+	if err := os.Mkdir(target, 0o755); err != nil {
+		t.Fatalf("mkdir target: %v", err)
+	}
+
+	match, ok := matchCleanableDir(target)
+	if !ok {
+		t.Fatalf("expected Cargo target to match")
+	}
+	if match.Confidence != confidenceHigh {
+		t.Errorf("confidence = %v, want high", match.Confidence)
+	}
+	if match.Reason == "" {
+		t.Errorf("expected a reason string")
+	}
+}
+
+func TestCleanableMemoClearedByScan(t *testing.T) {
+	base := t.TempDir()
+	marker := filepath.Join(base, "Cargo.toml")
+	if err := os.WriteFile(marker, nil, 0o644); err != nil {
+		t.Fatalf("write Cargo.toml: %v", err)
+	}
+	target := filepath.Join(base, "target")
+	if err := os.Mkdir(target, 0o755); err != nil {
+		t.Fatalf("mkdir target: %v", err)
+	}
+	if !isCleanableDir(target) {
+		t.Fatal("expected Cargo target to match")
+	}
+
+	if err := os.Remove(marker); err != nil {
+		t.Fatalf("remove Cargo.toml: %v", err)
+	}
+	m := newModel(base, false)
+	m.Update(scanResultMsg{path: base, result: scanResult{Root: base}})
+	if isCleanableDir(target) {
+		t.Fatal("expected a rescan to notice the project marker is gone")
+	}
+}
+
 func TestLoadCacheExpiresWhenDirectoryChanges(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -641,40 +732,3 @@ func TestScanPathPermissionError(t *testing.T) {
 		t.Logf("unexpected error type: %v", err)
 	}
 }
-
-func TestCalculateDirSizeFastHighFanoutCompletes(t *testing.T) {
-	root := t.TempDir()
-
-	// Reproduce high fan-out nested directory pattern that previously risked semaphore deadlock.
-	const fanout = 256
-	for i := 0; i < fanout; i++ {
-		nested := filepath.Join(root, fmt.Sprintf("dir-%03d", i), "nested")
-		if err := os.MkdirAll(nested, 0o755); err != nil {
-			t.Fatalf("create nested dir: %v", err)
-		}
-		if err := os.WriteFile(filepath.Join(nested, "data.bin"), []byte("x"), 0o644); err != nil {
-			t.Fatalf("write nested file: %v", err)
-		}
-	}
-
-	var files, dirs, bytes int64
-	current := &atomic.Value{}
-	current.Store("")
-
-	done := make(chan int64, 1)
-	go func() {
-		done <- calculateDirSizeFast(root, &files, &dirs, &bytes, current)
-	}()
-
-	select {
-	case total := <-done:
-		if total <= 0 {
-			t.Fatalf("expected positive total size, got %d", total)
-		}
-		if got := atomic.LoadInt64(&files); got < fanout {
-			t.Fatalf("expected at least %d files scanned, got %d", fanout, got)
-		}
-	case <-time.After(5 * time.Second):
-		t.Fatalf("calculateDirSizeFast did not complete under high fan-out")
-	}
-}
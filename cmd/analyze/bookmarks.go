@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const bookmarksFile = "bookmarks.json"
+
+// bookmark is a named folder kept in ~/.config/mole/bookmarks.json.
+type bookmark struct {
+	Name  string    `json:"name"`
+	Path  string    `json:"path"`
+	Added time.Time `json:"added"`
+}
+
+// bookmarkRow is a bookmark with its last known size for the picker.
+type bookmarkRow struct {
+	bookmark
+	Size    int64     // 0 when never measured
+	Updated time.Time // When Size was measured
+	Missing bool      // Folder no longer exists
+}
+
+func getBookmarksPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "mole", bookmarksFile), nil
+}
+
+func loadBookmarks() ([]bookmark, error) {
+	path, err := getBookmarksPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var marks []bookmark
+	if err := json.Unmarshal(data, &marks); err != nil {
+		return nil, err
+	}
+	return marks, nil
+}
+
+func saveBookmarks(marks []bookmark) error {
+	path, err := getBookmarksPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// putBookmark adds or renames the bookmark for path.
+func putBookmark(marks []bookmark, name, path string) []bookmark {
+	for i := range marks {
+		if marks[i].Path == path {
+			marks[i].Name = name
+			return marks
+		}
+	}
+	return append(marks, bookmark{Name: name, Path: path, Added: time.Now()})
+}
+
+func removeBookmark(marks []bookmark, path string) []bookmark {
+	kept := marks[:0]
+	for _, mark := range marks {
+		if mark.Path != path {
+			kept = append(kept, mark)
+		}
+	}
+	return kept
+}
+
+// bookmarkRows attaches cached sizes so the picker opens without scanning.
+func bookmarkRows(marks []bookmark) []bookmarkRow {
+	rows := make([]bookmarkRow, 0, len(marks))
+	for _, mark := range marks {
+		row := bookmarkRow{bookmark: mark, Missing: !isDir(mark.Path)}
+		if !row.Missing {
+			if size, updated, err := loadOverviewCachedSize(mark.Path); err == nil {
+				row.Size = size
+				row.Updated = updated
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// bookmarkTarget is the folder "*" would bookmark: the selected root on the overview,
+// the current folder elsewhere.
+func (m model) bookmarkTarget() string {
+	if m.inOverviewMode() {
+		if m.selected < len(m.entries) {
+			return m.entries[m.selected].Path
+		}
+		return ""
+	}
+	return m.path
+}
+
+// openBookmarkPrompt asks for a name for the current folder.
+func (m *model) openBookmarkPrompt() {
+	target := m.bookmarkTarget()
+	if target == "" {
+		return
+	}
+	m.bookmarkNaming = true
+	m.bookmarkName = filepath.Base(target)
+	if marks, err := loadBookmarks(); err == nil {
+		for _, mark := range marks {
+			if mark.Path == target {
+				m.bookmarkName = mark.Name
+			}
+		}
+	}
+}
+
+func (m model) updateBookmarkPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		name := strings.TrimSpace(m.bookmarkName)
+		target := m.bookmarkTarget()
+		m.bookmarkNaming = false
+		m.bookmarkName = ""
+		if name == "" || target == "" {
+			m.status = "Cancelled"
+			return m, nil
+		}
+		marks, err := loadBookmarks()
+		if err == nil {
+			err = saveBookmarks(putBookmark(marks, name, target))
+		}
+		if err != nil {
+			m.status = fmt.Sprintf("Bookmark failed: %v", err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Bookmarked %s as %s", displayPath(target), name)
+	case "esc":
+		m.bookmarkNaming = false
+		m.bookmarkName = ""
+		m.status = "Cancelled"
+	case "backspace":
+		if m.bookmarkName != "" {
+			runes := []rune(m.bookmarkName)
+			m.bookmarkName = string(runes[:len(runes)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.bookmarkName += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// openBookmarksView lists bookmarks with their cached sizes.
+func (m *model) openBookmarksView() {
+	marks, err := loadBookmarks()
+	if err != nil {
+		m.status = fmt.Sprintf("Cannot read bookmarks: %v", err)
+		return
+	}
+	if len(marks) == 0 {
+		m.status = fmt.Sprintf("No bookmarks yet, press %s to add one", m.hint(actionBookmark, ""))
+		return
+	}
+	m.bookmarkRows = bookmarkRows(marks)
+	m.showBookmarks = true
+	m.bookmarkSelected = 0
+}
+
+func (m model) updateBookmarksKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.keyAction(msg) == actionDelete {
+		if m.bookmarkSelected >= len(m.bookmarkRows) {
+			return m, nil
+		}
+		row := m.bookmarkRows[m.bookmarkSelected]
+		marks, err := loadBookmarks()
+		if err == nil {
+			err = saveBookmarks(removeBookmark(marks, row.Path))
+		}
+		if err != nil {
+			m.status = fmt.Sprintf("Cannot update bookmarks: %v", err)
+			return m, nil
+		}
+		m.bookmarkRows = slices.Delete(m.bookmarkRows, m.bookmarkSelected, m.bookmarkSelected+1)
+		m.bookmarkSelected = min(m.bookmarkSelected, max(len(m.bookmarkRows)-1, 0))
+		m.status = fmt.Sprintf("Removed bookmark %s", row.Name)
+		if len(m.bookmarkRows) == 0 {
+			m.showBookmarks = false
+		}
+		return m, nil
+	}
+
+	if m.closesView(msg, actionBookmarks) {
+		m.showBookmarks = false
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k", "K":
+		if m.bookmarkSelected > 0 {
+			m.bookmarkSelected--
+		}
+	case "down", "j", "J":
+		if m.bookmarkSelected < len(m.bookmarkRows)-1 {
+			m.bookmarkSelected++
+		}
+	case "enter", "right", "l", "L":
+		if m.bookmarkSelected >= len(m.bookmarkRows) {
+			return m, nil
+		}
+		row := m.bookmarkRows[m.bookmarkSelected]
+		if row.Missing {
+			m.status = fmt.Sprintf("%s no longer exists", displayPath(row.Path))
+			return m, nil
+		}
+		m.showBookmarks = false
+		m.showLargeFiles = false
+		if row.Path == m.path && !m.inOverviewMode() {
+			return m, nil
+		}
+		return m.openDir(row.Path)
+	}
+	return m, nil
+}
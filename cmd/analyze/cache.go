@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/cespare/xxhash/v2"
+
+	"github.com/tw93/mole/pkg/scan"
 )
 
 type overviewSizeSnapshot struct {
@@ -26,12 +28,15 @@ var (
 )
 
 func snapshotFromModel(m model) historyEntry {
+	entries, totalSize := m.unfilteredEntries()
 	return historyEntry{
 		Path:          m.path,
-		Entries:       slices.Clone(m.entries),
-		LargeFiles:    slices.Clone(m.largeFiles),
-		TotalSize:     m.totalSize,
+		Entries:       entries,
+		LargeFiles:    slices.Clone(m.unfilteredLargeFiles()),
+		TotalSize:     totalSize,
 		TotalFiles:    m.totalFiles,
+		Issues:        m.scanIssues,
+		Owners:        m.scanOwners,
 		Selected:      m.selected,
 		EntryOffset:   m.offset,
 		LargeSelected: m.largeSelected,
@@ -90,27 +95,41 @@ func getOverviewSizeStorePath() (string, error) {
 }
 
 func loadStoredOverviewSize(path string) (int64, error) {
+	snapshot, err := loadStoredOverviewSnapshot(path)
+	if err != nil {
+		return 0, err
+	}
+	return snapshot.Size, nil
+}
+
+func loadStoredOverviewSnapshot(path string) (overviewSizeSnapshot, error) {
 	if path == "" {
-		return 0, fmt.Errorf("empty path")
+		return overviewSizeSnapshot{}, fmt.Errorf("empty path")
 	}
 	overviewSnapshotMu.Lock()
 	defer overviewSnapshotMu.Unlock()
 	if err := ensureOverviewSnapshotCacheLocked(); err != nil {
-		return 0, err
+		return overviewSizeSnapshot{}, err
 	}
 	if overviewSnapshotCache == nil {
-		return 0, fmt.Errorf("snapshot cache unavailable")
+		return overviewSizeSnapshot{}, fmt.Errorf("snapshot cache unavailable")
 	}
 	if snapshot, ok := overviewSnapshotCache[path]; ok && snapshot.Size > 0 {
 		if time.Since(snapshot.Updated) < overviewCacheTTL {
-			return snapshot.Size, nil
+			return snapshot, nil
 		}
-		return 0, fmt.Errorf("snapshot expired")
+		return overviewSizeSnapshot{}, fmt.Errorf("snapshot expired")
 	}
-	return 0, fmt.Errorf("snapshot not found")
+	return overviewSizeSnapshot{}, fmt.Errorf("snapshot not found")
 }
 
 func storeOverviewSize(path string, size int64) error {
+	return storeOverviewSizeAt(path, size, time.Now())
+}
+
+// storeOverviewSizeAt records size as measured at updated, so sizes taken from an
+// older scan cache keep their real age.
+func storeOverviewSizeAt(path string, size int64, updated time.Time) error {
 	if path == "" || size <= 0 {
 		return fmt.Errorf("invalid overview size")
 	}
@@ -124,7 +143,7 @@ func storeOverviewSize(path string, size int64) error {
 	}
 	overviewSnapshotCache[path] = overviewSizeSnapshot{
 		Size:    size,
-		Updated: time.Now(),
+		Updated: updated,
 	}
 	return persistOverviewSnapshotLocked()
 }
@@ -145,19 +164,20 @@ func persistOverviewSnapshotLocked() error {
 	return os.Rename(tmpPath, storePath)
 }
 
-func loadOverviewCachedSize(path string) (int64, error) {
+// loadOverviewCachedSize returns a folder's last known size and when it was measured.
+func loadOverviewCachedSize(path string) (int64, time.Time, error) {
 	if path == "" {
-		return 0, fmt.Errorf("empty path")
+		return 0, time.Time{}, fmt.Errorf("empty path")
 	}
-	if snapshot, err := loadStoredOverviewSize(path); err == nil {
-		return snapshot, nil
+	if snapshot, err := loadStoredOverviewSnapshot(path); err == nil {
+		return snapshot.Size, snapshot.Updated, nil
 	}
 	cacheEntry, err := loadCacheFromDisk(path)
 	if err != nil {
-		return 0, err
+		return 0, time.Time{}, err
 	}
-	_ = storeOverviewSize(path, cacheEntry.TotalSize)
-	return cacheEntry.TotalSize, nil
+	_ = storeOverviewSizeAt(path, cacheEntry.TotalSize, cacheEntry.ScanTime)
+	return cacheEntry.TotalSize, cacheEntry.ScanTime, nil
 }
 
 func getCacheDir() (string, error) {
@@ -268,6 +288,8 @@ func saveCacheToDisk(path string, result scanResult) error {
 		LargeFiles: result.LargeFiles,
 		TotalSize:  result.TotalSize,
 		TotalFiles: result.TotalFiles,
+		Issues:     result.Issues,
+		Owners:     result.Owners,
 		ModTime:    info.ModTime(),
 		ScanTime:   time.Now(),
 	}
@@ -332,6 +354,7 @@ func removeOverviewSnapshot(path string) {
 }
 
 // prefetchOverviewCache warms overview cache in background.
+// It always runs under scan.LowImpact so the first screen stays responsive.
 func prefetchOverviewCache(ctx context.Context) {
 	entries := createOverviewEntries()
 
@@ -354,7 +377,7 @@ func prefetchOverviewCache(ctx context.Context) {
 		default:
 		}
 
-		size, err := measureOverviewSize(path)
+		size, err := measureOverviewSizeWithProfile(path, scan.LowImpact)
 		if err == nil && size > 0 {
 			_ = storeOverviewSize(path, size)
 		}
@@ -1,28 +1,206 @@
 package main
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
+// cleanableConfidence ranks how sure a rule is that a directory is a
+// regenerable artifact rather than committed source.
+type cleanableConfidence int
+
+const (
+	confidenceLow cleanableConfidence = iota + 1
+	confidenceMedium
+	confidenceHigh
+)
+
+func (c cleanableConfidence) String() string {
+	switch c {
+	case confidenceHigh:
+		return "high"
+	case confidenceMedium:
+		return "medium"
+	case confidenceLow:
+		return "low"
+	default:
+		return "unknown"
+	}
+}
+
+// cleanableRule matches a directory by name plus project context.
+// Siblings are checked in the parent directory, Inside within the directory itself.
+// A rule with no markers is self-identifying (the name alone is unambiguous).
+type cleanableRule struct {
+	Siblings   []string
+	Inside     []string
+	Exclude    []string // Sibling markers that veto the match.
+	Confidence cleanableConfidence
+	Reason     string
+}
+
+// cleanableMatch is the outcome of a successful rule check.
+type cleanableMatch struct {
+	Confidence cleanableConfidence
+	Reason     string
+}
+
+var (
+	jsMarkers     = []string{"package.json"}
+	pythonMarkers = []string{"pyproject.toml", "setup.py", "setup.cfg", "requirements.txt", "Pipfile", "tox.ini"}
+	gradleMarkers = []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}
+)
+
+// Project dependency and build directories, keyed by base name.
+// Rules are tried in order; the first one whose markers are present wins.
+var projectDependencyDirs = map[string][]cleanableRule{
+	// JavaScript/Node.
+	"node_modules":     {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "npm dependencies"}},
+	"bower_components": {{Siblings: []string{"bower.json", ".bowerrc"}, Confidence: confidenceHigh, Reason: "Bower dependencies"}},
+	".yarn":            {{Siblings: []string{"yarn.lock"}, Confidence: confidenceLow, Reason: "Yarn cache, may be committed for zero-installs"}},
+	".pnpm-store":      {{Confidence: confidenceMedium, Reason: "pnpm store"}},
+
+	// Python.
+	"venv":               {{Inside: []string{"pyvenv.cfg"}, Confidence: confidenceHigh, Reason: "Python virtualenv"}},
+	".venv":              {{Inside: []string{"pyvenv.cfg"}, Confidence: confidenceHigh, Reason: "Python virtualenv"}},
+	"virtualenv":         {{Inside: []string{"pyvenv.cfg"}, Confidence: confidenceHigh, Reason: "Python virtualenv"}},
+	"__pycache__":        {{Confidence: confidenceHigh, Reason: "Python bytecode cache"}},
+	".pytest_cache":      {{Confidence: confidenceHigh, Reason: "pytest cache"}},
+	".mypy_cache":        {{Confidence: confidenceHigh, Reason: "mypy cache"}},
+	".ruff_cache":        {{Confidence: confidenceHigh, Reason: "Ruff cache"}},
+	".tox":               {{Siblings: pythonMarkers, Confidence: confidenceHigh, Reason: "tox environments"}},
+	".eggs":              {{Siblings: []string{"setup.py", "setup.cfg"}, Confidence: confidenceHigh, Reason: "setuptools eggs"}},
+	"htmlcov":            {{Siblings: append([]string{".coverage", ".coveragerc"}, pythonMarkers...), Confidence: confidenceMedium, Reason: "coverage.py HTML report"}},
+	".ipynb_checkpoints": {{Confidence: confidenceHigh, Reason: "Jupyter checkpoints"}},
+
+	// Ruby and PHP. Go vendor trees are committed on purpose, so go.mod vetoes.
+	"vendor": {
+		{Siblings: []string{"composer.json"}, Exclude: []string{"go.mod"}, Confidence: confidenceMedium, Reason: "Composer dependencies"},
+		{Siblings: []string{"Gemfile"}, Inside: []string{"bundle"}, Exclude: []string{"go.mod"}, Confidence: confidenceMedium, Reason: "Bundler dependencies"},
+	},
+	".bundle": {{Siblings: []string{"Gemfile"}, Confidence: confidenceMedium, Reason: "Bundler config and gems"}},
+
+	// Java/Kotlin/Scala.
+	".gradle": {{Siblings: gradleMarkers, Confidence: confidenceHigh, Reason: "Gradle project cache"}},
+	"out": {
+		{Siblings: gradleMarkers, Confidence: confidenceMedium, Reason: "Gradle/IntelliJ output"},
+		{Siblings: []string{".idea"}, Confidence: confidenceLow, Reason: "IntelliJ output"},
+	},
+
+	// Build outputs.
+	"build": {
+		{Siblings: gradleMarkers, Confidence: confidenceHigh, Reason: "Gradle build output"},
+		{Siblings: []string{"CMakeLists.txt"}, Confidence: confidenceMedium, Reason: "CMake build tree"},
+		{Siblings: jsMarkers, Confidence: confidenceMedium, Reason: "JS build output"},
+		{Siblings: pythonMarkers, Confidence: confidenceMedium, Reason: "Python build output"},
+	},
+	"dist": {
+		{Siblings: jsMarkers, Confidence: confidenceMedium, Reason: "JS bundle output"},
+		{Siblings: pythonMarkers, Confidence: confidenceMedium, Reason: "Python distributions"},
+	},
+	"target": {
+		{Siblings: []string{"Cargo.toml"}, Confidence: confidenceHigh, Reason: "Rust build output"},
+		{Siblings: []string{"pom.xml"}, Confidence: confidenceHigh, Reason: "Maven build output"},
+		{Siblings: []string{"build.sbt"}, Confidence: confidenceHigh, Reason: "sbt build output"},
+	},
+	".next":         {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "Next.js build cache"}},
+	".nuxt":         {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "Nuxt build cache"}},
+	".output":       {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "Nitro/Nuxt output"}},
+	".parcel-cache": {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "Parcel cache"}},
+	".turbo":        {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "Turborepo cache"}},
+	".vite":         {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "Vite cache"}},
+	".nx":           {{Siblings: []string{"nx.json", "package.json"}, Confidence: confidenceHigh, Reason: "Nx cache"}},
+	"coverage":      {{Siblings: jsMarkers, Confidence: confidenceMedium, Reason: "coverage report"}},
+	".nyc_output":   {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "nyc coverage data"}},
+
+	// Frontend framework outputs.
+	".angular":    {{Siblings: []string{"angular.json", "package.json"}, Confidence: confidenceHigh, Reason: "Angular cache"}},
+	".svelte-kit": {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "SvelteKit output"}},
+	".astro":      {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "Astro cache"}},
+	".docusaurus": {{Siblings: jsMarkers, Confidence: confidenceHigh, Reason: "Docusaurus cache"}},
+
+	// Apple dev.
+	"DerivedData": {
+		{Siblings: []string{"*.xcodeproj", "*.xcworkspace"}, Confidence: confidenceHigh, Reason: "Xcode build data"},
+		{Inside: []string{"info.plist", "ModuleCache.noindex"}, Confidence: confidenceHigh, Reason: "Xcode build data"},
+	},
+	"Pods":       {{Siblings: []string{"Podfile"}, Confidence: confidenceHigh, Reason: "CocoaPods dependencies"}},
+	".build":     {{Siblings: []string{"Package.swift"}, Confidence: confidenceHigh, Reason: "SwiftPM build output"}},
+	"Carthage":   {{Siblings: []string{"Cartfile"}, Confidence: confidenceMedium, Reason: "Carthage dependencies"}},
+	".dart_tool": {{Siblings: []string{"pubspec.yaml"}, Confidence: confidenceHigh, Reason: "Dart tool cache"}},
+
+	// Other tools.
+	".terraform": {{Siblings: []string{"*.tf"}, Confidence: confidenceHigh, Reason: "Terraform providers"}},
+}
+
+// cleanableMemo caches rule results by path; View calls this for every visible row.
+// It is cleared whenever a scan result arrives, so it holds one listing at a time
+// and sees markers added or removed since.
+var cleanableMemo sync.Map
+
 // isCleanableDir marks paths safe to delete manually (not handled by mo clean).
 func isCleanableDir(path string) bool {
+	_, ok := matchCleanableDir(path)
+	return ok
+}
+
+// matchCleanableDir checks a directory against projectDependencyDirs using
+// its project context, so committed trees (e.g. Go vendor/) are not flagged.
+func matchCleanableDir(path string) (cleanableMatch, bool) {
 	if path == "" {
-		return false
+		return cleanableMatch{}, false
 	}
 
 	// Exclude paths mo clean already handles.
 	if isHandledByMoClean(path) {
-		return false
+		return cleanableMatch{}, false
+	}
+
+	rules, ok := projectDependencyDirs[filepath.Base(path)]
+	if !ok {
+		return cleanableMatch{}, false
 	}
 
-	baseName := filepath.Base(path)
+	if cached, ok := cleanableMemo.Load(path); ok {
+		match := cached.(cleanableMatch)
+		return match, match.Confidence > 0
+	}
 
-	// Project dependencies and build outputs are safe.
-	if projectDependencyDirs[baseName] {
-		return true
+	var match cleanableMatch
+	parent := filepath.Dir(path)
+	for _, rule := range rules {
+		if hasAnyMarker(parent, rule.Exclude) {
+			continue
+		}
+		if len(rule.Siblings) > 0 && !hasAnyMarker(parent, rule.Siblings) {
+			continue
+		}
+		if len(rule.Inside) > 0 && !hasAnyMarker(path, rule.Inside) {
+			continue
+		}
+		match = cleanableMatch{Confidence: rule.Confidence, Reason: rule.Reason}
+		break
 	}
 
+	cleanableMemo.Store(path, match)
+	return match, match.Confidence > 0
+}
+
+// hasAnyMarker reports whether dir contains one of the markers (glob patterns allowed).
+func hasAnyMarker(dir string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.ContainsAny(marker, "*?[") {
+			if found, _ := filepath.Glob(filepath.Join(dir, marker)); len(found) > 0 {
+				return true
+			}
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
 	return false
 }
 
@@ -44,64 +222,3 @@ func isHandledByMoClean(path string) bool {
 
 	return false
 }
-
-// Project dependency and build directories.
-var projectDependencyDirs = map[string]bool{
-	// JavaScript/Node.
-	"node_modules":     true,
-	"bower_components": true,
-	".yarn":            true,
-	".pnpm-store":      true,
-
-	// Python.
-	"venv":               true,
-	".venv":              true,
-	"virtualenv":         true,
-	"__pycache__":        true,
-	".pytest_cache":      true,
-	".mypy_cache":        true,
-	".ruff_cache":        true,
-	".tox":               true,
-	".eggs":              true,
-	"htmlcov":            true,
-	".ipynb_checkpoints": true,
-
-	// Ruby.
-	"vendor":  true,
-	".bundle": true,
-
-	// Java/Kotlin/Scala.
-	".gradle": true,
-	"out":     true,
-
-	// Build outputs.
-	"build":         true,
-	"dist":          true,
-	"target":        true,
-	".next":         true,
-	".nuxt":         true,
-	".output":       true,
-	".parcel-cache": true,
-	".turbo":        true,
-	".vite":         true,
-	".nx":           true,
-	"coverage":      true,
-	".coverage":     true,
-	".nyc_output":   true,
-
-	// Frontend framework outputs.
-	".angular":    true,
-	".svelte-kit": true,
-	".astro":      true,
-	".docusaurus": true,
-
-	// Apple dev.
-	"DerivedData": true,
-	"Pods":        true,
-	".build":      true,
-	"Carthage":    true,
-	".dart_tool":  true,
-
-	// Other tools.
-	".terraform": true,
-}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// compareStatus classifies a merged entry in compare mode.
+type compareStatus int
+
+const (
+	compareSame compareStatus = iota
+	compareDiffer
+	compareOnlyA
+	compareOnlyB
+)
+
+// compareEntry is one name present on either side of a comparison.
+type compareEntry struct {
+	Name   string
+	PathA  string // Empty when the entry only exists in B.
+	PathB  string // Empty when the entry only exists in A.
+	SizeA  int64
+	SizeB  int64
+	IsDir  bool
+	Status compareStatus
+}
+
+// delta is how much bigger B is than A.
+func (e compareEntry) delta() int64 {
+	return e.SizeB - e.SizeA
+}
+
+// compareLevel is one step of the compare navigation stack, like historyEntry.
+type compareLevel struct {
+	PathA    string
+	PathB    string
+	Entries  []compareEntry
+	TotalA   int64
+	TotalB   int64
+	Selected int
+	Offset   int
+}
+
+type compareResultMsg struct {
+	pathA string
+	pathB string
+	level compareLevel
+	errA  error
+	errB  error
+}
+
+func absDelta(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// mergeCompareEntries joins both listings by name, biggest differences first.
+func mergeCompareEntries(a, b []dirEntry) []compareEntry {
+	byName := make(map[string]*compareEntry, len(a)+len(b))
+	var order []string
+	for _, e := range a {
+		byName[e.Name] = &compareEntry{Name: e.Name, PathA: e.Path, SizeA: e.Size, IsDir: e.IsDir, Status: compareOnlyA}
+		order = append(order, e.Name)
+	}
+	for _, e := range b {
+		if existing, ok := byName[e.Name]; ok {
+			existing.PathB = e.Path
+			existing.SizeB = e.Size
+			existing.IsDir = existing.IsDir || e.IsDir
+			if existing.SizeA == e.Size {
+				existing.Status = compareSame
+			} else {
+				existing.Status = compareDiffer
+			}
+			continue
+		}
+		byName[e.Name] = &compareEntry{Name: e.Name, PathB: e.Path, SizeB: e.Size, IsDir: e.IsDir, Status: compareOnlyB}
+		order = append(order, e.Name)
+	}
+
+	merged := make([]compareEntry, 0, len(order))
+	for _, name := range order {
+		merged = append(merged, *byName[name])
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		di, dj := absDelta(merged[i].delta()), absDelta(merged[j].delta())
+		if di != dj {
+			return di > dj
+		}
+		return max(merged[i].SizeA, merged[i].SizeB) > max(merged[j].SizeA, merged[j].SizeB)
+	})
+	return merged
+}
+
+// compareCounts tallies entries by status for the header.
+func compareCounts(entries []compareEntry) (onlyA, onlyB, differ int) {
+	for _, e := range entries {
+		switch e.Status {
+		case compareOnlyA:
+			onlyA++
+		case compareOnlyB:
+			onlyB++
+		case compareDiffer:
+			differ++
+		}
+	}
+	return onlyA, onlyB, differ
+}
+
+// scanCompareSide lists every entry of one side; an empty path means the side is missing.
+func scanCompareSide(path string, profile scanProfile, filesScanned, dirsScanned, bytesScanned *int64, currentPath *atomic.Value) (scanResult, error) {
+	if path == "" {
+		return scanResult{}, nil
+	}
+	return scanPathWithOptions(path, scanOptions{SkipLargeFiles: true, Profile: profile}, filesScanned, dirsScanned, bytesScanned, currentPath)
+}
+
+func scanCompareCmd(pathA, pathB string, profile scanProfile, filesScanned, dirsScanned, bytesScanned *int64, currentPath *atomic.Value) tea.Cmd {
+	return func() tea.Msg {
+		var resultA, resultB scanResult
+		var errA, errB error
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			resultA, errA = scanCompareSide(pathA, profile, filesScanned, dirsScanned, bytesScanned, currentPath)
+		}()
+		go func() {
+			defer wg.Done()
+			resultB, errB = scanCompareSide(pathB, profile, filesScanned, dirsScanned, bytesScanned, currentPath)
+		}()
+		wg.Wait()
+
+		return compareResultMsg{
+			pathA: pathA,
+			pathB: pathB,
+			level: compareLevel{
+				PathA:   pathA,
+				PathB:   pathB,
+				Entries: mergeCompareEntries(resultA.Entries, resultB.Entries),
+				TotalA:  resultA.TotalSize,
+				TotalB:  resultB.TotalSize,
+			},
+			errA: errA,
+			errB: errB,
+		}
+	}
+}
+
+// startCompareScan scans both sides of the current level; the result replaces compareLevel.
+func (m *model) startCompareScan(pathA, pathB string) tea.Cmd {
+	m.compareScanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	m.status = "Comparing..."
+	return tea.Batch(scanCompareCmd(pathA, pathB, m.scanProfile, m.filesScanned, m.dirsScanned, m.bytesScanned, m.currentPath), tickCmd())
+}
+
+func (m model) handleCompareResult(msg compareResultMsg) (tea.Model, tea.Cmd) {
+	if !m.compareMode || msg.pathA != m.compareLevel.PathA || msg.pathB != m.compareLevel.PathB {
+		return m, nil
+	}
+	m.compareScanning = false
+	if msg.errA != nil && msg.errB != nil {
+		m.status = fmt.Sprintf("Compare failed: %v", msg.errA)
+		return m, nil
+	}
+	level := msg.level
+	level.Selected = min(m.compareLevel.Selected, max(len(level.Entries)-1, 0))
+	level.Offset = min(m.compareLevel.Offset, level.Selected)
+	m.compareLevel = level
+	m.updateCompareStatus()
+	if msg.errA != nil {
+		m.status = fmt.Sprintf("Cannot read A: %v", msg.errA)
+	} else if msg.errB != nil {
+		m.status = fmt.Sprintf("Cannot read B: %v", msg.errB)
+	}
+	return m, nil
+}
+
+func (m *model) updateCompareStatus() {
+	onlyA, onlyB, differ := compareCounts(m.compareLevel.Entries)
+	m.status = fmt.Sprintf("%d only in A, %d only in B, %d differ", onlyA, onlyB, differ)
+}
+
+// visibleCompareEntries applies the differences-only filter.
+func (m model) visibleCompareEntries() []compareEntry {
+	if !m.compareDiffOnly {
+		return m.compareLevel.Entries
+	}
+	var entries []compareEntry
+	for _, e := range m.compareLevel.Entries {
+		if e.Status != compareSame {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func (m model) updateCompareKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c", "Q":
+		return m, tea.Quit
+	}
+	if m.compareScanning {
+		return m, nil
+	}
+
+	entries := m.visibleCompareEntries()
+	level := &m.compareLevel
+	switch msg.String() {
+	case "up", "k", "K":
+		if level.Selected > 0 {
+			level.Selected--
+			if level.Selected < level.Offset {
+				level.Offset = level.Selected
+			}
+		}
+	case "down", "j", "J":
+		if level.Selected < len(entries)-1 {
+			level.Selected++
+			viewport := calculateViewport(m.height)
+			if level.Selected >= level.Offset+viewport {
+				level.Offset = level.Selected - viewport + 1
+			}
+		}
+	case "enter", "right", "l", "L":
+		if level.Selected >= len(entries) {
+			return m, nil
+		}
+		entry := entries[level.Selected]
+		if !entry.IsDir || strings.HasSuffix(entry.Name, " →") {
+			return m, nil
+		}
+		m.compareHistory = append(m.compareHistory, m.compareLevel)
+		m.compareLevel = compareLevel{PathA: entry.PathA, PathB: entry.PathB}
+		return m, m.startCompareScan(entry.PathA, entry.PathB)
+	case "b", "left", "h", "B", "H", "esc":
+		if len(m.compareHistory) == 0 {
+			return m, nil
+		}
+		m.compareLevel = m.compareHistory[len(m.compareHistory)-1]
+		m.compareHistory = m.compareHistory[:len(m.compareHistory)-1]
+		m.updateCompareStatus()
+	case "r", "R":
+		return m, m.startCompareScan(level.PathA, level.PathB)
+	case "d", "D":
+		m.compareDiffOnly = !m.compareDiffOnly
+		level.Selected = 0
+		level.Offset = 0
+	}
+	return m, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+func TestMergeCompareEntries(t *testing.T) {
+	a := []dirEntry{
+		{Name: "same", Path: "/a/same", Size: 100, IsDir: true},
+		{Name: "grown", Path: "/a/grown", Size: 100, IsDir: true},
+		{Name: "gone", Path: "/a/gone", Size: 50},
+	}
+	b := []dirEntry{
+		{Name: "same", Path: "/b/same", Size: 100, IsDir: true},
+		{Name: "grown", Path: "/b/grown", Size: 1100, IsDir: true},
+		{Name: "new", Path: "/b/new", Size: 10},
+	}
+
+	merged := mergeCompareEntries(a, b)
+	if len(merged) != 4 {
+		t.Fatalf("expected 4 merged entries, got %d", len(merged))
+	}
+
+	want := []struct {
+		name   string
+		status compareStatus
+		delta  int64
+	}{
+		{"grown", compareDiffer, 1000},
+		{"gone", compareOnlyA, -50},
+		{"new", compareOnlyB, 10},
+		{"same", compareSame, 0},
+	}
+	for i, w := range want {
+		got := merged[i]
+		if got.Name != w.name || got.Status != w.status || got.delta() != w.delta {
+			t.Errorf("entry %d: got %s status=%d delta=%d, want %s status=%d delta=%d",
+				i, got.Name, got.Status, got.delta(), w.name, w.status, w.delta)
+		}
+	}
+	if merged[1].PathB != "" || merged[2].PathA != "" {
+		t.Fatalf("one-sided entries should leave the other path empty: %+v %+v", merged[1], merged[2])
+	}
+
+	onlyA, onlyB, differ := compareCounts(merged)
+	if onlyA != 1 || onlyB != 1 || differ != 1 {
+		t.Fatalf("unexpected counts: onlyA=%d onlyB=%d differ=%d", onlyA, onlyB, differ)
+	}
+}
+
+func TestScanCompareCmdKeepsAllEntries(t *testing.T) {
+	base := t.TempDir()
+	sideA := filepath.Join(base, "a")
+	sideB := filepath.Join(base, "b")
+	for i := 0; i < maxEntries+5; i++ {
+		writeFileWithSize(t, filepath.Join(sideA, fmt.Sprintf("file-%02d", i)), 4096)
+		writeFileWithSize(t, filepath.Join(sideB, fmt.Sprintf("file-%02d", i)), 4096)
+	}
+	writeFileWithSize(t, filepath.Join(sideB, "extra", "blob"), 64*1024)
+
+	var files, dirs, bytes int64
+	msg := scanCompareCmd(sideA, sideB, scan.FullSpeed, &files, &dirs, &bytes, nil)().(compareResultMsg)
+	if msg.errA != nil || msg.errB != nil {
+		t.Fatalf("unexpected errors: %v / %v", msg.errA, msg.errB)
+	}
+	if got := len(msg.level.Entries); got != maxEntries+6 {
+		t.Fatalf("expected %d merged entries, got %d", maxEntries+6, got)
+	}
+	first := msg.level.Entries[0]
+	if first.Name != "extra" || first.Status != compareOnlyB {
+		t.Fatalf("expected extra (only in B) first, got %+v", first)
+	}
+	if msg.level.TotalB <= msg.level.TotalA {
+		t.Fatalf("expected B to be larger: A=%d B=%d", msg.level.TotalA, msg.level.TotalB)
+	}
+}
+
+func TestScanCompareSideMissing(t *testing.T) {
+	var files, dirs, bytes int64
+	result, err := scanCompareSide("", scan.FullSpeed, &files, &dirs, &bytes, nil)
+	if err != nil || len(result.Entries) != 0 || result.TotalSize != 0 {
+		t.Fatalf("expected empty result for missing side, got %+v err=%v", result, err)
+	}
+}
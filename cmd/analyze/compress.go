@@ -0,0 +1,411 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+const archiveExt = ".zip"
+
+// compressResult describes one archive-and-replace.
+type compressResult struct {
+	Source       string
+	Archive      string
+	OriginalSize int64
+	ArchiveSize  int64
+	Files        int64
+	Trashed      bool
+}
+
+type compressResultMsg struct {
+	results []compressResult
+	err     error
+}
+
+// savedBytes sums what the archives freed compared with the originals. It is
+// never negative, even if an archive's size could not be read back.
+func (msg compressResultMsg) savedBytes() int64 {
+	var saved int64
+	for _, r := range msg.results {
+		if r.Trashed {
+			saved += max(r.OriginalSize-r.ArchiveSize, 0)
+		}
+	}
+	return saved
+}
+
+// uniqueArchivePath returns dir/name.zip, adding a counter if it is taken.
+func uniqueArchivePath(dir, name string) string {
+	candidate := filepath.Join(dir, name+archiveExt)
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = filepath.Join(dir, fmt.Sprintf("%s-%d%s", name, i, archiveExt))
+	}
+}
+
+// zipStats counts what went into an archive.
+type zipStats struct {
+	Entries      int64 // Every header, directories included.
+	Files        int64
+	OriginalSize int64
+}
+
+// compressPathToZip writes src (file or directory) into a zip at archivePath.
+// Entries are stored relative to src's parent so the archive extracts to the original name.
+// The archive is written to a temporary name and renamed only when complete.
+func compressPathToZip(src, archivePath string, counter *int64) (stats zipStats, err error) {
+	tmpPath := archivePath + ".partial"
+	out, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return stats, err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	zw := zip.NewWriter(out)
+	base := filepath.Dir(src)
+
+	walkErr := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+
+		switch {
+		case d.IsDir():
+			header.Name += "/"
+			if _, err := zw.CreateHeader(header); err != nil {
+				return err
+			}
+			stats.Entries++
+			return nil
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			w, err := zw.CreateHeader(header)
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, target); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			header.Method = zip.Deflate
+			w, err := zw.CreateHeader(header)
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(w, f)
+			_ = f.Close()
+			if err != nil {
+				return err
+			}
+			stats.OriginalSize += scan.DiskUsage(info)
+		default:
+			// Sockets, pipes and devices cannot be restored from an archive.
+			return fmt.Errorf("cannot archive special file %s", displayPath(path))
+		}
+
+		stats.Entries++
+		stats.Files++
+		if counter != nil {
+			atomic.AddInt64(counter, 1)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		_ = zw.Close()
+		_ = out.Close()
+		return zipStats{}, walkErr
+	}
+	if err = zw.Close(); err != nil {
+		_ = out.Close()
+		return zipStats{}, err
+	}
+	if err = out.Sync(); err != nil {
+		_ = out.Close()
+		return zipStats{}, err
+	}
+	if err = out.Close(); err != nil {
+		return zipStats{}, err
+	}
+	if err = os.Rename(tmpPath, archivePath); err != nil {
+		return zipStats{}, err
+	}
+	return stats, nil
+}
+
+// verifyZipArchive re-reads every entry so CRC mismatches surface before the original is removed.
+func verifyZipArchive(archivePath string, expectedEntries int64) error {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer r.Close() //nolint:errcheck
+
+	if int64(len(r.File)) != expectedEntries {
+		return fmt.Errorf("verify: archive has %d entries, expected %d", len(r.File), expectedEntries)
+	}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("verify %s: %w", f.Name, err)
+		}
+		_, err = io.Copy(io.Discard, rc)
+		_ = rc.Close()
+		if err != nil {
+			return fmt.Errorf("verify %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// archiveAndReplace compresses src into destDir (or next to it), verifies the
+// archive and moves the original to Trash. The archive is kept even if trashing
+// fails, and dropped instead of the original when it saves nothing.
+func archiveAndReplace(src, destDir string, counter *int64) (compressResult, error) {
+	result := compressResult{Source: src}
+	if destDir == "" {
+		destDir = filepath.Dir(src)
+	}
+	if destDir == src || strings.HasPrefix(destDir, src+string(filepath.Separator)) {
+		return result, fmt.Errorf("destination %s is inside %s", displayPath(destDir), filepath.Base(src))
+	}
+
+	archivePath := uniqueArchivePath(destDir, filepath.Base(src))
+	stats, err := compressPathToZip(src, archivePath, counter)
+	if err != nil {
+		return result, fmt.Errorf("compress %s: %w", filepath.Base(src), err)
+	}
+	if err := verifyZipArchive(archivePath, stats.Entries); err != nil {
+		_ = os.Remove(archivePath)
+		return result, err
+	}
+	result.OriginalSize = stats.OriginalSize
+	result.Files = stats.Files
+
+	if info, err := os.Stat(archivePath); err == nil {
+		result.ArchiveSize = scan.DiskUsage(info)
+	}
+	if result.ArchiveSize >= result.OriginalSize {
+		_ = os.Remove(archivePath)
+		return result, fmt.Errorf("%s does not shrink when zipped (%s to %s), original kept",
+			filepath.Base(src), humanizeBytes(result.OriginalSize), humanizeBytes(result.ArchiveSize))
+	}
+	result.Archive = archivePath
+
+	logOperation("ARCHIVED", src, fmt.Sprintf("%s -> %s, %s", oplogSize(result.OriginalSize), oplogSize(result.ArchiveSize), displayPath(archivePath)))
+
+	if _, err := trashPathWithProgress(src, nil); err != nil {
+		return result, fmt.Errorf("archive written to %s but original kept: %w", displayPath(archivePath), err)
+	}
+	result.Trashed = true
+	return result, nil
+}
+
+// compressPathsCmd archives each path on its own and aggregates the results.
+func compressPathsCmd(paths []string, destDir string, counter *int64) tea.Cmd {
+	return func() tea.Msg {
+		var results []compressResult
+		var errors []string
+		for _, path := range paths {
+			result, err := archiveAndReplace(path, destDir, counter)
+			if result.Archive != "" {
+				results = append(results, result)
+			}
+			if err != nil {
+				errors = append(errors, err.Error())
+			}
+		}
+
+		var resultErr error
+		if len(errors) > 0 {
+			resultErr = &multiDeleteError{errors: errors}
+		}
+		return compressResultMsg{results: results, err: resultErr}
+	}
+}
+
+// selectedActionPaths returns the multi-selection, or the highlighted row, for the active list.
+func (m model) selectedActionPaths() []string {
+	var paths []string
+	if m.showLargeFiles {
+		for path := range m.largeMultiSelected {
+			paths = append(paths, path)
+		}
+		if len(paths) == 0 && m.largeSelected < len(m.largeFiles) {
+			paths = append(paths, m.largeFiles[m.largeSelected].Path)
+		}
+		return paths
+	}
+	for path := range m.multiSelected {
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 && m.selected < len(m.entries) {
+		paths = append(paths, m.entries[m.selected].Path)
+	}
+	return paths
+}
+
+// openCompressConfirm asks where to write archives for the current selection.
+func (m *model) openCompressConfirm() {
+	if m.inOverviewMode() {
+		return
+	}
+	if m.policy.readOnly {
+		m.status = "Read-only mode, compression is disabled"
+		return
+	}
+	paths := m.selectedActionPaths()
+	if len(paths) == 0 {
+		return
+	}
+	if m.virtual != nil && slices.ContainsFunc(paths, m.virtual.partial) {
+		// The archive would take files that were never listed.
+		m.status = "Only listed files can be compressed here, this folder holds others too"
+		return
+	}
+	if err := m.policy.checkAll(paths); err != nil {
+		m.status = err.Error()
+		return
+	}
+	m.compressConfirm = true
+	m.compressPaths = paths
+	m.compressDest = ""
+}
+
+// resolveDestDir expands the typed destination; empty means next to each original.
+func resolveDestDir(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(input, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		input = home + strings.TrimPrefix(input, "~")
+	}
+	abs, err := filepath.Abs(input)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", fmt.Errorf("destination %s: %w", displayPath(abs), err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("destination %s is not a folder", displayPath(abs))
+	}
+	return abs, nil
+}
+
+func (m model) updateCompressConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		dest, err := resolveDestDir(m.compressDest)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		paths := m.compressPaths
+		m.compressConfirm = false
+		m.compressPaths = nil
+		m.compressDest = ""
+		m.compressing = true
+		var compressCount int64
+		m.compressCount = &compressCount
+		if len(paths) == 1 {
+			m.status = fmt.Sprintf("Compressing %s...", filepath.Base(paths[0]))
+		} else {
+			m.status = fmt.Sprintf("Compressing %d items...", len(paths))
+		}
+		return m, tea.Batch(compressPathsCmd(paths, dest, m.compressCount), tickCmd())
+	case "esc":
+		m.compressConfirm = false
+		m.compressPaths = nil
+		m.compressDest = ""
+		m.status = "Cancelled"
+	case "backspace":
+		if m.compressDest != "" {
+			runes := []rune(m.compressDest)
+			m.compressDest = string(runes[:len(runes)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.compressDest += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// handleCompressResult reports the outcome and rescans when originals were replaced.
+func (m model) handleCompressResult(msg compressResultMsg) (tea.Model, tea.Cmd) {
+	m.compressing = false
+	m.multiSelected = make(map[string]bool)
+	m.largeMultiSelected = make(map[string]bool)
+
+	trashed := 0
+	for _, r := range msg.results {
+		if r.Trashed {
+			trashed++
+			invalidateCache(r.Source)
+		}
+	}
+
+	switch {
+	case msg.err != nil && trashed == 0:
+		m.status = fmt.Sprintf("Compression failed: %v", msg.err)
+	case msg.err != nil:
+		m.status = fmt.Sprintf("Compressed %d items, saved %s; some failed: %v", trashed, humanizeBytes(msg.savedBytes()), msg.err)
+	case len(msg.results) == 1:
+		r := msg.results[0]
+		m.status = fmt.Sprintf("Compressed %s to %s, saved %s", humanizeBytes(r.OriginalSize), humanizeBytes(r.ArchiveSize), humanizeBytes(msg.savedBytes()))
+	default:
+		m.status = fmt.Sprintf("Compressed %d items, saved %s", trashed, humanizeBytes(msg.savedBytes()))
+	}
+
+	if len(msg.results) == 0 {
+		return m, nil
+	}
+	return m, m.rescanAfterChange()
+}
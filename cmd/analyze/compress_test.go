@@ -0,0 +1,138 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCompressPathToZipRoundTrip(t *testing.T) {
+	base := t.TempDir()
+	src := filepath.Join(base, "old-project")
+	if err := os.MkdirAll(filepath.Join(src, "logs"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	content := strings.Repeat("log line that compresses well\n", 2000)
+	if err := os.WriteFile(filepath.Join(src, "logs", "app.log"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "README"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("write readme: %v", err)
+	}
+	if err := os.Symlink("README", filepath.Join(src, "link")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	var counter int64
+	archivePath := uniqueArchivePath(base, filepath.Base(src))
+	stats, err := compressPathToZip(src, archivePath, &counter)
+	if err != nil {
+		t.Fatalf("compressPathToZip: %v", err)
+	}
+	if stats.Files != 3 || counter != 3 {
+		t.Fatalf("expected 3 files archived, got stats=%d counter=%d", stats.Files, counter)
+	}
+	if stats.Entries != 5 {
+		t.Fatalf("expected 5 entries including directories, got %d", stats.Entries)
+	}
+	if err := verifyZipArchive(archivePath, stats.Entries); err != nil {
+		t.Fatalf("verifyZipArchive: %v", err)
+	}
+	if _, err := os.Stat(archivePath + ".partial"); !os.IsNotExist(err) {
+		t.Fatalf("expected temporary archive to be renamed, err=%v", err)
+	}
+
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer r.Close() //nolint:errcheck
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	want := []string{"old-project/", "old-project/README", "old-project/link", "old-project/logs/", "old-project/logs/app.log"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected archive entries: %v", names)
+	}
+}
+
+func TestUniqueArchivePath(t *testing.T) {
+	dir := t.TempDir()
+	first := uniqueArchivePath(dir, "logs")
+	if filepath.Base(first) != "logs.zip" {
+		t.Fatalf("expected logs.zip, got %s", first)
+	}
+	if err := os.WriteFile(first, nil, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if second := uniqueArchivePath(dir, "logs"); filepath.Base(second) != "logs-1.zip" {
+		t.Fatalf("expected logs-1.zip, got %s", second)
+	}
+}
+
+func TestVerifyZipArchiveDetectsMissingEntries(t *testing.T) {
+	base := t.TempDir()
+	src := filepath.Join(base, "data.txt")
+	if err := os.WriteFile(src, []byte("data"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	archivePath := filepath.Join(base, "data.txt.zip")
+	stats, err := compressPathToZip(src, archivePath, nil)
+	if err != nil {
+		t.Fatalf("compressPathToZip: %v", err)
+	}
+	if err := verifyZipArchive(archivePath, stats.Entries+1); err == nil {
+		t.Fatal("expected entry count mismatch to fail verification")
+	}
+}
+
+func TestArchiveAndReplaceRejectsDestinationInsideSource(t *testing.T) {
+	src := t.TempDir()
+	dest := filepath.Join(src, "archives")
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if _, err := archiveAndReplace(src, dest, nil); err == nil {
+		t.Fatal("expected destination inside source to be rejected")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Fatalf("source should be untouched: %v", err)
+	}
+}
+
+func TestArchiveAndReplaceKeepsOriginalWhenZipIsNotSmaller(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "photos")
+	if err := os.MkdirAll(src, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	// Random bytes do not compress, so the archive ends up larger.
+	noise := make([]byte, 64*1024)
+	if _, err := rand.Read(noise); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "raw.bin"), noise, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	result, err := archiveAndReplace(src, "", nil)
+	if err == nil || result.Trashed || result.Archive != "" {
+		t.Fatalf("expected the original to be kept, got %+v err=%v", result, err)
+	}
+	if _, err := os.Stat(filepath.Join(src, "raw.bin")); err != nil {
+		t.Fatalf("source should be untouched: %v", err)
+	}
+	if _, err := os.Stat(src + archiveExt); !os.IsNotExist(err) {
+		t.Fatalf("expected the archive to be removed, err=%v", err)
+	}
+
+	msg := compressResultMsg{results: []compressResult{{OriginalSize: 100, ArchiveSize: 4096, Trashed: true}}}
+	if saved := msg.savedBytes(); saved != 0 {
+		t.Fatalf("expected savings never to go negative, got %d", saved)
+	}
+}
@@ -3,248 +3,41 @@ package main
 import "time"
 
 const (
-	maxEntries             = 30
-	maxLargeFiles          = 20
-	barWidth               = 24
-	spotlightMinFileSize   = 100 << 20
-	largeFileWarmupMinSize = 1 << 20
-	defaultViewport        = 12
-	overviewCacheTTL       = 7 * 24 * time.Hour
-	overviewCacheFile      = "overview_sizes.json"
-	duTimeout              = 30 * time.Second
-	mdlsTimeout            = 5 * time.Second
-	maxConcurrentOverview  = 8
-	batchUpdateSize        = 100
-	cacheModTimeGrace      = 30 * time.Minute
-	cacheReuseWindow       = 24 * time.Hour
-	staleCacheTTL          = 3 * 24 * time.Hour
+	maxEntries            = 30
+	maxLargeFiles         = 20
+	barWidth              = 24
+	defaultLargeIndexMin  = 50 << 20
+	largeFileIndexFile    = "large_files.json"
+	defaultViewport       = 12
+	overviewCacheTTL      = 7 * 24 * time.Hour
+	overviewCacheFile     = "overview_sizes.json"
+	maxConcurrentOverview = 8
+	cacheModTimeGrace     = 30 * time.Minute
+	cacheReuseWindow      = 24 * time.Hour
+	staleCacheTTL         = 3 * 24 * time.Hour
+	reclaimStaleAge       = 7 * 24 * time.Hour // Matches MIN_AGE_DAYS in mo purge.
 
-	// Worker pool limits.
-	minWorkers         = 16
-	maxWorkers         = 64
-	cpuMultiplier      = 4
-	maxDirWorkers      = 32
 	openCommandTimeout = 10 * time.Second
-)
-
-var foldDirs = map[string]bool{
-	// VCS.
-	".git": true,
-	".svn": true,
-	".hg":  true,
-
-	// JavaScript/Node.
-	"node_modules":                  true,
-	".npm":                          true,
-	"_npx":                          true,
-	"_cacache":                      true,
-	"_logs":                         true,
-	"_locks":                        true,
-	"_quick":                        true,
-	"_libvips":                      true,
-	"_prebuilds":                    true,
-	"_update-notifier-last-checked": true,
-	".yarn":                         true,
-	".pnpm-store":                   true,
-	".next":                         true,
-	".nuxt":                         true,
-	"bower_components":              true,
-	".vite":                         true,
-	".turbo":                        true,
-	".parcel-cache":                 true,
-	".nx":                           true,
-	".rush":                         true,
-	"tnpm":                          true,
-	".tnpm":                         true,
-	".bun":                          true,
-	".deno":                         true,
-
-	// Python.
-	"__pycache__":   true,
-	".pytest_cache": true,
-	".mypy_cache":   true,
-	".ruff_cache":   true,
-	"venv":          true,
-	".venv":         true,
-	"virtualenv":    true,
-	".tox":          true,
-	"site-packages": true,
-	".eggs":         true,
-	"*.egg-info":    true,
-	".pyenv":        true,
-	".poetry":       true,
-	".pip":          true,
-	".pipx":         true,
-
-	// Ruby/Go/PHP (vendor), Java/Kotlin/Scala/Rust (target).
-	"vendor":        true,
-	".bundle":       true,
-	"gems":          true,
-	".rbenv":        true,
-	"target":        true,
-	".gradle":       true,
-	".m2":           true,
-	".ivy2":         true,
-	"out":           true,
-	"pkg":           true,
-	"composer.phar": true,
-	".composer":     true,
-	".cargo":        true,
-
-	// Build outputs.
-	"build":     true,
-	"dist":      true,
-	".output":   true,
-	"coverage":  true,
-	".coverage": true,
-
-	// IDE.
-	".idea":   true,
-	".vscode": true,
-	".vs":     true,
-	".fleet":  true,
-
-	// Cache directories.
-	".cache":                  true,
-	"__MACOSX":                true,
-	".DS_Store":               true,
-	".Trash":                  true,
-	"Caches":                  true,
-	".Spotlight-V100":         true,
-	".fseventsd":              true,
-	".DocumentRevisions-V100": true,
-	".TemporaryItems":         true,
-	"$RECYCLE.BIN":            true,
-	".temp":                   true,
-	".tmp":                    true,
-	"_temp":                   true,
-	"_tmp":                    true,
-	".Homebrew":               true,
-	".rustup":                 true,
-	".sdkman":                 true,
-	".nvm":                    true,
-
-	// macOS.
-	"Application Scripts":     true,
-	"Saved Application State": true,
-
-	// iCloud.
-	"Mobile Documents": true,
 
-	// Containers.
-	".docker":     true,
-	".containerd": true,
-
-	// Mobile development.
-	"Pods":        true,
-	"DerivedData": true,
-	".build":      true,
-	"xcuserdata":  true,
-	"Carthage":    true,
-	".dart_tool":  true,
-
-	// Web frameworks.
-	".angular":    true,
-	".svelte-kit": true,
-	".astro":      true,
-	".solid":      true,
-
-	// Databases.
-	".mysql":    true,
-	".postgres": true,
-	"mongodb":   true,
-
-	// Other.
-	".terraform": true,
-	".vagrant":   true,
-	"tmp":        true,
-	"temp":       true,
-}
-
-var skipSystemDirs = map[string]bool{
-	"dev":                     true,
-	"tmp":                     true,
-	"private":                 true,
-	"cores":                   true,
-	"net":                     true,
-	"home":                    true,
-	"System":                  true,
-	"sbin":                    true,
-	"bin":                     true,
-	"etc":                     true,
-	"var":                     true,
-	"opt":                     false,
-	"usr":                     false,
-	"Volumes":                 true,
-	"Network":                 true,
-	".vol":                    true,
-	".Spotlight-V100":         true,
-	".fseventsd":              true,
-	".DocumentRevisions-V100": true,
-	".TemporaryItems":         true,
-	".MobileBackups":          true,
-}
-
-var defaultSkipDirs = map[string]bool{
-	"nfs":         true,
-	"PHD":         true,
-	"Permissions": true,
-
-	// Virtualization/Container mounts (NFS, network filesystems).
-	"OrbStack":        true, // OrbStack NFS mounts
-	"Colima":          true, // Colima VM mounts
-	"Parallels":       true, // Parallels Desktop VMs
-	"VMware Fusion":   true, // VMware Fusion VMs
-	"VirtualBox VMs":  true, // VirtualBox VMs
-	"Rancher Desktop": true, // Rancher Desktop mounts
-	".lima":           true, // Lima VM mounts
-	".colima":         true, // Colima config/mounts
-	".orbstack":       true, // OrbStack config/mounts
-}
-
-var skipExtensions = map[string]bool{
-	".go":     true,
-	".js":     true,
-	".ts":     true,
-	".tsx":    true,
-	".jsx":    true,
-	".json":   true,
-	".md":     true,
-	".txt":    true,
-	".yml":    true,
-	".yaml":   true,
-	".xml":    true,
-	".html":   true,
-	".css":    true,
-	".scss":   true,
-	".sass":   true,
-	".less":   true,
-	".py":     true,
-	".rb":     true,
-	".java":   true,
-	".kt":     true,
-	".rs":     true,
-	".swift":  true,
-	".m":      true,
-	".mm":     true,
-	".c":      true,
-	".cpp":    true,
-	".h":      true,
-	".hpp":    true,
-	".cs":     true,
-	".sql":    true,
-	".db":     true,
-	".lock":   true,
-	".gradle": true,
-	".mjs":    true,
-	".cjs":    true,
-	".coffee": true,
-	".dart":   true,
-	".svelte": true,
-	".vue":    true,
-	".nim":    true,
-	".hx":     true,
-}
+	// Deletions at or above this size must be confirmed by typing typedConfirmWord.
+	typedConfirmThreshold = 10 << 30
+	typedConfirmWord      = "delete"
+
+	// Git repository insights.
+	gitTimeout      = 60 * time.Second
+	gitTopBlobs     = 10
+	gitBloatRatio   = 2
+	gitBloatMinSize = 100 << 20
+
+	// Preview pane.
+	previewMinWidth    = 32
+	previewMaxWidth    = 60
+	previewHeadBytes   = 4096
+	previewTextLines   = 20
+	previewMembers     = 10
+	previewTopChildren = 5
+	previewTarBudget   = 256 << 20 // Compressed bytes read to list a .tar.gz
+)
 
 var spinnerFrames = []string{"|", "/", "-", "\\", "|", "/", "-", "\\"}
 
@@ -3,36 +3,50 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
 )
 
 const trashTimeout = 30 * time.Second
 
-func deletePathCmd(path string, counter *int64) tea.Cmd {
+func deletePathCmd(path string, counter *int64, permanent bool) tea.Cmd {
 	return func() tea.Msg {
-		count, err := trashPathWithProgress(path, counter)
+		count, err := removeWithProgress(path, counter, permanent)
+		var removed, untrashed []string
+		switch {
+		case err == nil:
+			removed = []string{path}
+		case !permanent && pathExists(path):
+			untrashed = []string{path}
+		}
 		return deleteProgressMsg{
-			done:  true,
-			err:   err,
-			count: count,
-			path:  path,
+			done:      true,
+			err:       err,
+			count:     count,
+			path:      path,
+			removed:   removed,
+			untrashed: untrashed,
 		}
 	}
 }
 
-// deleteMultiplePathsCmd moves paths to Trash and aggregates results.
-func deleteMultiplePathsCmd(paths []string, counter *int64) tea.Cmd {
+// deleteMultiplePathsCmd moves paths to Trash (or removes them when permanent) and aggregates results.
+func deleteMultiplePathsCmd(paths []string, counter *int64, permanent bool) tea.Cmd {
 	return func() tea.Msg {
 		var totalCount int64
 		var errors []string
+		var removed, untrashed []string
 
 		// Process deeper paths first to avoid parent/child conflicts.
 		pathsToDelete := append([]string(nil), paths...)
@@ -41,14 +55,16 @@ func deleteMultiplePathsCmd(paths []string, counter *int64) tea.Cmd {
 		})
 
 		for _, path := range pathsToDelete {
-			count, err := trashPathWithProgress(path, counter)
+			count, err := removeWithProgress(path, counter, permanent)
 			totalCount += count
-			if err != nil {
-				if os.IsNotExist(err) {
-					continue
-				}
+			if err != nil && !os.IsNotExist(err) {
 				errors = append(errors, err.Error())
+				if !permanent && pathExists(path) {
+					untrashed = append(untrashed, path)
+				}
+				continue
 			}
+			removed = append(removed, path)
 		}
 
 		var resultErr error
@@ -57,10 +73,12 @@ func deleteMultiplePathsCmd(paths []string, counter *int64) tea.Cmd {
 		}
 
 		return deleteProgressMsg{
-			done:  true,
-			err:   resultErr,
-			count: totalCount,
-			path:  "",
+			done:      true,
+			err:       resultErr,
+			count:     totalCount,
+			path:      "",
+			removed:   removed,
+			untrashed: untrashed,
 		}
 	}
 }
@@ -77,6 +95,20 @@ func (e *multiDeleteError) Error() string {
 	return strings.Join(e.errors[:min(3, len(e.errors))], "; ")
 }
 
+// removeWithProgress trashes path, or deletes it outright when permanent is set.
+func removeWithProgress(path string, counter *int64, permanent bool) (int64, error) {
+	if permanent {
+		return removePathWithProgress(path, counter)
+	}
+	return trashPathWithProgress(path, counter)
+}
+
+// pathExists reports whether path is still on disk, broken symlinks included.
+func pathExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
 // trashPathWithProgress moves a path to Trash using Finder.
 // This allows users to recover accidentally deleted files.
 func trashPathWithProgress(root string, counter *int64) (int64, error) {
@@ -86,10 +118,50 @@ func trashPathWithProgress(root string, counter *int64) (int64, error) {
 		return 0, err
 	}
 
-	// Count items for progress reporting.
-	var count int64
+	count, size := countPathFiles(root, info, counter)
+
+	home, _ := os.UserHomeDir()
+	destination := "trash"
+	if trashDir := trashDirFor(root, home); trashDir != "" {
+		destination = "trash: " + displayPath(trashDir)
+	}
+
+	// Move to Trash using Finder AppleScript.
+	if err := moveToTrash(root); err != nil {
+		logDeleteResult(root, size, count, destination, err)
+		return 0, err
+	}
+
+	logDeleteResult(root, size, count, destination, nil)
+	return count, nil
+}
+
+// removePathWithProgress deletes a path without going through Trash.
+// Used for volumes that have no Trash or when the user explicitly asks for it.
+func removePathWithProgress(root string, counter *int64) (int64, error) {
+	info, err := os.Lstat(root)
+	if err != nil {
+		return 0, err
+	}
+
+	count, size := countPathFiles(root, info, counter)
+
+	if err := os.RemoveAll(root); err != nil {
+		err = fmt.Errorf("failed to delete %s: %w", filepath.Base(root), err)
+		logDeleteResult(root, size, count, "permanent", err)
+		return 0, err
+	}
+
+	logDeleteResult(root, size, count, "permanent", nil)
+	return count, nil
+}
+
+// countPathFiles counts files under root for progress reporting and sums
+// their on-disk size for the operations log.
+func countPathFiles(root string, info os.FileInfo, counter *int64) (int64, int64) {
+	var count, size int64
 	if info.IsDir() {
-		_ = filepath.WalkDir(root, func(_ string, d os.DirEntry, err error) error {
+		_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
 			if err != nil {
 				return nil
 			}
@@ -98,22 +170,20 @@ func trashPathWithProgress(root string, counter *int64) (int64, error) {
 				if counter != nil {
 					atomic.StoreInt64(counter, count)
 				}
+				if fileInfo, err := d.Info(); err == nil {
+					size += scan.DiskUsage(fileInfo)
+				}
 			}
 			return nil
 		})
 	} else {
 		count = 1
+		size = scan.DiskUsage(info)
 		if counter != nil {
 			atomic.StoreInt64(counter, 1)
 		}
 	}
-
-	// Move to Trash using Finder AppleScript.
-	if err := moveToTrash(root); err != nil {
-		return 0, err
-	}
-
-	return count, nil
+	return count, size
 }
 
 // moveToTrash uses macOS Finder to move a file/directory to Trash.
@@ -144,3 +214,199 @@ func moveToTrash(path string) error {
 
 	return nil
 }
+
+// deleteSelection is the multi-selection a delete from the current view applies to.
+func (m model) deleteSelection() map[string]bool {
+	switch {
+	case m.showReclaim:
+		return m.reclaimMultiSelected
+	case m.showJunk:
+		return m.junkMultiSelected
+	case m.showRecent:
+		return nil // Only the file under the cursor.
+	case m.showLargeFiles:
+		return m.largeMultiSelected
+	default:
+		return m.multiSelected
+	}
+}
+
+// pendingDeletePaths collects the paths the current confirm prompt applies to.
+func (m model) pendingDeletePaths() []string {
+	selected := m.deleteSelection()
+	var paths []string
+	if len(selected) > 0 {
+		for path := range selected {
+			paths = append(paths, path)
+		}
+	} else if m.deleteTarget != nil {
+		paths = append(paths, m.deleteTarget.Path)
+	}
+	return paths
+}
+
+// pendingDeleteSize sums the sizes of the paths awaiting confirmation.
+func (m model) pendingDeleteSize() int64 {
+	paths := m.pendingDeletePaths()
+	if len(paths) == 1 && m.deleteTarget != nil && m.deleteTarget.Path == paths[0] {
+		return m.deleteTarget.Size
+	}
+	if m.showReclaim {
+		return m.reclaimSelectionSize()
+	}
+	if m.showJunk {
+		return m.junkSelectionSize()
+	}
+
+	var total int64
+	for _, path := range paths {
+		if m.showLargeFiles {
+			for _, file := range m.largeFiles {
+				if file.Path == path {
+					total += file.Size
+					break
+				}
+			}
+			continue
+		}
+		for _, entry := range m.entries {
+			if entry.Path == path {
+				total += entry.Size
+				break
+			}
+		}
+	}
+	return total
+}
+
+// prepareDeleteConfirm runs the delete policy once a confirm prompt has been
+// requested, refusing protected paths before the user is asked anything.
+func (m *model) prepareDeleteConfirm() {
+	if !m.deleteConfirm {
+		return
+	}
+	m.deleteTyped = ""
+	m.deletePermanent = false
+	m.deleteNoTrash = false
+
+	if m.policy.readOnly {
+		m.deleteConfirm = false
+		m.deleteTarget = nil
+		m.status = "Read-only mode, deletion is disabled"
+		return
+	}
+
+	paths := m.pendingDeletePaths()
+	if m.virtual != nil && slices.ContainsFunc(paths, m.virtual.partial) {
+		// Trashing it would take files that were never listed.
+		m.deleteConfirm = false
+		m.deleteTarget = nil
+		m.status = "Only listed files can be deleted here, this folder holds others too"
+		return
+	}
+	if err := m.policy.checkAll(paths); err != nil {
+		m.deleteConfirm = false
+		m.deleteTarget = nil
+		m.status = err.Error()
+		return
+	}
+
+	for _, path := range paths {
+		if trashDirFor(path, m.policy.home) == "" {
+			m.deleteNoTrash = true
+			m.deletePermanent = true
+			break
+		}
+	}
+}
+
+// offerPermanentDelete reopens the confirm prompt for paths Finder could not
+// move to Trash, this time as a permanent delete.
+func (m *model) offerPermanentDelete(paths []string, err error) {
+	if selected := m.deleteSelection(); len(selected) > 0 {
+		maps.DeleteFunc(selected, func(path string, _ bool) bool { return !slices.Contains(paths, path) })
+	}
+	if m.deleteTarget == nil || len(paths) > 1 || m.deleteTarget.Path != paths[0] {
+		m.deleteTarget = &dirEntry{Name: filepath.Base(paths[0]), Path: paths[0]}
+	}
+	m.deleteConfirm = true
+	m.deleteTyped = ""
+	m.deletePermanent = true
+	m.deleteNoTrash = true
+	m.status = fmt.Sprintf("Could not move to Trash: %v", err)
+}
+
+func (m model) updateDeleteConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	typed := needsTypedConfirm(m.pendingDeleteSize(), m.deletePermanent)
+
+	switch msg.String() {
+	case "enter":
+		if typed && m.deleteTyped != typedConfirmWord {
+			m.status = fmt.Sprintf("Type %q to confirm", typedConfirmWord)
+			return m, nil
+		}
+
+		pathsToDelete := m.pendingDeletePaths()
+		permanent := m.deletePermanent
+		m.deleteConfirm = false
+		m.deleteTyped = ""
+		m.deletePermanent = false
+		m.deleteNoTrash = false
+		if len(pathsToDelete) == 0 {
+			m.deleteTarget = nil
+			m.status = "Nothing to delete"
+			return m, nil
+		}
+
+		m.deleting = true
+		m.deletingPermanent = permanent
+		var deleteCount int64
+		m.deleteCount = &deleteCount
+
+		verb := "Deleting"
+		if permanent {
+			verb = "Permanently deleting"
+		}
+		if len(pathsToDelete) == 1 {
+			targetPath := pathsToDelete[0]
+			m.status = fmt.Sprintf("%s %s...", verb, filepath.Base(targetPath))
+			return m, tea.Batch(deletePathCmd(targetPath, m.deleteCount, permanent), tickCmd())
+		}
+
+		m.status = fmt.Sprintf("%s %d items...", verb, len(pathsToDelete))
+		return m, tea.Batch(deleteMultiplePathsCmd(pathsToDelete, m.deleteCount, permanent), tickCmd())
+	case "tab":
+		if m.deleteNoTrash {
+			m.status = "Trash is unavailable here, only permanent delete is possible"
+			return m, nil
+		}
+		m.deletePermanent = !m.deletePermanent
+		m.deleteTyped = ""
+		return m, nil
+	case "esc":
+		m.status = "Cancelled"
+		m.deleteConfirm = false
+		m.deleteTarget = nil
+		m.deleteTyped = ""
+		return m, nil
+	case "backspace":
+		if typed && m.deleteTyped != "" {
+			m.deleteTyped = m.deleteTyped[:len(m.deleteTyped)-1]
+		}
+		return m, nil
+	}
+
+	if !typed {
+		if msg.String() == "q" {
+			m.status = "Cancelled"
+			m.deleteConfirm = false
+			m.deleteTarget = nil
+		}
+		return m, nil
+	}
+
+	if msg.Type == tea.KeyRunes && len(m.deleteTyped) < len(typedConfirmWord) {
+		m.deleteTyped += string(msg.Runes)
+	}
+	return m, nil
+}
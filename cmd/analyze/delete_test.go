@@ -11,6 +11,7 @@ func TestTrashPathWithProgress(t *testing.T) {
 	if os.Getenv("CI") != "" {
 		t.Skip("Skipping Finder-dependent test in CI")
 	}
+	t.Setenv("MO_NO_OPLOG", "1")
 
 	parent := t.TempDir()
 	target := filepath.Join(parent, "target")
@@ -46,6 +47,7 @@ func TestDeleteMultiplePathsCmdHandlesParentChild(t *testing.T) {
 	if os.Getenv("CI") != "" {
 		t.Skip("Skipping Finder-dependent test in CI")
 	}
+	t.Setenv("MO_NO_OPLOG", "1")
 
 	base := t.TempDir()
 	parent := filepath.Join(base, "parent")
@@ -63,7 +65,7 @@ func TestDeleteMultiplePathsCmdHandlesParentChild(t *testing.T) {
 	}
 
 	var counter int64
-	msg := deleteMultiplePathsCmd([]string{parent, child}, &counter)()
+	msg := deleteMultiplePathsCmd([]string{parent, child}, &counter, false)()
 	progress, ok := msg.(deleteProgressMsg)
 	if !ok {
 		t.Fatalf("expected deleteProgressMsg, got %T", msg)
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+// maxListIssues is how many unreadable listed paths a folder reports, as the
+// scanner does.
+const maxListIssues = 500
+
+// readPathList reads the paths for --from-stdin: NUL-separated when the input
+// holds a NUL, one per line otherwise. Relative paths are resolved against the
+// working directory and repeats are dropped.
+func readPathList(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	sep := []byte("\n")
+	if bytes.IndexByte(data, 0) >= 0 {
+		sep = []byte{0}
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, field := range bytes.Split(data, sep) {
+		line := strings.TrimSuffix(string(field), "\r")
+		if line == "" {
+			continue
+		}
+		path, err := filepath.Abs(line)
+		if err != nil || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// virtualTree browses a list of paths as a directory tree rooted at their
+// common parent. Each folder counts only the listed files beneath it; a listed
+// folder with nothing listed beneath it is sized in full and scanned for real
+// when opened.
+type virtualTree struct {
+	root  string
+	paths []string
+
+	mu      sync.Mutex
+	results map[string]scanResult // nil until built, and after invalidate
+}
+
+func newVirtualTree(paths []string) *virtualTree {
+	return &virtualTree{root: commonDir(paths), paths: paths}
+}
+
+// commonDir is the deepest folder holding every path.
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return "/"
+	}
+	root := filepath.Dir(paths[0])
+	for _, path := range paths[1:] {
+		for root != "/" && !inDir(path, root) {
+			root = filepath.Dir(root)
+		}
+	}
+	return root
+}
+
+// inDir reports whether path lies below dir.
+func inDir(path, dir string) bool {
+	if dir == string(filepath.Separator) {
+		return path != dir
+	}
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// partial reports whether path is a folder of the tree, whose size counts only
+// the listed paths beneath it rather than everything on disk.
+func (t *virtualTree) partial(path string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.ContainsFunc(t.paths, func(p string) bool { return inDir(p, path) })
+}
+
+// forget drops deleted paths, and anything listed beneath them, so they are not
+// reported as unreadable when the tree is built again.
+func (t *virtualTree) forget(removed []string) {
+	if len(removed) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paths = slices.DeleteFunc(t.paths, func(p string) bool {
+		return slices.ContainsFunc(removed, func(r string) bool { return p == r || inDir(p, r) })
+	})
+}
+
+// invalidate makes the next result stat every path again, after deletes or a refresh.
+func (t *virtualTree) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.results = nil
+}
+
+// result returns the listing for a folder of the tree, building the tree first
+// if needed. Folders outside it, or listed without anything beneath them, are
+// not part of the tree.
+func (t *virtualTree) result(path string, filesScanned, bytesScanned *int64) (scanResult, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.results == nil {
+		t.results = t.build(filesScanned, bytesScanned)
+	}
+	result, ok := t.results[path]
+	return result, ok
+}
+
+// virtualDir collects one folder's listing while the tree is built.
+type virtualDir struct {
+	entries map[string]*dirEntry
+	files   []fileEntry
+	issues  scanIssueReport
+	total   int64
+	count   int64
+}
+
+func (t *virtualTree) build(filesScanned, bytesScanned *int64) map[string]scanResult {
+	sorted := slices.Sorted(slices.Values(t.paths))
+	dirs := make(map[string]*virtualDir)
+	node := func(path string) *virtualDir {
+		d, ok := dirs[path]
+		if !ok {
+			d = &virtualDir{entries: make(map[string]*dirEntry)}
+			dirs[path] = d
+		}
+		return d
+	}
+	// add counts an item toward every folder from its parent up to the root.
+	add := func(path string, size int64, isDir bool, file *fileEntry) {
+		child, childIsDir := path, isDir
+		for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+			d := node(dir)
+			e, ok := d.entries[child]
+			if !ok {
+				e = &dirEntry{Name: filepath.Base(child), Path: child, IsDir: childIsDir}
+				d.entries[child] = e
+			}
+			e.Size += size
+			d.total += size
+			if file != nil {
+				d.count++
+				d.files = append(d.files, *file)
+				if len(d.files) >= 2*maxLargeFiles {
+					d.files = topLargeFiles(d.files)
+				}
+			}
+			if dir == t.root || dir == filepath.Dir(dir) {
+				return
+			}
+			child, childIsDir = dir, true
+		}
+	}
+
+	var issues []scan.Issue
+	for _, path := range t.paths {
+		info, err := os.Lstat(path)
+		if err != nil {
+			issues = append(issues, listIssue(path, err))
+			continue
+		}
+		if info.IsDir() {
+			// Folders with listed paths beneath them are covered by those paths.
+			if i, _ := slices.BinarySearch(sorted, path+string(filepath.Separator)); i < len(sorted) && inDir(sorted[i], path) {
+				continue
+			}
+			size, err := scan.Size(context.Background(), path, scan.Options{SizeCache: sizeCache})
+			if err != nil {
+				issues = append(issues, listIssue(path, err))
+				continue
+			}
+			add(path, size, true, nil)
+			atomic.AddInt64(bytesScanned, size)
+			continue
+		}
+		size := scan.DiskUsage(info)
+		add(path, size, false, &fileEntry{Name: info.Name(), Path: path, Size: size, ModTime: info.ModTime()})
+		atomic.AddInt64(filesScanned, 1)
+		atomic.AddInt64(bytesScanned, size)
+	}
+
+	for _, issue := range issues {
+		for dir := filepath.Dir(issue.Path); ; dir = filepath.Dir(dir) {
+			if d, ok := dirs[dir]; ok {
+				if d.issues.Total < maxListIssues {
+					d.issues.Issues = append(d.issues.Issues, issue)
+				}
+				d.issues.Total++
+			}
+			if dir == t.root || dir == filepath.Dir(dir) {
+				break
+			}
+		}
+	}
+	if _, ok := dirs[t.root]; !ok {
+		node(t.root).issues = scanIssueReport{Issues: issues[:min(len(issues), maxListIssues)], Total: len(issues)}
+	}
+
+	results := make(map[string]scanResult, len(dirs))
+	for path, d := range dirs {
+		entries := make([]dirEntry, 0, len(d.entries))
+		for _, e := range d.entries {
+			entries = append(entries, *e)
+		}
+		slices.SortFunc(entries, func(a, b dirEntry) int {
+			return cmp.Or(cmp.Compare(b.Size, a.Size), strings.Compare(a.Name, b.Name))
+		})
+		results[path] = scanResult{
+			Root:       path,
+			Entries:    entries,
+			LargeFiles: topLargeFiles(d.files),
+			TotalSize:  d.total,
+			TotalFiles: d.count,
+			Issues:     d.issues,
+		}
+	}
+	return results
+}
+
+// topLargeFiles keeps the maxLargeFiles largest files, largest first.
+func topLargeFiles(files []fileEntry) []fileEntry {
+	sortLargeFiles(files, false)
+	return files[:min(len(files), maxLargeFiles)]
+}
+
+// listIssue describes a listed path that could not be read.
+func listIssue(path string, err error) scan.Issue {
+	kind := scan.IssueIO
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		kind = scan.IssueVanished
+	case errors.Is(err, fs.ErrPermission):
+		kind = scan.IssuePermission
+	}
+	return scan.Issue{Path: path, Kind: kind, Err: err.Error()}
+}
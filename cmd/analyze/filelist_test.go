@@ -0,0 +1,207 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+func TestReadPathList(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+
+	paths, err := readPathList(strings.NewReader("/tmp/a b\r\n\nrel/c\n/tmp/a b\n"))
+	if err != nil {
+		t.Fatalf("readPathList: %v", err)
+	}
+	if want := []string{"/tmp/a b", filepath.Join(wd, "rel", "c")}; !slices.Equal(paths, want) {
+		t.Fatalf("got %q, want %q", paths, want)
+	}
+
+	// With NULs, newlines belong to the names.
+	paths, _ = readPathList(strings.NewReader("/tmp/line\nbreak\x00/tmp/d\x00"))
+	if want := []string{"/tmp/line\nbreak", "/tmp/d"}; !slices.Equal(paths, want) {
+		t.Fatalf("got %q, want %q", paths, want)
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{[]string{"/data/a/x.bin", "/data/b/y.bin"}, "/data"},
+		{[]string{"/data/a/x.bin", "/data/a"}, "/data"},
+		{[]string{"/data/a/x.bin", "/"}, "/"}, // Used to loop forever.
+		{[]string{"/", "/data/a/x.bin"}, "/"},
+	}
+	for _, tt := range tests {
+		if got := commonDir(tt.paths); got != tt.want {
+			t.Errorf("commonDir(%q) = %s, want %s", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestVirtualTreeCountsListedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "src", "main.go"), 3000)
+	writeFileWithSize(t, filepath.Join(root, "src", "util", "util.go"), 9000)
+	writeFileWithSize(t, filepath.Join(root, "src", "util", "unlisted.go"), 50000)
+	writeFileWithSize(t, filepath.Join(root, "assets", "logo.png"), 20000)
+	writeFileWithSize(t, filepath.Join(root, "assets", "icons", "a.svg"), 8000)
+
+	usage := func(rel string) int64 {
+		info, err := os.Lstat(filepath.Join(root, rel))
+		if err != nil {
+			t.Fatalf("lstat: %v", err)
+		}
+		return scan.DiskUsage(info)
+	}
+
+	tree := newVirtualTree([]string{
+		filepath.Join(root, "src", "main.go"),
+		filepath.Join(root, "src", "util", "util.go"),
+		filepath.Join(root, "assets"), // Covered by logo.png below.
+		filepath.Join(root, "assets", "logo.png"),
+		filepath.Join(root, "assets", "icons"), // Nothing listed beneath, so sized in full.
+		filepath.Join(root, "gone.txt"),
+	})
+	if tree.root != root {
+		t.Fatalf("expected the common parent %s, got %s", root, tree.root)
+	}
+
+	var files, bytes int64
+	top, ok := tree.result(root, &files, &bytes)
+	if !ok {
+		t.Fatal("expected a listing for the root")
+	}
+	if top.TotalFiles != 3 || files != 3 {
+		t.Fatalf("expected the three listed files, got %d (%d counted)", top.TotalFiles, files)
+	}
+	if top.Issues.Total != 1 || top.Issues.Issues[0].Kind != scan.IssueVanished {
+		t.Fatalf("expected the missing path as an issue, got %+v", top.Issues)
+	}
+
+	src, _ := tree.result(filepath.Join(root, "src"), &files, &bytes)
+	if want := usage("src/main.go") + usage("src/util/util.go"); src.TotalSize != want {
+		t.Fatalf("expected src to hold only listed files, %d bytes, got %d", want, src.TotalSize)
+	}
+	if len(src.LargeFiles) != 2 || src.LargeFiles[0].Name != "util.go" {
+		t.Fatalf("expected listed files largest first, got %+v", src.LargeFiles)
+	}
+
+	assets, _ := tree.result(filepath.Join(root, "assets"), &files, &bytes)
+	var names []string
+	for _, e := range assets.Entries {
+		names = append(names, e.Name)
+	}
+	if !slices.Equal(names, []string{"logo.png", "icons"}) || !assets.Entries[1].IsDir || assets.Entries[1].Size != usage("assets/icons/a.svg") {
+		t.Fatalf("expected logo.png and the fully sized icons folder, got %+v", assets.Entries)
+	}
+	if _, ok := tree.result(filepath.Join(root, "assets", "icons"), &files, &bytes); ok {
+		t.Fatal("expected a folder listed on its own to be scanned for real")
+	}
+
+	if err := os.Remove(filepath.Join(root, "src", "main.go")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	tree.invalidate()
+	if src, _ = tree.result(filepath.Join(root, "src"), &files, &bytes); src.TotalFiles != 1 {
+		t.Fatalf("expected the deleted file to drop out, got %d files", src.TotalFiles)
+	}
+}
+
+func TestVirtualTreeStaysAtRoot(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a", "one.bin"), 100)
+	writeFileWithSize(t, filepath.Join(root, "b", "two.bin"), 100)
+
+	tree := newVirtualTree([]string{filepath.Join(root, "a", "one.bin"), filepath.Join(root, "b", "two.bin")})
+	m := newModel(tree.root, false)
+	m.virtual = tree
+
+	next, _ := m.Update(m.scanCmd(m.path)())
+	m = next.(model)
+	if len(m.entries) != 2 || m.scanning {
+		t.Fatalf("expected both listed folders, got %+v", m.entries)
+	}
+
+	next, cmd := m.goBack()
+	if next.(model).path != root || next.(model).inOverviewMode() || cmd != nil {
+		t.Fatal("expected back at the top of the list to stay put")
+	}
+}
+
+func TestVirtualTreeDeletesOnlyListedPaths(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "src", "main.go"), 100)
+	writeFileWithSize(t, filepath.Join(root, "src", "untracked.go"), 100)
+	writeFileWithSize(t, filepath.Join(root, "notes.txt"), 100)
+
+	tree := newVirtualTree([]string{filepath.Join(root, "src", "main.go"), filepath.Join(root, "notes.txt")})
+	m := newModel(tree.root, false)
+	m.virtual = tree
+	next, _ := m.Update(m.scanCmd(m.path)())
+	m = next.(model)
+
+	// src/ counts only main.go, but trashing it would take untracked.go too.
+	m.selected = slices.IndexFunc(m.entries, func(e dirEntry) bool { return e.Name == "src" })
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m = next.(model); m.deleteConfirm {
+		t.Fatal("expected deleting a listed folder to be refused")
+	}
+	m.selected = slices.IndexFunc(m.entries, func(e dirEntry) bool { return e.Name == "notes.txt" })
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m = next.(model); !m.deleteConfirm {
+		t.Fatalf("expected a listed file to be deletable, status %q", m.status)
+	}
+
+	if err := os.Remove(filepath.Join(root, "notes.txt")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	m.deleteConfirm = false
+	next, _ = m.Update(deleteProgressMsg{done: true, count: 1, path: filepath.Join(root, "notes.txt"), removed: []string{filepath.Join(root, "notes.txt")}})
+	m = next.(model)
+	var files, bytes int64
+	if top, _ := tree.result(root, &files, &bytes); top.Issues.Total != 0 {
+		t.Fatalf("expected the deleted file not to come back as unreadable, got %+v", top.Issues)
+	}
+}
+
+func TestVirtualTreeCompressesAndMovesOnlyListedPaths(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "src", "main.go"), 100)
+	writeFileWithSize(t, filepath.Join(root, "src", "untracked.go"), 100)
+	writeFileWithSize(t, filepath.Join(root, "notes.txt"), 100)
+
+	tree := newVirtualTree([]string{filepath.Join(root, "src", "main.go"), filepath.Join(root, "notes.txt")})
+	m := newModel(tree.root, false)
+	m.virtual = tree
+	next, _ := m.Update(m.scanCmd(m.path)())
+	m = next.(model)
+
+	// src/ holds untracked.go, which the archive or the move would take along.
+	m.selected = slices.IndexFunc(m.entries, func(e dirEntry) bool { return e.Name == "src" })
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	if m = next.(model); m.compressConfirm {
+		t.Fatal("expected compressing a partly listed folder to be refused")
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if m = next.(model); m.moveConfirm {
+		t.Fatal("expected moving a partly listed folder to be refused")
+	}
+
+	m.selected = slices.IndexFunc(m.entries, func(e dirEntry) bool { return e.Name == "notes.txt" })
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	if m = next.(model); !m.compressConfirm {
+		t.Fatalf("expected a listed file to be compressible, status %q", m.status)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -93,6 +94,33 @@ func humanizeBytes(size int64) string {
 	return fmt.Sprintf("%.1f %cB", value, "KMGTPE"[exp])
 }
 
+// parseByteSize reads sizes such as "512K", "50M", "1.5GB" or a plain byte count.
+func parseByteSize(s string) (int64, error) {
+	value := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+	multiplier := int64(1)
+	if i := strings.IndexAny(value, "KMGT"); i >= 0 && i == len(value)-1 {
+		multiplier = int64(1) << (10 * (strings.IndexByte("KMGT", value[i]) + 1))
+		value = value[:i]
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("not a size: %q", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// formatDelta renders a signed size difference, e.g. "+1.2 GB" or "-300 B".
+func formatDelta(delta int64) string {
+	switch {
+	case delta > 0:
+		return "+" + humanizeBytes(delta)
+	case delta < 0:
+		return "-" + humanizeBytes(-delta)
+	default:
+		return "0"
+	}
+}
+
 func coloredProgressBar(value, maxValue int64, percent float64) string {
 	if maxValue <= 0 {
 		return colorGray + strings.Repeat("░", barWidth) + colorReset
@@ -245,3 +273,22 @@ func formatUnusedTime(lastAccess time.Time) string {
 
 	return ""
 }
+
+// formatAge formats how long ago a time was, in coarse units.
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+
+	days := int(time.Since(t).Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 30:
+		return fmt.Sprintf("%dd ago", days)
+	case days < 365:
+		return fmt.Sprintf("%dmo ago", days/30)
+	default:
+		return fmt.Sprintf("%dyr ago", days/365)
+	}
+}
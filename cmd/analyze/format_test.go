@@ -347,3 +347,44 @@ func TestFormatUnusedTime(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatAge(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{"zero", time.Time{}, "unknown"},
+		{"hours ago", now.Add(-3 * time.Hour), "today"},
+		{"days ago", now.Add(-5 * 24 * time.Hour), "5d ago"},
+		{"months ago", now.Add(-65 * 24 * time.Hour), "2mo ago"},
+		{"years ago", now.Add(-800 * 24 * time.Hour), "2yr ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatAge(tt.t); got != tt.want {
+				t.Errorf("formatAge() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDelta(t *testing.T) {
+	tests := []struct {
+		delta int64
+		want  string
+	}{
+		{0, "0"},
+		{512, "+512 B"},
+		{-2048, "-2.0 KB"},
+		{3 << 30, "+3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDelta(tt.delta); got != tt.want {
+			t.Errorf("formatDelta(%d) = %q, want %q", tt.delta, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+// gitBlob is one blob from repository history.
+type gitBlob struct {
+	ID         string
+	Path       string
+	Size       int64
+	DiskSize   int64
+	InCheckout bool // Path still exists in the working tree.
+}
+
+// gitWorktree is a linked worktree registered in the repository.
+type gitWorktree struct {
+	Path    string
+	Size    int64
+	Missing bool
+}
+
+// gitInsights breaks down where a repository's bytes go, using local data only.
+type gitInsights struct {
+	Root           string
+	GitDir         string
+	GitDirSize     int64
+	CheckoutSize   int64
+	PackSize       int64
+	PackCount      int
+	LooseSize      int64
+	LooseCount     int64
+	LFSSize        int64
+	Worktrees      []gitWorktree
+	UntrackedSize  int64
+	UntrackedCount int
+	IgnoredSize    int64
+	IgnoredCount   int
+	LargestBlobs   []gitBlob
+	Warnings       []string
+}
+
+// bloated reports whether .git is much larger than the checked-out tree.
+func (g gitInsights) bloated() bool {
+	return g.GitDirSize >= gitBloatMinSize && g.CheckoutSize > 0 && g.GitDirSize > g.CheckoutSize*gitBloatRatio
+}
+
+type gitInsightsMsg struct {
+	root     string
+	insights *gitInsights
+	err      error
+}
+
+// hasGitRepo reports whether dir is the top of a git checkout.
+func hasGitRepo(dir string) bool {
+	_, err := os.Lstat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// resolveGitDirs returns the repository's git dir and common dir.
+// A .git file (linked worktree or submodule) points at the real git dir.
+func resolveGitDirs(root string) (gitDir, commonDir string, err error) {
+	dotGit := filepath.Join(root, ".git")
+	info, err := os.Lstat(dotGit)
+	if err != nil {
+		return "", "", err
+	}
+
+	gitDir = dotGit
+	if !info.IsDir() {
+		data, err := os.ReadFile(dotGit)
+		if err != nil {
+			return "", "", err
+		}
+		line := strings.TrimSpace(string(data))
+		target, ok := strings.CutPrefix(line, "gitdir:")
+		if !ok {
+			return "", "", fmt.Errorf("unrecognized .git file")
+		}
+		gitDir = strings.TrimSpace(target)
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(root, gitDir)
+		}
+	}
+
+	commonDir = gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+	return filepath.Clean(gitDir), filepath.Clean(commonDir), nil
+}
+
+// collectGitInsights inspects the repository at root. rootTotal is the already
+// scanned size of root; pass 0 to skip the checkout/.git comparison.
+func collectGitInsights(ctx context.Context, root string, rootTotal int64) (*gitInsights, error) {
+	gitDir, commonDir, err := resolveGitDirs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &gitInsights{Root: root, GitDir: gitDir}
+	g.GitDirSize = measureGitPath(ctx, commonDir)
+	if rootTotal > 0 && strings.HasPrefix(commonDir, root+string(os.PathSeparator)) {
+		g.CheckoutSize = max(rootTotal-g.GitDirSize, 0)
+	}
+
+	packDir := filepath.Join(commonDir, "objects", "pack")
+	if entries, err := os.ReadDir(packDir); err == nil {
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || entry.IsDir() {
+				continue
+			}
+			g.PackSize += scan.DiskUsage(info)
+			if strings.HasSuffix(entry.Name(), ".pack") {
+				g.PackCount++
+			}
+		}
+	}
+
+	g.LooseSize, g.LooseCount = measureLooseObjects(filepath.Join(commonDir, "objects"))
+	g.LFSSize = measureGitPath(ctx, filepath.Join(commonDir, "lfs"))
+	g.Worktrees = listGitWorktrees(ctx, commonDir)
+
+	if _, err := exec.LookPath("git"); err != nil {
+		g.Warnings = append(g.Warnings, "git not found, skipped working tree and history details")
+		return g, ctx.Err()
+	}
+
+	gctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+
+	if paths, err := gitListPaths(gctx, root, "ls-files", "-z", "--others", "--exclude-standard"); err == nil {
+		g.UntrackedCount = len(paths)
+		g.UntrackedSize = sumGitPaths(ctx, root, paths)
+	} else {
+		g.Warnings = append(g.Warnings, fmt.Sprintf("untracked files: %v", err))
+	}
+
+	if paths, err := gitListPaths(gctx, root, "ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory"); err == nil {
+		g.IgnoredCount = len(paths)
+		g.IgnoredSize = sumGitPaths(ctx, root, paths)
+	} else {
+		g.Warnings = append(g.Warnings, fmt.Sprintf("ignored files: %v", err))
+	}
+
+	if blobs, err := largestGitBlobs(gctx, root, gitTopBlobs); err == nil {
+		for i := range blobs {
+			if blobs[i].Path != "" {
+				_, statErr := os.Lstat(filepath.Join(root, blobs[i].Path))
+				blobs[i].InCheckout = statErr == nil
+			}
+		}
+		g.LargestBlobs = blobs
+	} else {
+		g.Warnings = append(g.Warnings, fmt.Sprintf("history blobs: %v", err))
+	}
+
+	// A cancelled inspection has partial sizes and timeout warnings; drop them.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func measureGitPath(ctx context.Context, path string) int64 {
+	if _, err := os.Stat(path); err != nil {
+		return 0
+	}
+	size, _ := scan.Size(ctx, path, scan.Options{SizeCache: sizeCache})
+	return size
+}
+
+// measureLooseObjects sums objects stored in the two-hex-digit fan-out dirs.
+func measureLooseObjects(objectsDir string) (int64, int64) {
+	var size, count int64
+	entries, err := os.ReadDir(objectsDir)
+	if err != nil {
+		return 0, 0
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || len(entry.Name()) != 2 {
+			continue
+		}
+		if _, err := strconv.ParseUint(entry.Name(), 16, 8); err != nil {
+			continue
+		}
+		fanout := filepath.Join(objectsDir, entry.Name())
+		objects, err := os.ReadDir(fanout)
+		if err != nil {
+			continue
+		}
+		for _, obj := range objects {
+			info, err := obj.Info()
+			if err != nil || obj.IsDir() {
+				continue
+			}
+			size += scan.DiskUsage(info)
+			count++
+		}
+	}
+	return size, count
+}
+
+// listGitWorktrees reads linked worktrees from <common>/worktrees/*/gitdir.
+func listGitWorktrees(ctx context.Context, commonDir string) []gitWorktree {
+	adminDir := filepath.Join(commonDir, "worktrees")
+	entries, err := os.ReadDir(adminDir)
+	if err != nil {
+		return nil
+	}
+	var worktrees []gitWorktree
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(adminDir, entry.Name(), "gitdir"))
+		if err != nil {
+			continue
+		}
+		// gitdir holds the path of the worktree's .git file.
+		path := filepath.Dir(strings.TrimSpace(string(data)))
+		wt := gitWorktree{Path: path}
+		if _, err := os.Stat(path); err != nil {
+			wt.Missing = true
+		} else {
+			wt.Size = measureGitPath(ctx, path)
+		}
+		worktrees = append(worktrees, wt)
+	}
+	sort.Slice(worktrees, func(i, j int) bool { return worktrees[i].Size > worktrees[j].Size })
+	return worktrees
+}
+
+func gitCommand(ctx context.Context, root string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", root}, args...)...)
+	// Read-only inspection: never take optional locks or prompt for credentials.
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0", "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+func gitListPaths(ctx context.Context, root string, args ...string) ([]string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := gitCommand(ctx, root, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timeout after %v", gitTimeout)
+		}
+		return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	var paths []string
+	for _, p := range bytes.Split(stdout.Bytes(), []byte{0}) {
+		if len(p) > 0 {
+			paths = append(paths, string(p))
+		}
+	}
+	return paths, nil
+}
+
+// sumGitPaths sizes repo-relative paths; directory entries end with a slash.
+func sumGitPaths(ctx context.Context, root string, paths []string) int64 {
+	var total int64
+	for _, rel := range paths {
+		full := filepath.Join(root, rel)
+		info, err := os.Lstat(full)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			total += measureGitPath(ctx, full)
+			continue
+		}
+		total += scan.DiskUsage(info)
+	}
+	return total
+}
+
+// largestGitBlobs lists the biggest blobs reachable from any ref.
+func largestGitBlobs(ctx context.Context, root string, limit int) ([]gitBlob, error) {
+	revList := gitCommand(ctx, root, "rev-list", "--objects", "--all")
+	catFile := gitCommand(ctx, root, "cat-file", "--batch-check=%(objecttype) %(objectname) %(objectsize) %(objectsize:disk) %(rest)")
+
+	pipe, err := revList.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	catFile.Stdin = pipe
+	out, err := catFile.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := revList.Start(); err != nil {
+		return nil, err
+	}
+	if err := catFile.Start(); err != nil {
+		// Nothing reads rev-list's output, so it would block on a full pipe.
+		_ = revList.Process.Kill()
+		_ = revList.Wait()
+		return nil, err
+	}
+
+	var blobs []gitBlob
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		blob, ok := parseGitBatchCheckLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		blobs = insertTopBlob(blobs, blob, limit)
+	}
+	scanErr := scanner.Err()
+
+	// If the scan stopped early, closing our end makes cat-file exit instead of
+	// blocking on a full pipe, and rev-list follows once its reader is gone.
+	_ = out.Close()
+	catErr := catFile.Wait()
+	revErr := revList.Wait()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("timeout after %v", gitTimeout)
+	}
+	if scanErr != nil {
+		return nil, fmt.Errorf("git cat-file output: %v", scanErr)
+	}
+	if revErr != nil {
+		return nil, fmt.Errorf("git rev-list: %v", revErr)
+	}
+	if catErr != nil {
+		return nil, fmt.Errorf("git cat-file: %v", catErr)
+	}
+	return blobs, nil
+}
+
+// parseGitBatchCheckLine parses "<type> <id> <size> <disk size> <path>" for blobs.
+func parseGitBatchCheckLine(line string) (gitBlob, bool) {
+	fields := strings.SplitN(line, " ", 5)
+	if len(fields) < 4 || fields[0] != "blob" {
+		return gitBlob{}, false
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return gitBlob{}, false
+	}
+	diskSize, _ := strconv.ParseInt(fields[3], 10, 64)
+	blob := gitBlob{ID: fields[1], Size: size, DiskSize: diskSize}
+	if len(fields) == 5 {
+		blob.Path = fields[4]
+	}
+	return blob, true
+}
+
+// insertTopBlob keeps blobs sorted by size (descending), capped at limit.
+func insertTopBlob(blobs []gitBlob, blob gitBlob, limit int) []gitBlob {
+	if len(blobs) >= limit && blob.Size <= blobs[len(blobs)-1].Size {
+		return blobs
+	}
+	idx := sort.Search(len(blobs), func(i int) bool { return blobs[i].Size < blob.Size })
+	blobs = append(blobs, gitBlob{})
+	copy(blobs[idx+1:], blobs[idx:])
+	blobs[idx] = blob
+	if len(blobs) > limit {
+		blobs = blobs[:limit]
+	}
+	return blobs
+}
+
+func collectGitInsightsCmd(ctx context.Context, root string, rootTotal int64) tea.Cmd {
+	return func() tea.Msg {
+		insights, err := collectGitInsights(ctx, root, rootTotal)
+		return gitInsightsMsg{root: root, insights: insights, err: err}
+	}
+}
+
+func (m *model) openGitView() tea.Cmd {
+	stopScan(&m.gitCancel)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.gitCancel = cancel
+	m.showGit = true
+	m.showLargeFiles = false
+	m.gitLoading = true
+	m.gitInsights = nil
+	m.status = fmt.Sprintf("Inspecting repository %s...", displayPath(m.path))
+	return tea.Batch(collectGitInsightsCmd(ctx, m.path, m.totalSize), tickCmd())
+}
+
+func (m model) updateGitKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.closesView(msg, actionGit) {
+		stopScan(&m.gitCancel)
+		m.showGit = false
+		m.gitLoading = false
+		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+		return m, nil
+	}
+
+	if m.keyAction(msg) == actionRefresh && !m.gitLoading {
+		return m, m.openGitView()
+	}
+	return m, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGitBatchCheckLine(t *testing.T) {
+	blob, ok := parseGitBatchCheckLine("blob 1234abcd 5000 1200 assets/video file.mov")
+	if !ok {
+		t.Fatalf("expected blob line to parse")
+	}
+	if blob.Size != 5000 || blob.DiskSize != 1200 || blob.Path != "assets/video file.mov" {
+		t.Fatalf("unexpected blob: %+v", blob)
+	}
+
+	if _, ok := parseGitBatchCheckLine("tree 1234abcd 300 200 src"); ok {
+		t.Fatalf("trees should be ignored")
+	}
+	if _, ok := parseGitBatchCheckLine("garbage"); ok {
+		t.Fatalf("malformed line should be ignored")
+	}
+}
+
+func TestInsertTopBlobKeepsLargest(t *testing.T) {
+	var blobs []gitBlob
+	for _, size := range []int64{5, 1, 9, 3, 7} {
+		blobs = insertTopBlob(blobs, gitBlob{Size: size}, 3)
+	}
+	want := []int64{9, 7, 5}
+	if len(blobs) != len(want) {
+		t.Fatalf("expected %d blobs, got %d", len(want), len(blobs))
+	}
+	for i, size := range want {
+		if blobs[i].Size != size {
+			t.Fatalf("blobs[%d].Size = %d, want %d", i, blobs[i].Size, size)
+		}
+	}
+}
+
+func TestResolveGitDirsFollowsGitFile(t *testing.T) {
+	base := t.TempDir()
+	main := filepath.Join(base, "main", ".git")
+	wtAdmin := filepath.Join(main, "worktrees", "feature")
+	if err := os.MkdirAll(wtAdmin, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wtAdmin, "commondir"), []byte("../..\n"), 0o644); err != nil {
+		t.Fatalf("write commondir: %v", err)
+	}
+
+	worktree := filepath.Join(base, "feature")
+	if err := os.MkdirAll(worktree, 0o755); err != nil {
+		t.Fatalf("mkdir worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+wtAdmin+"\n"), 0o644); err != nil {
+		t.Fatalf("write .git file: %v", err)
+	}
+
+	gitDir, commonDir, err := resolveGitDirs(worktree)
+	if err != nil {
+		t.Fatalf("resolveGitDirs: %v", err)
+	}
+	if gitDir != wtAdmin {
+		t.Errorf("gitDir = %s, want %s", gitDir, wtAdmin)
+	}
+	if commonDir != main {
+		t.Errorf("commonDir = %s, want %s", commonDir, main)
+	}
+}
+
+func TestCollectGitInsights(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	run("init", "-q")
+	writeFileWithSize(t, filepath.Join(root, "big.bin"), 64*1024)
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("build/\n"), 0o644); err != nil {
+		t.Fatalf("write .gitignore: %v", err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+	run("rm", "-q", "big.bin")
+	run("commit", "-q", "-m", "drop big file")
+
+	writeFileWithSize(t, filepath.Join(root, "notes.txt"), 8192)
+	writeFileWithSize(t, filepath.Join(root, "build", "out.o"), 16384)
+
+	g, err := collectGitInsights(context.Background(), root, 0)
+	if err != nil {
+		t.Fatalf("collectGitInsights: %v", err)
+	}
+	if g.GitDirSize <= 0 {
+		t.Errorf("expected positive .git size")
+	}
+	if g.LooseCount == 0 && g.PackCount == 0 {
+		t.Errorf("expected objects to be counted")
+	}
+	if g.UntrackedCount != 1 || g.UntrackedSize <= 0 {
+		t.Errorf("untracked = %d files / %d bytes, want 1 file", g.UntrackedCount, g.UntrackedSize)
+	}
+	if g.IgnoredCount != 1 || g.IgnoredSize <= 0 {
+		t.Errorf("ignored = %d paths / %d bytes, want 1 path", g.IgnoredCount, g.IgnoredSize)
+	}
+	if len(g.LargestBlobs) == 0 || g.LargestBlobs[0].Path != "big.bin" {
+		t.Fatalf("expected big.bin as largest blob, got %+v", g.LargestBlobs)
+	}
+	if g.LargestBlobs[0].InCheckout {
+		t.Errorf("big.bin was removed from the checkout")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := collectGitInsights(ctx, root, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled inspection to report it, got %v", err)
+	}
+}
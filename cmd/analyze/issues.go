@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+// The scanner's issue types are used as-is.
+type (
+	scanIssue       = scan.Issue
+	scanIssueKind   = scan.IssueKind
+	scanIssueReport = scan.IssueReport
+)
+
+// issueSummary is the one-line indicator shown under the header.
+func issueSummary(r scanIssueReport) string {
+	noun := "paths"
+	if r.Total == 1 {
+		noun = "path"
+	}
+	return fmt.Sprintf("%d %s could not be read (≈ unknown size)", r.Total, noun)
+}
+
+// openIssuesView lists the paths the last scan could not read.
+func (m *model) openIssuesView() {
+	if m.scanIssues.Total == 0 {
+		m.status = "No unreadable paths in this scan"
+		return
+	}
+	m.showIssues = true
+	m.issueSelected = 0
+	m.issueOffset = 0
+}
+
+func (m model) updateIssuesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.closesView(msg, actionIssues) {
+		m.showIssues = false
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k", "K":
+		if m.issueSelected > 0 {
+			m.issueSelected--
+			if m.issueSelected < m.issueOffset {
+				m.issueOffset = m.issueSelected
+			}
+		}
+	case "down", "j", "J":
+		if m.issueSelected < len(m.scanIssues.Issues)-1 {
+			m.issueSelected++
+			viewport := calculateViewport(m.height)
+			if m.issueSelected >= m.issueOffset+viewport {
+				m.issueOffset = m.issueSelected - viewport + 1
+			}
+		}
+	}
+	return m, nil
+}
+
+// issueKindCounts groups the listed issues for the view header.
+func issueKindCounts(issues []scanIssue) string {
+	counts := make(map[scanIssueKind]int)
+	for _, issue := range issues {
+		counts[issue.Kind]++
+	}
+	var parts []string
+	for _, kind := range []scanIssueKind{scan.IssuePermission, scan.IssueIO, scan.IssueTimeout, scan.IssueVanished} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
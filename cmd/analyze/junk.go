@@ -0,0 +1,463 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+// junkKind is a category of clutter the junk finder reports.
+type junkKind int
+
+const (
+	junkBrokenLink junkKind = iota
+	junkEmptyDir
+	junkEmptyFile
+	junkPartial // Temp files and unfinished downloads
+)
+
+var junkKinds = []junkKind{junkPartial, junkBrokenLink, junkEmptyDir, junkEmptyFile}
+
+func (k junkKind) String() string {
+	switch k {
+	case junkBrokenLink:
+		return "Broken symlinks"
+	case junkEmptyDir:
+		return "Empty folders"
+	case junkEmptyFile:
+		return "Zero-byte files"
+	default:
+		return "Temp files and partial downloads"
+	}
+}
+
+// junkPatterns match leftover temp files and unfinished downloads by name.
+var junkPatterns = []string{
+	"*.crdownload", // Chrome
+	"*.part",       // Firefox, wget
+	"*.partial",    // Edge
+	"*.opdownload", // Opera
+	"*.download",   // Safari, a folder
+	".~lock.*#",    // LibreOffice
+	"~$*",          // Microsoft Office owner files
+}
+
+// junkKeepNames are zero-byte files that mark something and must stay.
+var junkKeepNames = map[string]bool{
+	"__init__.py": true,
+	"py.typed":    true,
+	".gitkeep":    true,
+	".keep":       true,
+	".nojekyll":   true,
+	".localized":  true,
+	"Icon\r":      true,
+
+	".metadata_never_index": true,
+}
+
+// junkPackageExts are bundles whose insides belong to the app that wrote them.
+var junkPackageExts = map[string]bool{
+	".app":           true,
+	".framework":     true,
+	".bundle":        true,
+	".plugin":        true,
+	".photoslibrary": true,
+	".xcodeproj":     true,
+	".xcworkspace":   true,
+}
+
+// junkItem is one piece of clutter. Detail says why it was picked, e.g. a broken link's target.
+type junkItem struct {
+	Kind   junkKind
+	Path   string
+	Size   int64
+	IsDir  bool
+	Detail string
+}
+
+// junkGroup holds the items of one kind.
+type junkGroup struct {
+	Kind  junkKind
+	Items []junkItem
+	Size  int64
+}
+
+// junkRow is a flattened list row: a group header (item == -1) or one item.
+type junkRow struct {
+	group int
+	item  int
+}
+
+type junkResultMsg struct {
+	root   string
+	groups []junkGroup
+	err    error
+}
+
+// matchJunkName reports whether name is a temp file or partial download.
+func matchJunkName(name string) bool {
+	for _, pattern := range junkPatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// junkWalker collects clutter below root.
+type junkWalker struct {
+	ctx         context.Context
+	root        string
+	found       *int64
+	currentPath *atomic.Value
+	items       []junkItem
+}
+
+func (w *junkWalker) add(item junkItem) {
+	w.items = append(w.items, item)
+	if w.found != nil {
+		atomic.AddInt64(w.found, 1)
+	}
+}
+
+// skipDir reports whether the walk stays out of a folder: system and network
+// mounts, VCS internals and caches, and app bundles all keep empty files on purpose.
+func (w *junkWalker) skipDir(dir, name string) bool {
+	if scan.DefaultSkipDirs[name] || (w.root == "/" && filepath.Dir(dir) == "/" && scan.DefaultSystemDirs[name]) {
+		return true
+	}
+	if scan.DefaultFoldDirs[name] && !reclaimContainerDirs[name] {
+		return true
+	}
+	return junkPackageExts[strings.ToLower(filepath.Ext(name))]
+}
+
+// walk visits dir and reports whether it is a recursively empty tree. Only
+// the topmost folder of an empty tree is listed, and never the root itself.
+func (w *junkWalker) walk(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	if w.currentPath != nil {
+		w.currentPath.Store(dir)
+	}
+
+	var emptyDirs []string
+	for _, entry := range entries {
+		if w.ctx.Err() != nil {
+			return false
+		}
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+		// Finder metadata alone does not make a folder worth keeping.
+		if name == ".DS_Store" {
+			continue
+		}
+
+		if matchJunkName(name) && entry.Type()&fs.ModeSymlink == 0 {
+			item := junkItem{Kind: junkPartial, Path: path, IsDir: entry.IsDir()}
+			if entry.IsDir() {
+				item.Size, _ = scan.Size(w.ctx, path, scan.Options{SizeCache: sizeCache})
+			} else if info, err := entry.Info(); err == nil {
+				item.Size = scan.DiskUsage(info)
+			}
+			w.add(item)
+			continue
+		}
+
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+				target, _ := os.Readlink(path)
+				w.add(junkItem{Kind: junkBrokenLink, Path: path, Detail: "→ " + target})
+			}
+		case entry.IsDir():
+			if w.skipDir(path, name) {
+				continue
+			}
+			if w.walk(path) {
+				emptyDirs = append(emptyDirs, path)
+			}
+		case entry.Type().IsRegular() && !junkKeepNames[name]:
+			if info, err := entry.Info(); err == nil && info.Size() == 0 {
+				w.add(junkItem{Kind: junkEmptyFile, Path: path})
+			}
+		}
+	}
+
+	// Any entry other than an empty folder makes the tree non-empty.
+	empty := len(emptyDirs) == countNonMetadata(entries)
+	if !empty || dir == w.root {
+		for _, path := range emptyDirs {
+			w.add(junkItem{Kind: junkEmptyDir, Path: path, IsDir: true})
+		}
+	}
+	return empty
+}
+
+// countNonMetadata counts entries other than .DS_Store.
+func countNonMetadata(entries []os.DirEntry) int {
+	count := 0
+	for _, entry := range entries {
+		if entry.Name() != ".DS_Store" {
+			count++
+		}
+	}
+	return count
+}
+
+// collectJunk walks root and returns its clutter grouped by kind.
+func collectJunk(ctx context.Context, root string, found *int64, currentPath *atomic.Value) ([]junkGroup, error) {
+	if _, err := os.ReadDir(root); err != nil {
+		return nil, err
+	}
+	w := &junkWalker{ctx: ctx, root: root, found: found, currentPath: currentPath}
+	w.walk(root)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return groupJunk(w.items), nil
+}
+
+// groupJunk buckets items by kind in junkKinds order, paths sorted within each.
+func groupJunk(items []junkItem) []junkGroup {
+	var groups []junkGroup
+	for _, kind := range junkKinds {
+		g := junkGroup{Kind: kind}
+		for _, item := range items {
+			if item.Kind == kind {
+				g.Items = append(g.Items, item)
+				g.Size += item.Size
+			}
+		}
+		if len(g.Items) == 0 {
+			continue
+		}
+		slices.SortFunc(g.Items, func(a, b junkItem) int { return strings.Compare(a.Path, b.Path) })
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+func junkCount(groups []junkGroup) int {
+	count := 0
+	for _, g := range groups {
+		count += len(g.Items)
+	}
+	return count
+}
+
+func buildJunkRows(groups []junkGroup) []junkRow {
+	var rows []junkRow
+	for gi, g := range groups {
+		rows = append(rows, junkRow{group: gi, item: -1})
+		for ii := range g.Items {
+			rows = append(rows, junkRow{group: gi, item: ii})
+		}
+	}
+	return rows
+}
+
+// pruneJunk drops items that no longer exist on disk (e.g. after trashing).
+func pruneJunk(groups []junkGroup) []junkGroup {
+	var items []junkItem
+	for _, g := range groups {
+		for _, item := range g.Items {
+			if _, err := os.Lstat(item.Path); err == nil {
+				items = append(items, item)
+			}
+		}
+	}
+	return groupJunk(items)
+}
+
+func scanJunkCmd(ctx context.Context, root string, found *int64, currentPath *atomic.Value) tea.Cmd {
+	return func() tea.Msg {
+		groups, err := collectJunk(ctx, root, found, currentPath)
+		return junkResultMsg{root: root, groups: groups, err: err}
+	}
+}
+
+func (m *model) openJunkView() tea.Cmd {
+	stopScan(&m.junkCancel)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.junkCancel = cancel
+	m.showJunk = true
+	m.showLargeFiles = false
+	m.junkScanning = true
+	m.junkGroups = nil
+	m.junkRows = nil
+	m.junkSelected = 0
+	m.junkOffset = 0
+	m.junkMultiSelected = make(map[string]bool)
+	var found int64
+	m.junkFound = &found
+	if m.currentPath != nil {
+		m.currentPath.Store("")
+	}
+	m.status = fmt.Sprintf("Looking for junk under %s...", displayPath(m.path))
+	return tea.Batch(scanJunkCmd(ctx, m.path, m.junkFound, m.currentPath), tickCmd())
+}
+
+func (m *model) setJunkGroups(groups []junkGroup) {
+	m.junkGroups = groups
+	m.junkRows = buildJunkRows(groups)
+	for path := range m.junkMultiSelected {
+		if _, err := os.Lstat(path); err != nil {
+			delete(m.junkMultiSelected, path)
+		}
+	}
+	if m.junkSelected >= len(m.junkRows) {
+		m.junkSelected = max(len(m.junkRows)-1, 0)
+	}
+	viewport := calculateViewport(m.height)
+	maxOffset := max(len(m.junkRows)-viewport, 0)
+	if m.junkOffset > maxOffset {
+		m.junkOffset = maxOffset
+	}
+	if m.junkSelected < m.junkOffset {
+		m.junkOffset = m.junkSelected
+	}
+}
+
+// junkSelectionSize sums the sizes of selected items.
+func (m model) junkSelectionSize() int64 {
+	var total int64
+	for _, g := range m.junkGroups {
+		for _, item := range g.Items {
+			if m.junkMultiSelected[item.Path] {
+				total += item.Size
+			}
+		}
+	}
+	return total
+}
+
+func (m *model) updateJunkStatus() {
+	if count := len(m.junkMultiSelected); count > 0 {
+		m.status = fmt.Sprintf("%d selected, %s", count, humanizeBytes(m.junkSelectionSize()))
+		return
+	}
+	m.status = fmt.Sprintf("%d junk items in %d groups", junkCount(m.junkGroups), len(m.junkGroups))
+}
+
+func (m model) updateJunkKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.closesView(msg, actionJunk) {
+		stopScan(&m.junkCancel)
+		m.showJunk = false
+		m.junkScanning = false
+		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+		return m, nil
+	}
+
+	if m.junkScanning {
+		return m, nil
+	}
+	switch m.keyAction(msg) {
+	case actionDelete:
+		return m.confirmJunkDelete()
+	case actionRefresh:
+		return m, m.openJunkView()
+	}
+
+	switch msg.String() {
+	case "up", "k", "K":
+		if m.junkSelected > 0 {
+			m.junkSelected--
+			if m.junkSelected < m.junkOffset {
+				m.junkOffset = m.junkSelected
+			}
+		}
+	case "down", "j", "J":
+		if m.junkSelected < len(m.junkRows)-1 {
+			m.junkSelected++
+			viewport := calculateViewport(m.height)
+			if m.junkSelected >= m.junkOffset+viewport {
+				m.junkOffset = m.junkSelected - viewport + 1
+			}
+		}
+	case " ":
+		if m.junkSelected >= len(m.junkRows) {
+			return m, nil
+		}
+		row := m.junkRows[m.junkSelected]
+		group := m.junkGroups[row.group]
+		if row.item >= 0 {
+			path := group.Items[row.item].Path
+			if m.junkMultiSelected[path] {
+				delete(m.junkMultiSelected, path)
+			} else {
+				m.junkMultiSelected[path] = true
+			}
+		} else {
+			// Header row toggles the whole group.
+			allSelected := m.junkGroupSelected(group)
+			for _, item := range group.Items {
+				if allSelected {
+					delete(m.junkMultiSelected, item.Path)
+				} else {
+					m.junkMultiSelected[item.Path] = true
+				}
+			}
+		}
+		m.updateJunkStatus()
+	case "a", "A":
+		// Select everything found, or clear the selection if it already is.
+		all := len(m.junkMultiSelected) == junkCount(m.junkGroups)
+		m.junkMultiSelected = make(map[string]bool)
+		if !all {
+			for _, g := range m.junkGroups {
+				for _, item := range g.Items {
+					m.junkMultiSelected[item.Path] = true
+				}
+			}
+		}
+		m.updateJunkStatus()
+	}
+	return m, nil
+}
+
+func (m model) junkGroupSelected(group junkGroup) bool {
+	for _, item := range group.Items {
+		if !m.junkMultiSelected[item.Path] {
+			return false
+		}
+	}
+	return true
+}
+
+// confirmJunkDelete asks to trash the selected items, or the one under the cursor.
+func (m model) confirmJunkDelete() (tea.Model, tea.Cmd) {
+	var target *junkItem
+	if len(m.junkMultiSelected) > 0 {
+		for _, g := range m.junkGroups {
+			for i := range g.Items {
+				if target == nil && m.junkMultiSelected[g.Items[i].Path] {
+					target = &g.Items[i]
+				}
+			}
+		}
+	} else if m.junkSelected < len(m.junkRows) {
+		if row := m.junkRows[m.junkSelected]; row.item >= 0 {
+			target = &m.junkGroups[row.group].Items[row.item]
+		}
+	}
+	if target == nil {
+		return m, nil
+	}
+	m.deleteConfirm = true
+	m.deleteTarget = &dirEntry{Name: filepath.Base(target.Path), Path: target.Path, Size: target.Size, IsDir: target.IsDir}
+	m.prepareDeleteConfirm()
+	return m, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/internal/keymap"
+)
+
+func TestCollectJunkGroupsByKind(t *testing.T) {
+	root := t.TempDir()
+
+	writeFileWithSize(t, filepath.Join(root, "docs", "report.pdf"), 100)
+	writeFileWithSize(t, filepath.Join(root, "docs", "empty.log"), 0)
+	writeFileWithSize(t, filepath.Join(root, "pkg", "__init__.py"), 0)
+	writeFileWithSize(t, filepath.Join(root, "Downloads", "movie.mkv.crdownload"), 2048)
+	writeFileWithSize(t, filepath.Join(root, "Downloads", ".~lock.budget.ods#"), 60)
+	// A tree holding only empty folders and Finder metadata is listed once, at its top.
+	for _, dir := range []string{"old/a/b", "old/c", "keep/empty"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	writeFileWithSize(t, filepath.Join(root, "old", "c", ".DS_Store"), 10)
+	writeFileWithSize(t, filepath.Join(root, "keep", "notes.txt"), 10)
+	if err := os.Symlink(filepath.Join(root, "missing"), filepath.Join(root, "docs", "dangling")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if err := os.Symlink("report.pdf", filepath.Join(root, "docs", "working")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	// Folded directories keep their own empty files.
+	writeFileWithSize(t, filepath.Join(root, "web", "node_modules", "dep", "empty.js"), 0)
+
+	groups, err := collectJunk(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("collectJunk: %v", err)
+	}
+
+	got := make(map[junkKind][]string)
+	for _, g := range groups {
+		for _, item := range g.Items {
+			rel, _ := filepath.Rel(root, item.Path)
+			got[g.Kind] = append(got[g.Kind], rel)
+		}
+	}
+	want := map[junkKind][]string{
+		junkPartial:    {"Downloads/.~lock.budget.ods#", "Downloads/movie.mkv.crdownload"},
+		junkBrokenLink: {"docs/dangling"},
+		junkEmptyDir:   {"keep/empty", "old"},
+		junkEmptyFile:  {"docs/empty.log"},
+	}
+	for kind, paths := range want {
+		if !slices.Equal(got[kind], paths) {
+			t.Errorf("%s: got %v, want %v", kind, got[kind], paths)
+		}
+	}
+	if len(groups) != len(want) || groups[0].Kind != junkPartial {
+		t.Fatalf("expected %d groups led by partial downloads, got %+v", len(want), groups)
+	}
+}
+
+func TestJunkHeaderSelectsGroupForDelete(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a.part"), 10)
+	writeFileWithSize(t, filepath.Join(root, "b.part"), 10)
+	writeFileWithSize(t, filepath.Join(root, "c.txt"), 0)
+
+	groups, err := collectJunk(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("collectJunk: %v", err)
+	}
+	m := model{path: root, showJunk: true, junkMultiSelected: make(map[string]bool)}
+	m.setJunkGroups(groups)
+
+	next, _ := m.updateJunkKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = next.(model)
+	if len(m.junkMultiSelected) != 2 {
+		t.Fatalf("expected the header to select both partial downloads, got %v", m.junkMultiSelected)
+	}
+
+	next, _ = m.updateJunkKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = next.(model)
+	paths := m.pendingDeletePaths()
+	slices.Sort(paths)
+	if !m.deleteConfirm || !slices.Equal(paths, []string{filepath.Join(root, "a.part"), filepath.Join(root, "b.part")}) {
+		t.Fatalf("expected a delete prompt for the selected group, got %v", paths)
+	}
+
+	if err := os.Remove(filepath.Join(root, "a.part")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	m.setJunkGroups(pruneJunk(m.junkGroups))
+	if junkCount(m.junkGroups) != 2 || len(m.junkMultiSelected) != 1 {
+		t.Fatalf("expected pruning to drop the removed file, got %d items, %v selected", junkCount(m.junkGroups), m.junkMultiSelected)
+	}
+}
+
+func TestJunkRemappedDeleteKey(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a.part"), 10)
+	groups, err := collectJunk(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("collectJunk: %v", err)
+	}
+	keys, _ := keymap.Parse(strings.NewReader("delete = x\n"), "analyze", analyzeBindings)
+	m := model{path: root, keys: keys, showJunk: true, junkMultiSelected: make(map[string]bool)}
+	m.setJunkGroups(groups)
+	m.junkSelected = 1 // The file below its group header.
+
+	next, _ := m.updateJunkKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if m = next.(model); !m.showJunk || !m.deleteConfirm {
+		t.Fatal("x should delete once delete is remapped to it, not close the view")
+	}
+}
+
+func TestJunkRefreshCancelsScan(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a.part"), 10)
+
+	m := model{path: root}
+	next, first := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = next.(model)
+	m.junkScanning = false // Let r through as if the first scan had finished.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if m = next.(model); !m.junkScanning || m.junkCancel == nil {
+		t.Fatal("expected r to start a new scan")
+	}
+
+	msg := first().(tea.BatchMsg)[0]().(junkResultMsg)
+	if !errors.Is(msg.err, context.Canceled) {
+		t.Fatalf("expected the earlier walk to stop on refresh, got %v", msg.err)
+	}
+	if next, _ = m.Update(msg); !next.(model).junkScanning {
+		t.Fatal("a cancelled result should not end the new scan")
+	}
+}
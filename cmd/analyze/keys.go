@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/internal/keymap"
+)
+
+// Actions for the main list. Names are what users write in ~/.config/mole/keys.
+const (
+	actionQuit       keymap.Action = "quit"
+	actionClose      keymap.Action = "close"
+	actionUp         keymap.Action = "up"
+	actionDown       keymap.Action = "down"
+	actionEnter      keymap.Action = "enter"
+	actionBack       keymap.Action = "back"
+	actionRefresh    keymap.Action = "refresh"
+	actionLargeFiles keymap.Action = "toggle_large"
+	actionLargeSort  keymap.Action = "sort_large"
+	actionLargeType  keymap.Action = "filter_type"
+	actionPageUp     keymap.Action = "page_up"
+	actionPageDown   keymap.Action = "page_down"
+	actionPreview    keymap.Action = "preview"
+	actionReclaim    keymap.Action = "reclaim"
+	actionJunk       keymap.Action = "junk"
+	actionRecent     keymap.Action = "recent"
+	actionGit        keymap.Action = "git"
+	actionIssues     keymap.Action = "issues"
+	actionOwners     keymap.Action = "owners"
+	actionCompress   keymap.Action = "compress"
+	actionMove       keymap.Action = "move"
+	actionMoves      keymap.Action = "moves"
+	actionOpen       keymap.Action = "open"
+	actionReveal     keymap.Action = "reveal"
+	actionSelect     keymap.Action = "select"
+	actionDelete     keymap.Action = "delete"
+	actionHelp       keymap.Action = "help"
+	actionBookmark   keymap.Action = "bookmark"
+	actionBookmarks  keymap.Action = "bookmarks"
+)
+
+var analyzeBindings = []keymap.Binding{
+	{Action: actionUp, Keys: []string{"up", "k", "K"}, Help: "Move up"},
+	{Action: actionDown, Keys: []string{"down", "j", "J"}, Help: "Move down"},
+	{Action: actionEnter, Keys: []string{"enter", "right", "l", "L"}, Help: "Open folder"},
+	{Action: actionBack, Keys: []string{"b", "left", "h", "B", "H"}, Help: "Go back"},
+	{Action: actionSelect, Keys: []string{" "}, Help: "Select for batch actions"},
+	{Action: actionRefresh, Keys: []string{"r", "R"}, Help: "Rescan"},
+	{Action: actionOpen, Keys: []string{"o", "O"}, Help: "Open with default app"},
+	{Action: actionReveal, Keys: []string{"f", "F"}, Help: "Reveal in Finder"},
+	{Action: actionPreview, Keys: []string{"p", "P"}, Help: "Preview pane"},
+	{Action: actionDelete, Keys: []string{"delete", "backspace"}, Help: "Delete selected"},
+	{Action: actionCompress, Keys: []string{"z", "Z"}, Help: "Zip and replace"},
+	{Action: actionMove, Keys: []string{"m", "M"}, Help: "Move to another volume"},
+	{Action: actionMoves, Keys: []string{"v", "V"}, Help: "Moved folders, to undo a move"},
+	{Action: actionLargeFiles, Keys: []string{"t", "T"}, Help: "Toggle large files"},
+	{Action: actionLargeSort, Keys: []string{"s", "S"}, Help: "Sort large files by size or age"},
+	{Action: actionLargeType, Keys: []string{"."}, Help: "Filter large files by extension"},
+	{Action: actionPageUp, Keys: []string{"pgup"}, Help: "Page up"},
+	{Action: actionPageDown, Keys: []string{"pgdown"}, Help: "Page down"},
+	{Action: actionReclaim, Keys: []string{"c", "C"}, Help: "Reclaimable build artifacts"},
+	{Action: actionJunk, Keys: []string{"x", "X"}, Help: "Empty folders, broken links and partial downloads"},
+	{Action: actionRecent, Keys: []string{"w", "W"}, Help: "Recently written files"},
+	{Action: actionGit, Keys: []string{"g", "G"}, Help: "Git repository panel"},
+	{Action: actionIssues, Keys: []string{"e", "E"}, Help: "Unreadable paths"},
+	{Action: actionOwners, Keys: []string{"u", "U"}, Help: "Space per owner"},
+	{Action: actionBookmark, Keys: []string{"*"}, Help: "Bookmark this folder"},
+	{Action: actionBookmarks, Keys: []string{"'"}, Help: "Jump to a bookmark"},
+	{Action: actionHelp, Keys: []string{"?"}, Help: "Show this help"},
+	{Action: actionClose, Keys: []string{"esc"}, Help: "Close view or clear filter"},
+	{Action: actionQuit, Keys: []string{"q", "ctrl+c", "Q"}, Help: "Quit"},
+}
+
+// defaultKeys backs models built without loadKeys, such as in tests.
+var defaultKeys = keymap.New(analyzeBindings)
+
+// loadKeys reads ~/.config/mole/keys. Lines it skips are printed to stderr,
+// where they are left on screen once the analyzer exits.
+func loadKeys() *keymap.Keymap {
+	keys, warnings := keymap.Load("analyze", analyzeBindings)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "keys: %s\n", warning)
+	}
+	return keys
+}
+
+func (m model) keymap() *keymap.Keymap {
+	if m.keys == nil {
+		return defaultKeys
+	}
+	return m.keys
+}
+
+func (m model) keyAction(msg tea.KeyMsg) keymap.Action {
+	return m.keymap().Action(msg.String())
+}
+
+// closesView reports whether msg leaves a view opened by toggle: close, back,
+// or the toggle key again.
+func (m model) closesView(msg tea.KeyMsg, toggle keymap.Action) bool {
+	switch m.keyAction(msg) {
+	case actionClose, actionBack, toggle:
+		return true
+	}
+	return false
+}
+
+// hint renders "R Refresh" for footers using the active binding; unbound actions render nothing.
+func (m model) hint(action keymap.Action, text string) string {
+	label := m.keymap().Label(action)
+	if label == "" {
+		return ""
+	}
+	if text == "" {
+		return label
+	}
+	return label + " " + text
+}
+
+// footer joins hints with the separator used across views, dropping empty ones.
+func footer(parts ...string) string {
+	kept := parts[:0]
+	for _, part := range parts {
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, " | ")
+}
+
+// helpScreen names the screen the overlay describes and the actions that work there.
+func (m model) helpScreen() (string, []keymap.Action) {
+	switch {
+	case m.inOverviewMode():
+		return "Overview", []keymap.Action{
+			actionUp, actionDown, actionEnter, actionBack, actionRefresh, actionOpen, actionReveal,
+			actionPreview, actionMoves, actionBookmark, actionBookmarks, actionHelp, actionQuit,
+		}
+	case m.showLargeFiles:
+		return "Large files", []keymap.Action{
+			actionUp, actionDown, actionPageUp, actionPageDown, actionSelect, actionOpen, actionReveal,
+			actionPreview, actionDelete, actionCompress, actionLargeSort, actionLargeType, actionRefresh, actionLargeFiles,
+			actionClose, actionHelp, actionQuit,
+		}
+	default:
+		return "Directory", []keymap.Action{
+			actionUp, actionDown, actionEnter, actionBack, actionSelect, actionOpen, actionReveal,
+			actionPreview, actionDelete, actionCompress, actionMove, actionMoves, actionRefresh, actionLargeFiles, actionReclaim,
+			actionJunk, actionRecent, actionGit, actionIssues, actionOwners, actionBookmark, actionBookmarks, actionClose,
+			actionHelp, actionQuit,
+		}
+	}
+}
+
+// updateHelpKey closes the overlay on any key; quit is handled before it.
+func (m model) updateHelpKey(tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.showHelp = false
+	return m, nil
+}
+
+// renderHelp lists the active bindings for the screen under the overlay.
+func (m model) renderHelp(b *strings.Builder) {
+	screen, actions := m.helpScreen()
+	fmt.Fprintf(b, "%sKeys: %s%s\n\n", colorBold, screen, colorReset)
+	for _, binding := range m.keymap().Bindings(actions...) {
+		fmt.Fprintf(b, "   %s%-18s%s %s\n", colorCyan, keymap.KeysLabel(binding.Keys), colorReset, binding.Help)
+	}
+	fmt.Fprintln(b)
+	if path, err := keymap.DefaultPath(); err == nil {
+		fmt.Fprintf(b, "%sRebind in %s, e.g. delete = x%s\n", colorGray, displayPath(path), colorReset)
+	}
+	fmt.Fprintf(b, "%sAny key to close%s\n", colorGray, colorReset)
+}
+
+// mainFooter is the key hint line under the overview, directory and large-file lists.
+func (m model) mainFooter() string {
+	if m.inOverviewMode() {
+		if len(m.history) > 0 {
+			return footer("↑↓←→", m.hint(actionEnter, ""), m.hint(actionRefresh, "Refresh"), m.hint(actionOpen, "Open"),
+				m.hint(actionReveal, "File"), m.hint(actionBookmarks, "Bookmarks"), "← Back", m.hint(actionHelp, "Help"),
+				m.hint(actionQuit, "Quit"))
+		}
+		return footer("↑↓→", m.hint(actionEnter, ""), m.hint(actionRefresh, "Refresh"), m.hint(actionOpen, "Open"),
+			m.hint(actionReveal, "File"), m.hint(actionBookmarks, "Bookmarks"), m.hint(actionHelp, "Help"),
+			m.hint(actionQuit, "Quit"))
+	}
+
+	del := m.hint(actionDelete, "Del")
+	if m.showLargeFiles {
+		if count := len(m.largeMultiSelected); count > 0 && del != "" {
+			del = fmt.Sprintf("%s %d", del, count)
+		}
+		return footer("↑↓←", m.hint(actionSelect, "Select"), m.hint(actionRefresh, "Refresh"), m.hint(actionOpen, "Open"),
+			m.hint(actionReveal, "File"), m.hint(actionPreview, "Preview"), del, m.hint(actionCompress, "Zip"),
+			m.hint(actionLargeSort, "Sort"), m.hint(actionLargeType, "Type"), "← Back", m.hint(actionHelp, "Help"),
+			m.hint(actionQuit, "Quit"))
+	}
+
+	if count := len(m.multiSelected); count > 0 && del != "" {
+		del = fmt.Sprintf("%s %d", del, count)
+	}
+	top := ""
+	if count := len(m.largeFiles); count > 0 {
+		if top = m.hint(actionLargeFiles, "Top"); top != "" {
+			top = fmt.Sprintf("%s %d", top, count)
+		}
+	}
+	return footer("↑↓←→", m.hint(actionSelect, "Select"), m.hint(actionEnter, ""), m.hint(actionRefresh, "Refresh"),
+		m.hint(actionOpen, "Open"), m.hint(actionReveal, "File"), m.hint(actionPreview, "Preview"), del,
+		m.hint(actionCompress, "Zip"), m.hint(actionMove, "Move"), m.hint(actionReclaim, "Reclaim"),
+		m.hint(actionJunk, "Junk"), m.hint(actionRecent, "Recent"), top, m.hint(actionHelp, "Help"), m.hint(actionQuit, "Quit"))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/internal/keymap"
+)
+
+func TestRemappedDeleteKey(t *testing.T) {
+	keys, _ := keymap.Parse(strings.NewReader("delete = x\n"), "analyze", analyzeBindings)
+	m := model{
+		path:    "/tmp/root",
+		keys:    keys,
+		entries: []dirEntry{{Name: "a", Path: "/tmp/root/a", Size: 10}},
+	}
+
+	next, _ := m.updateKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	if next.(model).deleteConfirm {
+		t.Fatal("backspace should no longer delete once delete is remapped")
+	}
+
+	next, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !next.(model).deleteConfirm {
+		t.Fatal("x should open the delete confirmation")
+	}
+
+	if footer := m.mainFooter(); !strings.Contains(footer, "X Del") || strings.Contains(footer, "⌫") {
+		t.Errorf("footer should show the remapped key, got %q", footer)
+	}
+}
+
+func TestHelpOverlayListsScreenBindings(t *testing.T) {
+	m := model{path: "/tmp/root", largeFiles: []fileEntry{{Name: "big", Path: "/tmp/root/big"}}}
+
+	next, _ := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = next.(model)
+	if !m.showHelp {
+		t.Fatal("? should open the help overlay")
+	}
+
+	var b strings.Builder
+	m.renderHelp(&b)
+	if !strings.Contains(b.String(), "Directory") || !strings.Contains(b.String(), "Space per owner") {
+		t.Errorf("directory help should list its bindings, got:\n%s", b.String())
+	}
+
+	m.showLargeFiles = true
+	b.Reset()
+	m.renderHelp(&b)
+	if strings.Contains(b.String(), "Space per owner") {
+		t.Error("large-file help should not list directory-only actions")
+	}
+
+	next, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if next.(model).showHelp {
+		t.Error("any key should close the overlay")
+	}
+}
+
+func TestSubViewsCloseOnBoundKeys(t *testing.T) {
+	keys, _ := keymap.Parse(strings.NewReader("back = a\nowners = o\n"), "analyze", analyzeBindings)
+	m := model{path: "/tmp/root", keys: keys, showOwners: true}
+
+	next, _ := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	if !next.(model).showOwners {
+		t.Fatal("b should no longer close a view once back is remapped")
+	}
+	for _, r := range []rune{'a', 'o'} {
+		next, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		if next.(model).showOwners {
+			t.Fatalf("%c should close the owners view", r)
+		}
+	}
+	next, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if next.(model).showOwners {
+		t.Fatal("esc should close the owners view")
+	}
+}
+
+func TestSubViewsRefreshOnBoundKey(t *testing.T) {
+	keys, _ := keymap.Parse(strings.NewReader("refresh = y\n"), "analyze", analyzeBindings)
+	for _, m := range []model{
+		{path: "/tmp/root", keys: keys, showGit: true},
+		{path: "/tmp/root", keys: keys, showJunk: true},
+	} {
+		if _, cmd := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}}); cmd != nil {
+			t.Fatal("r should no longer rescan once refresh is remapped")
+		}
+		if _, cmd := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}); cmd == nil {
+			t.Fatal("y should rescan the view")
+		}
+	}
+
+	m := model{path: "/tmp/root", keys: keys, showGit: true, gitInsights: &gitInsights{}}
+	var b strings.Builder
+	m.renderGit(&b)
+	if !strings.Contains(b.String(), "Y Refresh") || strings.Contains(b.String(), "R Refresh") {
+		t.Errorf("git footer should show the remapped key, got:\n%s", b.String())
+	}
+}
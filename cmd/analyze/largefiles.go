@@ -0,0 +1,197 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+// largeFileIndex lists every file over the --large-min threshold that scans
+// have seen. main replaces it with the copy saved by the last run.
+var largeFileIndex = scan.NewFileIndex(defaultLargeIndexMin)
+
+func getLargeFileIndexPath() (string, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, largeFileIndexFile), nil
+}
+
+// loadLargeFileIndex reads the saved index, starting empty when there is none
+// or it cannot be read.
+func loadLargeFileIndex(minSize int64) *scan.FileIndex {
+	path, err := getLargeFileIndexPath()
+	if err != nil {
+		return scan.NewFileIndex(minSize)
+	}
+	index, _ := scan.LoadFileIndex(path, minSize)
+	return index
+}
+
+func saveLargeFileIndex() error {
+	path, err := getLargeFileIndexPath()
+	if err != nil {
+		return err
+	}
+	return largeFileIndex.Save(path)
+}
+
+// mergeLargeFiles adds the scan's own top files to the indexed ones, which may
+// hold smaller files when the tree has few over the threshold. Largest first.
+func mergeLargeFiles(top, indexed []fileEntry) []fileEntry {
+	merged := slices.Clone(indexed)
+	for _, file := range top {
+		if !slices.ContainsFunc(indexed, func(f fileEntry) bool { return f.Path == file.Path }) {
+			merged = append(merged, file)
+		}
+	}
+	sortLargeFiles(merged, false)
+	return merged
+}
+
+// sortLargeFiles orders files largest first, or least recently modified first.
+func sortLargeFiles(files []fileEntry, byAge bool) {
+	slices.SortStableFunc(files, func(a, b fileEntry) int {
+		if byAge {
+			// Files without a time sort last.
+			if a.ModTime.IsZero() != b.ModTime.IsZero() {
+				if a.ModTime.IsZero() {
+					return 1
+				}
+				return -1
+			}
+			if c := a.ModTime.Compare(b.ModTime); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(b.Size, a.Size)
+	})
+}
+
+// largeFileExt is the extension a file is filtered by; "" for none.
+func largeFileExt(path string) string {
+	return strings.ToLower(filepath.Ext(path))
+}
+
+// largeFileExts lists the extensions among files, the most space first.
+func largeFileExts(files []fileEntry) []string {
+	totals := make(map[string]int64)
+	for _, file := range files {
+		totals[largeFileExt(file.Path)] += file.Size
+	}
+	exts := make([]string, 0, len(totals))
+	for ext := range totals {
+		exts = append(exts, ext)
+	}
+	slices.SortFunc(exts, func(a, b string) int {
+		return cmp.Or(cmp.Compare(totals[b], totals[a]), strings.Compare(a, b))
+	})
+	return exts
+}
+
+func extLabel(ext string) string {
+	if ext == "" {
+		return "no extension"
+	}
+	return ext
+}
+
+// setLargeFiles replaces the large-file list, dropping the extension filter
+// and keeping the sort order.
+func (m *model) setLargeFiles(files []fileEntry) {
+	m.largeExtFilter = ""
+	m.largeAllFiles = nil
+	m.largeFiles = files
+	sortLargeFiles(m.largeFiles, m.largeByAge)
+}
+
+// unfilteredLargeFiles returns the list without the extension filter.
+func (m model) unfilteredLargeFiles() []fileEntry {
+	if m.largeExtFilter != "" {
+		return m.largeAllFiles
+	}
+	return m.largeFiles
+}
+
+// cycleLargeExtFilter narrows the list to the next extension, by space used,
+// and back to every file after the last one.
+func (m *model) cycleLargeExtFilter() {
+	exts := largeFileExts(m.unfilteredLargeFiles())
+	if len(exts) == 0 {
+		return
+	}
+	next := exts[0]
+	if m.largeExtFilter != "" {
+		i := slices.Index(exts, m.largeExtFilter)
+		if i+1 >= len(exts) {
+			m.clearLargeExtFilter()
+			return
+		}
+		next = exts[i+1]
+	} else {
+		m.largeAllFiles = m.largeFiles
+	}
+
+	m.largeExtFilter = next
+	m.largeFiles = slices.DeleteFunc(slices.Clone(m.largeAllFiles), func(f fileEntry) bool {
+		return largeFileExt(f.Path) != next
+	})
+	m.largeSelected = 0
+	m.largeOffset = 0
+	m.largeMultiSelected = make(map[string]bool)
+	m.status = fmt.Sprintf("Showing %s only, %d files", extLabel(next), len(m.largeFiles))
+}
+
+func (m *model) clearLargeExtFilter() {
+	m.largeFiles = m.largeAllFiles
+	m.largeExtFilter = ""
+	m.largeAllFiles = nil
+	m.largeSelected = 0
+	m.largeOffset = 0
+	m.largeMultiSelected = make(map[string]bool)
+	m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+}
+
+func (m *model) toggleLargeSort() {
+	m.largeByAge = !m.largeByAge
+	sortLargeFiles(m.largeFiles, m.largeByAge)
+	sortLargeFiles(m.largeAllFiles, m.largeByAge)
+	m.largeSelected = 0
+	m.largeOffset = 0
+	if m.largeByAge {
+		m.status = "Large files by age, oldest first"
+	} else {
+		m.status = "Large files by size"
+	}
+}
+
+// pageLarge moves the large-file cursor by whole screens.
+func (m *model) pageLarge(pages int) {
+	m.largeSelected += pages * calculateViewport(m.height)
+	m.clampLargeSelection()
+}
+
+// largeFilesSummary is the line above the large-file list: how many files,
+// the filter and order, and which rows are shown.
+func (m model) largeFilesSummary() string {
+	parts := []string{fmt.Sprintf("%s files", formatNumber(int64(len(m.largeFiles))))}
+	if m.largeExtFilter != "" {
+		parts = append(parts, extLabel(m.largeExtFilter)+" only")
+	}
+	if m.largeByAge {
+		parts = append(parts, "oldest first")
+	} else {
+		parts = append(parts, "largest first")
+	}
+	if viewport := calculateViewport(m.height); len(m.largeFiles) > viewport {
+		start := max(m.largeOffset, 0)
+		end := min(start+viewport, len(m.largeFiles))
+		parts = append(parts, fmt.Sprintf("%d-%d", start+1, end))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -4,6 +4,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
@@ -14,34 +16,18 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-)
-
-type dirEntry struct {
-	Name       string
-	Path       string
-	Size       int64
-	IsDir      bool
-	LastAccess time.Time
-}
-
-type fileEntry struct {
-	Name string
-	Path string
-	Size int64
-}
 
-type scanResult struct {
-	Entries    []dirEntry
-	LargeFiles []fileEntry
-	TotalSize  int64
-	TotalFiles int64
-}
+	"github.com/tw93/mole/internal/keymap"
+	"github.com/tw93/mole/pkg/scan"
+)
 
 type cacheEntry struct {
 	Entries    []dirEntry
 	LargeFiles []fileEntry
 	TotalSize  int64
 	TotalFiles int64
+	Issues     scanIssueReport
+	Owners     ownershipReport
 	ModTime    time.Time
 	ScanTime   time.Time
 }
@@ -52,6 +38,8 @@ type historyEntry struct {
 	LargeFiles    []fileEntry
 	TotalSize     int64
 	TotalFiles    int64
+	Issues        scanIssueReport
+	Owners        ownershipReport
 	Selected      int
 	EntryOffset   int
 	LargeSelected int
@@ -77,10 +65,12 @@ type overviewSizeMsg struct {
 type tickMsg time.Time
 
 type deleteProgressMsg struct {
-	done  bool
-	err   error
-	count int64
-	path  string
+	done      bool
+	err       error
+	count     int64
+	path      string
+	removed   []string // Paths gone from disk, even when others failed
+	untrashed []string // Paths Finder could not move to Trash
 }
 
 type model struct {
@@ -98,13 +88,40 @@ type model struct {
 	dirsScanned          *int64
 	bytesScanned         *int64
 	currentPath          *atomic.Value
+	scanStorage          *atomic.Value // storageNote of the latest scan
 	showLargeFiles       bool
 	isOverview           bool
 	deleteConfirm        bool
 	deleteTarget         *dirEntry
 	deleting             bool
+	deletingPermanent    bool // The running delete skips Trash
 	deleteCount          *int64
+	deletePermanent      bool   // Skip Trash for the pending delete
+	deleteNoTrash        bool   // Pending delete cannot go to Trash
+	deleteTyped          string // Typed confirmation for large or permanent deletes
+	policy               deletePolicy
+	compressConfirm      bool     // Asking for the archive destination
+	compressPaths        []string // Items to archive and replace
+	compressDest         string   // Typed destination folder, empty for next to original
+	compressing          bool
+	compressCount        *int64      // Files archived so far
+	moveConfirm          bool        // Asking where to relocate moveSource
+	moveSource           dirEntry    // Entry being moved or restored
+	moveDest             string      // Typed destination folder on another volume
+	moveSymlink          bool        // Leave a symlink at the original location
+	moveRestore          *moveRecord // Set when undoing an earlier move
+	moving               bool
+	showMoves            bool
+	moveRows             []moveRecord
+	moveRowSelected      int
+	moveBytes            *int64 // Bytes copied so far
+	compareMode          bool   // analyze --compare A B
+	compareLevel         compareLevel
+	compareHistory       []compareLevel
+	compareScanning      bool
+	compareDiffOnly      bool // Hide entries that match on both sides
 	cache                map[string]historyEntry
+	virtual              *virtualTree // analyze --from-stdin
 	largeSelected        int
 	largeOffset          int
 	overviewSizeCache    map[string]int64
@@ -113,13 +130,72 @@ type model struct {
 	overviewBytesScanned *int64
 	overviewCurrentPath  *string
 	overviewScanning     bool
-	overviewScanningSet  map[string]bool // Track which paths are currently being scanned
-	width                int             // Terminal width
-	height               int             // Terminal height
-	multiSelected        map[string]bool // Track multi-selected items by path (safer than index)
-	largeMultiSelected   map[string]bool // Track multi-selected large files by path (safer than index)
-	totalFiles           int64           // Total files found in current/last scan
-	lastTotalFiles       int64           // Total files from previous scan (for progress bar)
+	overviewScanningSet  map[string]bool      // Track which paths are currently being scanned
+	overviewMounts       map[string]mountInfo // Mounted filesystems listed below the roots
+	width                int                  // Terminal width
+	height               int                  // Terminal height
+	multiSelected        map[string]bool      // Track multi-selected items by path (safer than index)
+	largeMultiSelected   map[string]bool      // Track multi-selected large files by path (safer than index)
+	largeExtFilter       string               // Large-file list narrowed to this extension
+	largeAllFiles        []fileEntry          // Unfiltered large files while largeExtFilter is set
+	largeByAge           bool                 // Large files oldest first instead of largest
+	totalFiles           int64                // Total files found in current/last scan
+	lastTotalFiles       int64                // Total files from previous scan (for progress bar)
+	showReclaim          bool                 // Reclaimable-space view for the current root
+	reclaimScanning      bool
+	reclaimProjects      []reclaimProject
+	reclaimRows          []reclaimRow
+	reclaimSelected      int
+	reclaimOffset        int
+	reclaimMultiSelected map[string]bool // Selected artifacts by path
+	reclaimFound         *int64
+	reclaimCancel        context.CancelFunc
+	showJunk             bool // Junk finder for the current root
+	junkScanning         bool
+	junkGroups           []junkGroup
+	junkRows             []junkRow
+	junkSelected         int
+	junkOffset           int
+	junkMultiSelected    map[string]bool // Selected junk by path
+	junkFound            *int64
+	junkCancel           context.CancelFunc
+	showRecent           bool // Files written recently under the current root
+	recentScanning       bool
+	recentFiles          []fileEntry // Written within the widest window
+	recentAt             time.Time   // When recentFiles were collected
+	recentWindow         int         // Index into recentWindows
+	recentItems          []recentItem
+	recentSelected       int
+	recentOffset         int
+	recentChecked        *int64
+	recentCancel         context.CancelFunc
+	showGit              bool // Repository panel for the current root
+	gitLoading           bool
+	gitCancel            context.CancelFunc
+	gitInsights          *gitInsights
+	scanIssues           scanIssueReport // Paths the last scan could not read
+	showIssues           bool
+	issueSelected        int
+	issueOffset          int
+	scanProfile          scanProfile // Full speed unless --low-impact
+	scanOwners           ownershipReport
+	showOwners           bool
+	ownerSelected        int
+	ownerFilter          *uint32    // Entry list narrowed to this uid
+	ownerAllEntries      []dirEntry // Unfiltered entries while ownerFilter is set
+	ownerAllTotal        int64
+	lastClickPath        string // Row clicked last, a second click opens it
+	keys                 *keymap.Keymap
+	showHelp             bool              // Key binding overlay for the current screen
+	savedCursors         map[string]string // Entry to select per folder, from the last session
+	bookmarkNaming       bool              // Typing a name for a new bookmark
+	bookmarkName         string
+	showBookmarks        bool
+	bookmarkRows         []bookmarkRow
+	bookmarkSelected     int
+	showPreview          bool     // Side pane describing the selected entry
+	preview              *preview // Pane contents for previewPath, nil while reading
+	previewPath          string
 }
 
 func (m model) inOverviewMode() bool {
@@ -127,9 +203,38 @@ func (m model) inOverviewMode() bool {
 }
 
 func main() {
+	readOnly := flag.Bool("read-only", false, "disable deletion (for shared or demo machines)")
+	compare := flag.Bool("compare", false, "compare two directory trees: --compare A B")
+	lowImpact := flag.Bool("low-impact", false, "scan slowly at background priority, pausing on battery")
+	fresh := flag.Bool("fresh", false, "start at the overview instead of where the last session ended")
+	largeMin := flag.String("large-min", "50M", "smallest file kept in the large-file index, e.g. 200M or 1G")
+	fromStdin := flag.Bool("from-stdin", false, "browse the newline- or NUL-separated paths read from stdin")
+	flag.Parse()
+
+	minSize, err := parseByteSize(*largeMin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --large-min: %v\n", err)
+		os.Exit(2)
+	}
+	largeFileIndex = loadLargeFileIndex(minSize)
+
+	profile := scan.FullSpeed
+	if *lowImpact {
+		profile = scan.LowImpact
+	}
+
+	if *compare {
+		runCompare(flag.Args(), *readOnly, profile)
+		return
+	}
+	if *fromStdin {
+		runFromStdin(*readOnly, profile)
+		return
+	}
+
 	target := os.Getenv("MO_ANALYZE_PATH")
-	if target == "" && len(os.Args) > 1 {
-		target = os.Args[1]
+	if target == "" && flag.NArg() > 0 {
+		target = flag.Arg(0)
 	}
 
 	var abs string
@@ -153,7 +258,84 @@ func main() {
 	defer prefetchCancel()
 	go prefetchOverviewCache(prefetchCtx)
 
-	p := tea.NewProgram(newModel(abs, isOverview), tea.WithAltScreen())
+	m := newModel(abs, isOverview)
+	m.policy.readOnly = *readOnly
+	m.scanProfile = profile
+
+	// Resume the last session unless a path or --fresh was given; per-folder selections come back either way.
+	session, _ := loadSession()
+	restore := session
+	if *fresh || !isOverview {
+		restore.Path, restore.History = "", nil
+	}
+	m.restoreSession(restore)
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	final, err := p.Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "analyzer error: %v\n", err)
+		os.Exit(1)
+	}
+	if fm, ok := final.(model); ok {
+		_ = saveSession(sessionFromModel(fm, session))
+	}
+}
+
+// runCompare starts the analyzer in side-by-side mode for two trees.
+func runCompare(args []string, readOnly bool, profile scanProfile) {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: analyze --compare A B")
+		os.Exit(2)
+	}
+	var paths [2]string
+	for i, arg := range args {
+		abs, err := filepath.Abs(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot resolve %q: %v\n", arg, err)
+			os.Exit(1)
+		}
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "%s is not a directory\n", arg)
+			os.Exit(1)
+		}
+		paths[i] = abs
+	}
+
+	m := newModel(paths[0], false)
+	m.policy.readOnly = readOnly
+	m.scanProfile = profile
+	m.scanning = false
+	m.compareMode = true
+	m.compareLevel = compareLevel{PathA: paths[0], PathB: paths[1]}
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "analyzer error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runFromStdin browses the paths piped in as a tree. Keys are read from the
+// terminal, since stdin is taken; the session is not saved.
+func runFromStdin(readOnly bool, profile scanProfile) {
+	paths, err := readPathList(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot read paths from stdin: %v\n", err)
+		os.Exit(1)
+	}
+	if len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "no paths on stdin; usage: find ... | analyze --from-stdin")
+		os.Exit(2)
+	}
+
+	tree := newVirtualTree(paths)
+	m := newModel(tree.root, false)
+	m.policy.readOnly = readOnly
+	m.scanProfile = profile
+	m.virtual = tree
+	m.lastTotalFiles = int64(len(paths))
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithInputTTY())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "analyzer error: %v\n", err)
 		os.Exit(1)
@@ -176,6 +358,7 @@ func newModel(path string, isOverview bool) model {
 		dirsScanned:          &dirsScanned,
 		bytesScanned:         &bytesScanned,
 		currentPath:          currentPath,
+		scanStorage:          &atomic.Value{},
 		showLargeFiles:       false,
 		isOverview:           isOverview,
 		cache:                make(map[string]historyEntry),
@@ -187,6 +370,8 @@ func newModel(path string, isOverview bool) model {
 		overviewScanningSet:  make(map[string]bool),
 		multiSelected:        make(map[string]bool),
 		largeMultiSelected:   make(map[string]bool),
+		policy:               loadDeletePolicy(false),
+		keys:                 loadKeys(),
 	}
 
 	if isOverview {
@@ -212,28 +397,6 @@ func newModel(path string, isOverview bool) model {
 	return m
 }
 
-func createOverviewEntries() []dirEntry {
-	home := os.Getenv("HOME")
-	entries := []dirEntry{}
-
-	// Separate Home and ~/Library to avoid double counting.
-	if home != "" {
-		entries = append(entries, dirEntry{Name: "Home", Path: home, IsDir: true, Size: -1})
-
-		userLibrary := filepath.Join(home, "Library")
-		if _, err := os.Stat(userLibrary); err == nil {
-			entries = append(entries, dirEntry{Name: "App Library", Path: userLibrary, IsDir: true, Size: -1})
-		}
-	}
-
-	entries = append(entries,
-		dirEntry{Name: "Applications", Path: "/Applications", IsDir: true, Size: -1},
-		dirEntry{Name: "System Library", Path: "/Library", IsDir: true, Size: -1},
-	)
-
-	return entries
-}
-
 func (m *model) hydrateOverviewEntries() {
 	m.entries = createOverviewEntries()
 	if m.overviewSizeCache == nil {
@@ -244,17 +407,22 @@ func (m *model) hydrateOverviewEntries() {
 			m.entries[i].Size = size
 			continue
 		}
-		if size, err := loadOverviewCachedSize(m.entries[i].Path); err == nil {
+		if size, _, err := loadOverviewCachedSize(m.entries[i].Path); err == nil {
 			m.entries[i].Size = size
 			m.overviewSizeCache[m.entries[i].Path] = size
 		}
 	}
-	m.totalSize = sumKnownEntrySizes(m.entries)
+	m.appendOverviewMounts()
+	m.totalSize = overviewTotal(m.entries, m.overviewMounts)
 }
 
 func (m *model) sortOverviewEntriesBySize() {
-	// Stable sort by size.
+	// Stable sort by size, mounts stay below the roots.
 	sort.SliceStable(m.entries, func(i, j int) bool {
+		mi, mj := m.isOverviewMount(m.entries[i].Path), m.isOverviewMount(m.entries[j].Path)
+		if mi != mj {
+			return mj
+		}
 		return m.entries[i].Size > m.entries[j].Size
 	})
 }
@@ -287,7 +455,7 @@ func (m *model) scheduleOverviewScans() tea.Cmd {
 	for _, idx := range pendingIndices {
 		entry := m.entries[idx]
 		m.overviewScanningSet[entry.Path] = true
-		cmd := scanOverviewPathCmd(entry.Path, idx)
+		cmd := scanOverviewPathCmd(entry.Path, idx, m.scanProfile)
 		cmds = append(cmds, cmd)
 	}
 
@@ -325,6 +493,9 @@ func (m *model) getScanProgress() (files, dirs, bytes int64) {
 }
 
 func (m model) Init() tea.Cmd {
+	if m.compareMode {
+		return m.startCompareScan(m.compareLevel.PathA, m.compareLevel.PathB)
+	}
 	if m.inOverviewMode() {
 		return m.scheduleOverviewScans()
 	}
@@ -333,12 +504,20 @@ func (m model) Init() tea.Cmd {
 
 func (m model) scanCmd(path string) tea.Cmd {
 	return func() tea.Msg {
+		if m.virtual != nil {
+			if result, ok := m.virtual.result(path, m.filesScanned, m.bytesScanned); ok {
+				return scanResultMsg{path: path, result: result}
+			}
+		}
+
 		if cached, err := loadCacheFromDisk(path); err == nil {
 			result := scanResult{
 				Entries:    cached.Entries,
 				LargeFiles: cached.LargeFiles,
 				TotalSize:  cached.TotalSize,
 				TotalFiles: cached.TotalFiles,
+				Issues:     cached.Issues,
+				Owners:     cached.Owners,
 			}
 			return scanResultMsg{path: path, result: result, err: nil}
 		}
@@ -349,12 +528,14 @@ func (m model) scanCmd(path string) tea.Cmd {
 				LargeFiles: stale.LargeFiles,
 				TotalSize:  stale.TotalSize,
 				TotalFiles: stale.TotalFiles,
+				Issues:     stale.Issues,
+				Owners:     stale.Owners,
 			}
 			return scanResultMsg{path: path, result: result, err: nil, stale: true}
 		}
 
 		v, err, _ := scanGroup.Do(path, func() (any, error) {
-			return scanPathConcurrent(path, m.filesScanned, m.dirsScanned, m.bytesScanned, m.currentPath)
+			return scanPathWithOptions(path, scanOptions{EntryLimit: maxEntries, Profile: m.scanProfile, Storage: m.scanStorage}, m.filesScanned, m.dirsScanned, m.bytesScanned, m.currentPath)
 		})
 
 		if err != nil {
@@ -367,6 +548,7 @@ func (m model) scanCmd(path string) tea.Cmd {
 			if err := saveCacheToDisk(p, r); err != nil {
 				_ = err // Cache save failure is not critical
 			}
+			_ = saveLargeFileIndex()
 		}(path, result)
 
 		return scanResultMsg{path: path, result: result, err: nil}
@@ -376,7 +558,7 @@ func (m model) scanCmd(path string) tea.Cmd {
 func (m model) scanFreshCmd(path string) tea.Cmd {
 	return func() tea.Msg {
 		v, err, _ := scanGroup.Do(path, func() (any, error) {
-			return scanPathConcurrent(path, m.filesScanned, m.dirsScanned, m.bytesScanned, m.currentPath)
+			return scanPathWithOptions(path, scanOptions{EntryLimit: maxEntries, Profile: m.scanProfile, Storage: m.scanStorage}, m.filesScanned, m.dirsScanned, m.bytesScanned, m.currentPath)
 		})
 
 		if err != nil {
@@ -388,6 +570,7 @@ func (m model) scanFreshCmd(path string) tea.Cmd {
 			if err := saveCacheToDisk(p, r); err != nil {
 				_ = err
 			}
+			_ = saveLargeFileIndex()
 		}(path, result)
 
 		return scanResultMsg{path: path, result: result}
@@ -401,9 +584,15 @@ func tickCmd() tea.Cmd {
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return syncPreview(m.update(msg))
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.updateKey(msg)
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -411,6 +600,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case deleteProgressMsg:
 		if msg.done {
 			m.deleting = false
+			if m.virtual != nil {
+				m.virtual.forget(msg.removed)
+			}
+			if len(msg.untrashed) > 0 {
+				for _, path := range msg.removed {
+					m.removePathFromView(path)
+					invalidateCache(path)
+				}
+				m.offerPermanentDelete(msg.untrashed, msg.err)
+				return m, nil
+			}
+			m.deleteTarget = nil
 			m.multiSelected = make(map[string]bool)
 			m.largeMultiSelected = make(map[string]bool)
 			if msg.err != nil {
@@ -420,31 +621,88 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.removePathFromView(msg.path)
 					invalidateCache(msg.path)
 				}
-				invalidateCache(m.path)
-				m.status = fmt.Sprintf("Deleted %d items", msg.count)
-				for i := range m.history {
-					m.history[i].Dirty = true
+				if m.showReclaim {
+					m.setReclaimProjects(pruneReclaimProjects(m.reclaimProjects))
+					m.reclaimMultiSelected = make(map[string]bool)
 				}
-				for path := range m.cache {
-					entry := m.cache[path]
-					entry.Dirty = true
-					m.cache[path] = entry
+				if m.showJunk {
+					m.setJunkGroups(pruneJunk(m.junkGroups))
+					m.junkMultiSelected = make(map[string]bool)
 				}
-				m.scanning = true
-				atomic.StoreInt64(m.filesScanned, 0)
-				atomic.StoreInt64(m.dirsScanned, 0)
-				atomic.StoreInt64(m.bytesScanned, 0)
-				if m.currentPath != nil {
-					m.currentPath.Store("")
+				if m.showRecent {
+					m.setRecentFiles(m.recentFiles)
 				}
-				return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+				m.status = fmt.Sprintf("Deleted %d items", msg.count)
+				return m, m.rescanAfterChange()
 			}
 		}
 		return m, nil
+	case compressResultMsg:
+		return m.handleCompressResult(msg)
+	case moveResultMsg:
+		return m.handleMoveResult(msg)
+	case compareResultMsg:
+		return m.handleCompareResult(msg)
+	case previewMsg:
+		return m.handlePreview(msg)
+	case reclaimResultMsg:
+		if !m.showReclaim || msg.root != m.path || errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		stopScan(&m.reclaimCancel)
+		m.reclaimScanning = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Reclaim scan failed: %v", msg.err)
+			return m, nil
+		}
+		m.setReclaimProjects(msg.projects)
+		m.updateReclaimStatus()
+		return m, nil
+	case junkResultMsg:
+		if !m.showJunk || msg.root != m.path || errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		stopScan(&m.junkCancel)
+		m.junkScanning = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Junk scan failed: %v", msg.err)
+			return m, nil
+		}
+		m.setJunkGroups(msg.groups)
+		m.updateJunkStatus()
+		return m, nil
+	case recentResultMsg:
+		if !m.showRecent || msg.root != m.path || errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		stopScan(&m.recentCancel)
+		m.recentScanning = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Recent scan failed: %v", msg.err)
+			return m, nil
+		}
+		m.recentAt = msg.at
+		m.setRecentFiles(msg.files)
+		m.updateRecentStatus()
+		return m, nil
+	case gitInsightsMsg:
+		if !m.showGit || msg.root != m.path || errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		stopScan(&m.gitCancel)
+		m.gitLoading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Repository inspection failed: %v", msg.err)
+			return m, nil
+		}
+		m.gitInsights = msg.insights
+		m.status = fmt.Sprintf(".git uses %s", humanizeBytes(msg.insights.GitDirSize))
+		return m, nil
 	case scanResultMsg:
 		if msg.path != "" && msg.path != m.path {
 			return m, nil
 		}
+		cleanableMemo.Clear()
 		m.scanning = false
 		if msg.err != nil {
 			m.status = fmt.Sprintf("Scan failed: %v", msg.err)
@@ -457,13 +715,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		m.entries = filteredEntries
-		m.largeFiles = msg.result.LargeFiles
+		m.setLargeFiles(msg.result.LargeFiles)
 		m.totalSize = msg.result.TotalSize
 		m.totalFiles = msg.result.TotalFiles
+		m.scanIssues = msg.result.Issues
+		m.scanOwners = msg.result.Owners
+		m.dropOwnerFilter()
 		m.clampEntrySelection()
 		m.clampLargeSelection()
+		m.applySavedCursor()
 		m.cache[m.path] = cacheSnapshot(m)
-		if m.totalSize > 0 {
+		if m.totalSize > 0 && m.virtual == nil {
 			if m.overviewSizeCache == nil {
 				m.overviewSizeCache = make(map[string]int64)
 			}
@@ -489,6 +751,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+		if msg.result.Strategy != scan.StrategyAuto {
+			m.status += ", " + storageLabel(msg.result.Device, msg.result.Strategy)
+		}
+		if msg.result.Dropped > 0 {
+			m.status += fmt.Sprintf(", %d results not listed", msg.result.Dropped)
+		}
 		return m, nil
 	case overviewSizeMsg:
 		delete(m.overviewScanningSet, msg.Path)
@@ -511,7 +779,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					break
 				}
 			}
-			m.totalSize = sumKnownEntrySizes(m.entries)
+			m.totalSize = overviewTotal(m.entries, m.overviewMounts)
 
 			if msg.Err != nil {
 				m.status = fmt.Sprintf("Unable to measure %s: %v", displayPath(msg.Path), msg.Err)
@@ -531,14 +799,28 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		}
-		if m.scanning || m.deleting || (m.inOverviewMode() && (m.overviewScanning || hasPending)) {
+		if m.scanning || m.deleting || m.compressing || m.moving || m.compareScanning || m.reclaimScanning || m.junkScanning || m.recentScanning || m.gitLoading || (m.inOverviewMode() && (m.overviewScanning || hasPending)) {
 			m.spinner = (m.spinner + 1) % len(spinnerFrames)
 			if m.deleting && m.deleteCount != nil {
 				count := atomic.LoadInt64(m.deleteCount)
-				if count > 0 {
+				if count > 0 && m.deletingPermanent {
+					m.status = fmt.Sprintf("Permanently deleting... %s items", formatNumber(count))
+				} else if count > 0 {
 					m.status = fmt.Sprintf("Moving to Trash... %s items", formatNumber(count))
 				}
 			}
+			if m.compressing && m.compressCount != nil {
+				count := atomic.LoadInt64(m.compressCount)
+				if count > 0 {
+					m.status = fmt.Sprintf("Compressing... %s files", formatNumber(count))
+				}
+			}
+			if m.moving && m.moveBytes != nil {
+				copied := atomic.LoadInt64(m.moveBytes)
+				if copied > 0 {
+					m.status = fmt.Sprintf("Copying... %s of %s", humanizeBytes(copied), humanizeBytes(m.moveSource.Size))
+				}
+			}
 			return m, tickCmd()
 		}
 		return m, nil
@@ -548,70 +830,77 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.compareMode {
+		return m.updateCompareKey(msg)
+	}
+
 	// Delete confirm flow.
 	if m.deleteConfirm {
-		switch msg.String() {
-		case "enter":
-			m.deleteConfirm = false
-			m.deleting = true
-			var deleteCount int64
-			m.deleteCount = &deleteCount
-
-			// Collect paths (safer than indices).
-			var pathsToDelete []string
-			if m.showLargeFiles {
-				if len(m.largeMultiSelected) > 0 {
-					for path := range m.largeMultiSelected {
-						pathsToDelete = append(pathsToDelete, path)
-					}
-				} else if m.deleteTarget != nil {
-					pathsToDelete = append(pathsToDelete, m.deleteTarget.Path)
-				}
-			} else {
-				if len(m.multiSelected) > 0 {
-					for path := range m.multiSelected {
-						pathsToDelete = append(pathsToDelete, path)
-					}
-				} else if m.deleteTarget != nil {
-					pathsToDelete = append(pathsToDelete, m.deleteTarget.Path)
-				}
-			}
+		return m.updateDeleteConfirmKey(msg)
+	}
+	if m.compressConfirm {
+		return m.updateCompressConfirmKey(msg)
+	}
+	if m.moveConfirm {
+		return m.updateMoveConfirmKey(msg)
+	}
+	if m.bookmarkNaming {
+		return m.updateBookmarkPromptKey(msg)
+	}
 
-			m.deleteTarget = nil
-			if len(pathsToDelete) == 0 {
-				m.deleting = false
-				m.status = "Nothing to delete"
-				return m, nil
-			}
+	// Quit works the same from every panel below.
+	if m.keyAction(msg) == actionQuit {
+		return m, tea.Quit
+	}
 
-			if len(pathsToDelete) == 1 {
-				targetPath := pathsToDelete[0]
-				m.status = fmt.Sprintf("Deleting %s...", filepath.Base(targetPath))
-				return m, tea.Batch(deletePathCmd(targetPath, m.deleteCount), tickCmd())
-			}
+	if m.showReclaim {
+		return m.updateReclaimKey(msg)
+	}
+	if m.showJunk {
+		return m.updateJunkKey(msg)
+	}
+	if m.showRecent {
+		return m.updateRecentKey(msg)
+	}
+	if m.showGit {
+		return m.updateGitKey(msg)
+	}
+	if m.showIssues {
+		return m.updateIssuesKey(msg)
+	}
+	if m.showOwners {
+		return m.updateOwnersKey(msg)
+	}
+	if m.showBookmarks {
+		return m.updateBookmarksKey(msg)
+	}
+	if m.showMoves {
+		return m.updateMovesKey(msg)
+	}
+	if m.showHelp {
+		return m.updateHelpKey(msg)
+	}
 
-			m.status = fmt.Sprintf("Deleting %d items...", len(pathsToDelete))
-			return m, tea.Batch(deleteMultiplePathsCmd(pathsToDelete, m.deleteCount), tickCmd())
-		case "esc", "q":
-			m.status = "Cancelled"
-			m.deleteConfirm = false
-			m.deleteTarget = nil
-			return m, nil
-		default:
+	switch m.keyAction(msg) {
+	case actionHelp:
+		m.showHelp = true
+		return m, nil
+	case actionClose:
+		if m.showLargeFiles && m.largeExtFilter != "" {
+			m.clearLargeExtFilter()
 			return m, nil
 		}
-	}
-
-	switch msg.String() {
-	case "q", "ctrl+c", "Q":
-		return m, tea.Quit
-	case "esc":
 		if m.showLargeFiles {
 			m.showLargeFiles = false
 			return m, nil
 		}
+		if m.ownerFilter != nil {
+			m.clearOwnerFilter()
+			m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+			return m, nil
+		}
 		return m, tea.Quit
-	case "up", "k", "K":
+	case actionUp:
 		if m.showLargeFiles {
 			if m.largeSelected > 0 {
 				m.largeSelected--
@@ -625,80 +914,34 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.offset = m.selected
 			}
 		}
-	case "down", "j", "J":
+	case actionDown:
 		if m.showLargeFiles {
 			if m.largeSelected < len(m.largeFiles)-1 {
 				m.largeSelected++
-				viewport := calculateViewport(m.height, true)
+				viewport := calculateViewport(m.height)
 				if m.largeSelected >= m.largeOffset+viewport {
 					m.largeOffset = m.largeSelected - viewport + 1
 				}
 			}
 		} else if len(m.entries) > 0 && m.selected < len(m.entries)-1 {
 			m.selected++
-			viewport := calculateViewport(m.height, false)
+			viewport := calculateViewport(m.height)
 			if m.selected >= m.offset+viewport {
 				m.offset = m.selected - viewport + 1
 			}
 		}
-	case "enter", "right", "l", "L":
+	case actionEnter:
 		if m.showLargeFiles {
 			return m, nil
 		}
 		return m.enterSelectedDir()
-	case "b", "left", "h", "B", "H":
+	case actionBack:
 		if m.showLargeFiles {
 			m.showLargeFiles = false
 			return m, nil
 		}
-		if len(m.history) == 0 {
-			if !m.inOverviewMode() {
-				return m, m.switchToOverviewMode()
-			}
-			return m, nil
-		}
-		last := m.history[len(m.history)-1]
-		m.history = m.history[:len(m.history)-1]
-		m.path = last.Path
-		m.selected = last.Selected
-		m.offset = last.EntryOffset
-		m.largeSelected = last.LargeSelected
-		m.largeOffset = last.LargeOffset
-		m.isOverview = last.IsOverview
-		if last.Dirty {
-			// On overview return, refresh cached entries.
-			if last.IsOverview {
-				m.hydrateOverviewEntries()
-				m.totalSize = sumKnownEntrySizes(m.entries)
-				m.status = "Ready"
-				m.scanning = false
-				if nextPendingOverviewIndex(m.entries) >= 0 {
-					m.overviewScanning = true
-					return m, m.scheduleOverviewScans()
-				}
-				return m, nil
-			}
-			m.status = "Scanning..."
-			m.scanning = true
-			return m, tea.Batch(m.scanCmd(m.path), tickCmd())
-		}
-		m.entries = last.Entries
-		m.largeFiles = last.LargeFiles
-		m.totalSize = last.TotalSize
-		m.clampEntrySelection()
-		m.clampLargeSelection()
-		if len(m.entries) == 0 {
-			m.selected = 0
-		} else if m.selected >= len(m.entries) {
-			m.selected = len(m.entries) - 1
-		}
-		if m.selected < 0 {
-			m.selected = 0
-		}
-		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
-		m.scanning = false
-		return m, nil
-	case "r", "R":
+		return m.goBack()
+	case actionRefresh:
 		m.multiSelected = make(map[string]bool)
 		m.largeMultiSelected = make(map[string]bool)
 
@@ -713,7 +956,9 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.hydrateOverviewEntries() // Reset sizes to pending
 
 			for i := range m.entries {
-				m.entries[i].Size = -1
+				if !m.isOverviewMount(m.entries[i].Path) {
+					m.entries[i].Size = -1
+				}
 			}
 			m.totalSize = 0
 
@@ -723,6 +968,9 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 		invalidateCache(m.path)
+		if m.virtual != nil {
+			m.virtual.invalidate()
+		}
 		m.status = "Refreshing..."
 		m.scanning = true
 		if m.totalFiles > 0 {
@@ -735,7 +983,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.currentPath.Store("")
 		}
 		return m, tea.Batch(m.scanCmd(m.path), tickCmd())
-	case "t", "T":
+	case actionLargeFiles:
 		if !m.inOverviewMode() {
 			m.showLargeFiles = !m.showLargeFiles
 			if m.showLargeFiles {
@@ -747,7 +995,67 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
 		}
-	case "o", "O":
+	case actionPageUp, actionPageDown:
+		if m.showLargeFiles {
+			if m.keyAction(msg) == actionPageUp {
+				m.pageLarge(-1)
+			} else {
+				m.pageLarge(1)
+			}
+		}
+	case actionLargeSort:
+		if m.showLargeFiles {
+			m.toggleLargeSort()
+		}
+	case actionLargeType:
+		if m.showLargeFiles {
+			m.cycleLargeExtFilter()
+		}
+	case actionPreview:
+		m.togglePreview()
+	case actionReclaim:
+		if !m.inOverviewMode() && !m.scanning {
+			return m, m.openReclaimView()
+		}
+	case actionJunk:
+		if !m.inOverviewMode() && !m.scanning {
+			return m, m.openJunkView()
+		}
+	case actionRecent:
+		if !m.inOverviewMode() && !m.scanning {
+			return m, m.openRecentView()
+		}
+	case actionGit:
+		if !m.inOverviewMode() && !m.scanning && hasGitRepo(m.path) {
+			return m, m.openGitView()
+		}
+	case actionIssues:
+		if !m.inOverviewMode() && !m.scanning {
+			m.openIssuesView()
+		}
+	case actionOwners:
+		if !m.inOverviewMode() && !m.scanning {
+			m.openOwnersView()
+		}
+	case actionBookmark:
+		if !m.scanning {
+			m.openBookmarkPrompt()
+		}
+	case actionBookmarks:
+		m.openBookmarksView()
+	case actionCompress:
+		if !m.scanning && !m.deleting && !m.compressing && !m.moving {
+			m.openCompressConfirm()
+		}
+	case actionMove:
+		if !m.scanning && !m.deleting && !m.compressing && !m.moving {
+			m.openMoveConfirm()
+		}
+	case actionMoves:
+		if !m.scanning && !m.deleting && !m.compressing && !m.moving {
+			m.openMovesView()
+		}
+	case actionOpen:
 		// Open selected entries (multi-select aware).
 		const maxBatchOpen = 20
 		if m.showLargeFiles {
@@ -801,7 +1109,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.status = fmt.Sprintf("Opening %s...", selected.Name)
 			}
 		}
-	case "f", "F":
+	case actionReveal:
 		// Reveal in Finder (multi-select aware).
 		const maxBatchReveal = 20
 		if m.showLargeFiles {
@@ -855,7 +1163,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.status = fmt.Sprintf("Showing %s in Finder...", selected.Name)
 			}
 		}
-	case " ":
+	case actionSelect:
 		// Toggle multi-select (paths as keys).
 		if m.showLargeFiles {
 			if len(m.largeFiles) > 0 && m.largeSelected < len(m.largeFiles) {
@@ -910,7 +1218,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
 			}
 		}
-	case "delete", "backspace":
+	case actionDelete:
 		if m.showLargeFiles {
 			if len(m.largeFiles) > 0 {
 				if len(m.largeMultiSelected) > 0 {
@@ -959,15 +1267,62 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.deleteTarget = &selected
 			}
 		}
+		m.prepareDeleteConfirm()
 	}
 	return m, nil
 }
 
+// rescanAfterChange marks cached results dirty and rescans the current
+// directory after items were removed or replaced on disk.
+func (m *model) rescanAfterChange() tea.Cmd {
+	invalidateCache(m.path)
+	if m.virtual != nil {
+		m.virtual.invalidate()
+	}
+	for i := range m.history {
+		m.history[i].Dirty = true
+	}
+	for path := range m.cache {
+		entry := m.cache[path]
+		entry.Dirty = true
+		m.cache[path] = entry
+	}
+	m.scanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		m.currentPath.Store("")
+	}
+	return tea.Batch(m.scanCmd(m.path), tickCmd())
+}
+
+// stopScan cancels a sub-view's background walk, if one is running.
+func stopScan(cancel *context.CancelFunc) {
+	if *cancel != nil {
+		(*cancel)()
+		*cancel = nil
+	}
+}
+
 func (m *model) switchToOverviewMode() tea.Cmd {
 	m.isOverview = true
 	m.path = "/"
 	m.scanning = false
 	m.showLargeFiles = false
+	stopScan(&m.reclaimCancel)
+	m.showReclaim = false
+	stopScan(&m.junkCancel)
+	m.showJunk = false
+	stopScan(&m.recentCancel)
+	m.showRecent = false
+	stopScan(&m.gitCancel)
+	m.showGit = false
+	m.showIssues = false
+	m.showOwners = false
+	m.showHelp = false
+	m.showBookmarks = false
+	m.showMoves = false
 	m.largeFiles = nil
 	m.largeSelected = 0
 	m.largeOffset = 0
@@ -984,56 +1339,122 @@ func (m *model) switchToOverviewMode() tea.Cmd {
 	return tea.Batch(cmd, tickCmd())
 }
 
-func (m model) enterSelectedDir() (tea.Model, tea.Cmd) {
-	if len(m.entries) == 0 {
-		return m, nil
-	}
-	selected := m.entries[m.selected]
-	if selected.IsDir {
-		if len(m.history) == 0 || m.history[len(m.history)-1].Path != m.path {
-			m.history = append(m.history, snapshotFromModel(m))
+// goBack returns to the previous history entry, or to the overview at the top.
+func (m model) goBack() (tea.Model, tea.Cmd) {
+	if len(m.history) == 0 {
+		if m.virtual != nil {
+			// The listed paths have no overview above them.
+			return m, nil
 		}
-		m.path = selected.Path
-		m.selected = 0
-		m.offset = 0
-		m.status = "Scanning..."
-		m.scanning = true
-		m.isOverview = false
-		m.multiSelected = make(map[string]bool)
-		m.largeMultiSelected = make(map[string]bool)
-
-		atomic.StoreInt64(m.filesScanned, 0)
-		atomic.StoreInt64(m.dirsScanned, 0)
-		atomic.StoreInt64(m.bytesScanned, 0)
-		if m.currentPath != nil {
-			m.currentPath.Store("")
+		if !m.inOverviewMode() {
+			return m, m.switchToOverviewMode()
 		}
-
-		if cached, ok := m.cache[m.path]; ok && !cached.Dirty {
-			m.entries = slices.Clone(cached.Entries)
-			m.largeFiles = slices.Clone(cached.LargeFiles)
-			m.totalSize = cached.TotalSize
-			m.totalFiles = cached.TotalFiles
-			m.selected = cached.Selected
-			m.offset = cached.EntryOffset
-			m.largeSelected = cached.LargeSelected
-			m.largeOffset = cached.LargeOffset
-			m.clampEntrySelection()
-			m.clampLargeSelection()
-			m.status = fmt.Sprintf("Cached view for %s", displayPath(m.path))
+		return m, nil
+	}
+	last := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	m.path = last.Path
+	m.selected = last.Selected
+	m.offset = last.EntryOffset
+	m.largeSelected = last.LargeSelected
+	m.largeOffset = last.LargeOffset
+	m.isOverview = last.IsOverview
+	if last.Dirty {
+		// On overview return, refresh cached entries.
+		if last.IsOverview {
+			m.hydrateOverviewEntries()
+			m.status = "Ready"
 			m.scanning = false
+			if nextPendingOverviewIndex(m.entries) >= 0 {
+				m.overviewScanning = true
+				return m, m.scheduleOverviewScans()
+			}
 			return m, nil
 		}
-		m.lastTotalFiles = 0
-		if total, err := peekCacheTotalFiles(m.path); err == nil && total > 0 {
-			m.lastTotalFiles = total
-		}
+		m.status = "Scanning..."
+		m.scanning = true
 		return m, tea.Batch(m.scanCmd(m.path), tickCmd())
 	}
+	m.entries = last.Entries
+	m.setLargeFiles(last.LargeFiles)
+	m.totalSize = last.TotalSize
+	m.scanIssues = last.Issues
+	m.scanOwners = last.Owners
+	m.dropOwnerFilter()
+	m.clampEntrySelection()
+	m.clampLargeSelection()
+	if len(m.entries) == 0 {
+		m.selected = 0
+	} else if m.selected >= len(m.entries) {
+		m.selected = len(m.entries) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+	m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+	m.scanning = false
+	return m, nil
+}
+
+func (m model) enterSelectedDir() (tea.Model, tea.Cmd) {
+	if len(m.entries) == 0 {
+		return m, nil
+	}
+	selected := m.entries[m.selected]
+	if selected.IsDir {
+		return m.openDir(selected.Path)
+	}
 	m.status = fmt.Sprintf("File: %s, %s", selected.Name, humanizeBytes(selected.Size))
 	return m, nil
 }
 
+// openDir pushes the current view onto the history and shows path, from the
+// in-memory cache when it is still valid.
+func (m model) openDir(path string) (tea.Model, tea.Cmd) {
+	if len(m.history) == 0 || m.history[len(m.history)-1].Path != m.path {
+		m.history = append(m.history, snapshotFromModel(m))
+	}
+	m.dropOwnerFilter()
+	m.path = path
+	m.selected = 0
+	m.offset = 0
+	m.status = "Scanning..."
+	m.scanning = true
+	m.isOverview = false
+	m.multiSelected = make(map[string]bool)
+	m.largeMultiSelected = make(map[string]bool)
+
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		m.currentPath.Store("")
+	}
+
+	if cached, ok := m.cache[m.path]; ok && !cached.Dirty {
+		m.entries = slices.Clone(cached.Entries)
+		m.setLargeFiles(slices.Clone(cached.LargeFiles))
+		m.totalSize = cached.TotalSize
+		m.totalFiles = cached.TotalFiles
+		m.scanIssues = cached.Issues
+		m.scanOwners = cached.Owners
+		m.selected = cached.Selected
+		m.offset = cached.EntryOffset
+		m.largeSelected = cached.LargeSelected
+		m.largeOffset = cached.LargeOffset
+		m.clampEntrySelection()
+		m.clampLargeSelection()
+		m.status = fmt.Sprintf("Cached view for %s", displayPath(m.path))
+		m.scanning = false
+		return m, nil
+	}
+	m.lastTotalFiles = 0
+	if total, err := peekCacheTotalFiles(m.path); err == nil && total > 0 {
+		m.lastTotalFiles = total
+	}
+	return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+}
+
 func (m *model) clampEntrySelection() {
 	if len(m.entries) == 0 {
 		m.selected = 0
@@ -1046,7 +1467,7 @@ func (m *model) clampEntrySelection() {
 	if m.selected < 0 {
 		m.selected = 0
 	}
-	viewport := calculateViewport(m.height, false)
+	viewport := calculateViewport(m.height)
 	maxOffset := max(len(m.entries)-viewport, 0)
 	if m.offset > maxOffset {
 		m.offset = maxOffset
@@ -1071,7 +1492,7 @@ func (m *model) clampLargeSelection() {
 	if m.largeSelected < 0 {
 		m.largeSelected = 0
 	}
-	viewport := calculateViewport(m.height, true)
+	viewport := calculateViewport(m.height)
 	maxOffset := max(len(m.largeFiles)-viewport, 0)
 	if m.largeOffset > maxOffset {
 		m.largeOffset = maxOffset
@@ -1084,16 +1505,6 @@ func (m *model) clampLargeSelection() {
 	}
 }
 
-func sumKnownEntrySizes(entries []dirEntry) int64 {
-	var total int64
-	for _, entry := range entries {
-		if entry.Size > 0 {
-			total += entry.Size
-		}
-	}
-	return total
-}
-
 func nextPendingOverviewIndex(entries []dirEntry) int {
 	for i, entry := range entries {
 		if entry.Size < 0 {
@@ -1128,12 +1539,9 @@ func (m *model) removePathFromView(path string) {
 		}
 	}
 
-	for i := 0; i < len(m.largeFiles); i++ {
-		if m.largeFiles[i].Path == path {
-			m.largeFiles = append(m.largeFiles[:i], m.largeFiles[i+1:]...)
-			break
-		}
-	}
+	isPath := func(f fileEntry) bool { return f.Path == path }
+	m.largeFiles = slices.DeleteFunc(m.largeFiles, isPath)
+	m.largeAllFiles = slices.DeleteFunc(m.largeAllFiles, isPath)
 
 	if removedSize > 0 {
 		if removedSize > m.totalSize {
@@ -1146,9 +1554,9 @@ func (m *model) removePathFromView(path string) {
 	m.clampLargeSelection()
 }
 
-func scanOverviewPathCmd(path string, index int) tea.Cmd {
+func scanOverviewPathCmd(path string, index int, profile scanProfile) tea.Cmd {
 	return func() tea.Msg {
-		size, err := measureOverviewSize(path)
+		size, err := measureOverviewSizeWithProfile(path, profile)
 		return overviewSizeMsg{
 			Path:  path,
 			Index: index,
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	mouseScrollLines = 3
+	headerRow        = 1                // View starts with a blank line.
+	headerTitle      = "Analyze Disk  " // Text before the path in the header.
+)
+
+// mouseEnabled reports whether the current screen handles clicks and scrolling.
+// Prompts and secondary panels stay keyboard-only.
+func (m model) mouseEnabled() bool {
+	return !m.compareMode && !m.scanning && !m.deleting && !m.compressing && !m.moving &&
+		!m.deleteConfirm && !m.compressConfirm && !m.moveConfirm &&
+		!m.showReclaim && !m.showJunk && !m.showRecent && !m.showGit && !m.showIssues && !m.showOwners &&
+		!m.showHelp && !m.showBookmarks && !m.showMoves && !m.bookmarkNaming
+}
+
+// listTop is the screen row of the first list entry, matching View's header layout.
+func (m model) listTop() int {
+	if m.inOverviewMode() {
+		return headerRow + 3 // Title, "Select a location", blank line.
+	}
+	top := headerRow + 2 // Header, blank line.
+	if m.scanIssues.Total > 0 {
+		top++
+	}
+	if m.showLargeFiles && len(m.largeFiles) > 0 {
+		top++ // Large-file summary line.
+	}
+	return top
+}
+
+func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if !m.mouseEnabled() {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.scrollList(-mouseScrollLines)
+		return m, nil
+	case tea.MouseButtonWheelDown:
+		m.scrollList(mouseScrollLines)
+		return m, nil
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+	default:
+		return m, nil
+	}
+
+	if m.showPreview && m.width > 0 && msg.X >= m.listWidth() {
+		return m, nil // The preview pane has nothing to click.
+	}
+	if msg.Y == headerRow && !m.inOverviewMode() {
+		return m.clickHeader(msg.X)
+	}
+	return m.clickRow(msg.Y - m.listTop())
+}
+
+// scrollList moves the viewport and keeps the selection visible.
+func (m *model) scrollList(delta int) {
+	if m.showLargeFiles {
+		viewport := calculateViewport(m.height)
+		maxOffset := max(len(m.largeFiles)-viewport, 0)
+		m.largeOffset = min(max(m.largeOffset+delta, 0), maxOffset)
+		m.largeSelected = min(max(m.largeSelected, m.largeOffset), m.largeOffset+viewport-1)
+		m.clampLargeSelection()
+		return
+	}
+	viewport := calculateViewport(m.height)
+	maxOffset := max(len(m.entries)-viewport, 0)
+	m.offset = min(max(m.offset+delta, 0), maxOffset)
+	m.selected = min(max(m.selected, m.offset), m.offset+viewport-1)
+	m.clampEntrySelection()
+}
+
+// clickRow selects the row under the pointer. Clicking the row again, as in a double-click, opens it.
+func (m model) clickRow(row int) (tea.Model, tea.Cmd) {
+	if row < 0 {
+		return m, nil
+	}
+
+	if m.showLargeFiles {
+		idx := m.largeOffset + row
+		if row >= calculateViewport(m.height) || idx >= len(m.largeFiles) {
+			return m, nil
+		}
+		m.largeSelected = idx
+		return m, nil
+	}
+
+	idx := m.offset + row
+	if row >= calculateViewport(m.height) || idx >= len(m.entries) {
+		return m, nil
+	}
+	entry := m.entries[idx]
+	repeat := idx == m.selected && m.lastClickPath == entry.Path
+	m.selected = idx
+	m.lastClickPath = entry.Path
+	if repeat && entry.IsDir {
+		m.lastClickPath = ""
+		return m.enterSelectedDir()
+	}
+	return m, nil
+}
+
+// clickHeader jumps back to a clicked path segment, or toggles large files elsewhere on the line.
+func (m model) clickHeader(x int) (tea.Model, tea.Cmd) {
+	col := x - displayWidth(headerTitle)
+	if col >= 0 && col < displayWidth(displayPath(m.path)) {
+		target := breadcrumbTarget(m.path, col)
+		if target == m.path {
+			return m, nil
+		}
+		for i := len(m.history) - 1; i >= 0; i-- {
+			if m.history[i].Path == target {
+				m.history = m.history[:i+1]
+				m.showLargeFiles = false
+				return m.goBack()
+			}
+		}
+		m.status = fmt.Sprintf("%s was not visited in this session", displayPath(target))
+		return m, nil
+	}
+
+	m.showLargeFiles = !m.showLargeFiles
+	if m.showLargeFiles {
+		m.largeSelected = 0
+		m.largeOffset = 0
+		m.largeMultiSelected = make(map[string]bool)
+	} else {
+		m.multiSelected = make(map[string]bool)
+	}
+	return m, nil
+}
+
+// breadcrumbTarget returns the ancestor of path whose segment covers column col of displayPath(path).
+func breadcrumbTarget(path string, col int) string {
+	var ancestors []string
+	for p := path; ; p = filepath.Dir(p) {
+		ancestors = append([]string{p}, ancestors...)
+		if p == filepath.Dir(p) {
+			break
+		}
+	}
+	shown := displayPath(path)
+	for _, ancestor := range ancestors {
+		// Ancestors above ~ are not part of the displayed path.
+		prefix := displayPath(ancestor)
+		if !strings.HasPrefix(shown, prefix) {
+			continue
+		}
+		if displayWidth(prefix) > col {
+			return ancestor
+		}
+	}
+	return path
+}
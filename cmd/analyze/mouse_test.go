@@ -0,0 +1,82 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBreadcrumbTarget(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, "Documents", "Projects")
+
+	tests := []struct {
+		col  int
+		want string
+	}{
+		{0, home},                             // "~"
+		{3, filepath.Join(home, "Documents")}, // Inside "Documents".
+		{12, path},                            // Inside "Projects".
+	}
+	for _, tt := range tests {
+		if got := breadcrumbTarget(path, tt.col); got != tt.want {
+			t.Errorf("breadcrumbTarget(col %d) = %q, want %q", tt.col, got, tt.want)
+		}
+	}
+
+	if got := breadcrumbTarget("/var/log", 1); got != "/var" {
+		t.Errorf("expected /var for an absolute path, got %q", got)
+	}
+}
+
+func TestClickRowSelectsThenOpens(t *testing.T) {
+	m := model{
+		path:    "/tmp/root",
+		height:  30,
+		entries: []dirEntry{{Name: "a", Path: "/tmp/root/a", IsDir: true}, {Name: "b", Path: "/tmp/root/b"}},
+	}
+
+	updated, cmd := m.handleMouse(tea.MouseMsg{X: 10, Y: m.listTop() + 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	m = updated.(model)
+	if m.selected != 1 || cmd != nil {
+		t.Fatalf("expected first click to select row 1, got selected=%d", m.selected)
+	}
+
+	// A second click on a file row does nothing more.
+	updated, _ = m.handleMouse(tea.MouseMsg{X: 10, Y: m.listTop() + 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	m = updated.(model)
+	if m.path != "/tmp/root" {
+		t.Fatalf("clicking a file must not navigate, path=%s", m.path)
+	}
+
+	m.scanning = true
+	updated, _ = m.handleMouse(tea.MouseMsg{X: 10, Y: m.listTop(), Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if updated.(model).selected != 1 {
+		t.Fatal("clicks should be ignored while scanning")
+	}
+}
+
+func TestListTopMatchesLargeFilesView(t *testing.T) {
+	m := model{
+		path:           "/tmp/root",
+		height:         30,
+		showLargeFiles: true,
+		largeFiles:     []fileEntry{{Name: "big.iso", Path: "/tmp/root/big.iso", Size: 2 << 30}, {Name: "small.zip", Path: "/tmp/root/small.zip", Size: 1 << 30}},
+	}
+
+	// The summary line above the list shifts the first file down a row.
+	lines := strings.Split(m.View(), "\n")
+	row := slices.IndexFunc(lines, func(line string) bool { return strings.Contains(line, "big.iso") })
+	if row != m.listTop() {
+		t.Fatalf("first large file is on row %d, listTop says %d", row, m.listTop())
+	}
+
+	updated, _ := m.handleMouse(tea.MouseMsg{X: 10, Y: m.listTop() + 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if updated.(model).largeSelected != 1 {
+		t.Fatalf("expected the click to select small.zip, got %d", updated.(model).largeSelected)
+	}
+}
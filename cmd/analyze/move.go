@@ -0,0 +1,556 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/sys/unix"
+)
+
+const moveJournalFile = "moves.json"
+
+// moveRecord is one relocation kept in ~/.config/mole/moves.json so it can be undone.
+type moveRecord struct {
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	Size        int64     `json:"size"`
+	Symlink     bool      `json:"symlink"`
+	MovedAt     time.Time `json:"moved_at"`
+}
+
+type moveResultMsg struct {
+	record  moveRecord
+	restore bool
+	err     error
+}
+
+var moveJournalMu sync.Mutex
+
+func getMoveJournalPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "mole", moveJournalFile), nil
+}
+
+func loadMoveJournal() ([]moveRecord, error) {
+	path, err := getMoveJournalPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var records []moveRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func saveMoveJournal(records []moveRecord) error {
+	path, err := getMoveJournalPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// updateMoveJournal replaces any record for source, or drops it when record is nil.
+func updateMoveJournal(source string, record *moveRecord) error {
+	moveJournalMu.Lock()
+	defer moveJournalMu.Unlock()
+
+	records, err := loadMoveJournal()
+	if err != nil {
+		return err
+	}
+	kept := records[:0]
+	for _, r := range records {
+		if r.Source != source {
+			kept = append(kept, r)
+		}
+	}
+	if record != nil {
+		kept = append(kept, *record)
+	}
+	return saveMoveJournal(kept)
+}
+
+// findMoveRecord returns the journal entry whose original location is path.
+func findMoveRecord(path string) (moveRecord, bool) {
+	moveJournalMu.Lock()
+	defer moveJournalMu.Unlock()
+
+	records, err := loadMoveJournal()
+	if err != nil {
+		return moveRecord{}, false
+	}
+	for _, r := range records {
+		if r.Source == path {
+			return r, true
+		}
+	}
+	return moveRecord{}, false
+}
+
+// copyTreeVerified copies src to dst (which must not exist), hashing each file
+// while writing and re-reading the copy to compare. counter tracks copied bytes.
+// Hard links inside src stay linked in the copy, and folders keep their mtimes.
+func copyTreeVerified(src, dst string, counter *int64) error {
+	type inode struct{ dev, ino uint64 }
+	linked := make(map[inode]string) // First copy of each multiply linked file.
+	type dirTime struct {
+		path    string
+		modTime time.Time
+	}
+	var dirs []dirTime
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			dirs = append(dirs, dirTime{target, info.ModTime()})
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			stat, ok := info.Sys().(*syscall.Stat_t)
+			if ok && stat.Nlink > 1 {
+				key := inode{uint64(stat.Dev), uint64(stat.Ino)}
+				if first, seen := linked[key]; seen {
+					return os.Link(first, target)
+				}
+				linked[key] = target
+			}
+			if err := copyFileVerified(path, target, info, counter); err != nil {
+				return err
+			}
+			return os.Chtimes(target, info.ModTime(), info.ModTime())
+		default:
+			return fmt.Errorf("cannot move special file %s", displayPath(path))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	// Creating entries bumps a folder's mtime, so set them once everything is in.
+	for _, dir := range dirs {
+		if err := os.Chtimes(dir.path, dir.modTime, dir.modTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFileVerified(src, dst string, info os.FileInfo, counter *int64) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	hasher := xxhash.New()
+	if err := copySparse(out, in, info.Size(), io.MultiWriter(hasher, progressWriter{counter: counter})); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	copied, err := os.Open(dst)
+	if err != nil {
+		return err
+	}
+	defer copied.Close() //nolint:errcheck
+	check := xxhash.New()
+	if _, err := io.Copy(check, copied); err != nil {
+		return err
+	}
+	if check.Sum64() != hasher.Sum64() {
+		return fmt.Errorf("verification failed for %s", displayPath(dst))
+	}
+	return nil
+}
+
+// copySparse copies the first size bytes of in to out one data region at a
+// time, seeking over holes so sparse files stay sparse. seen is fed every byte,
+// holes as zeros, so it matches a plain read of either file. Filesystems that
+// cannot report holes are copied as one data region.
+func copySparse(out, in *os.File, size int64, seen io.Writer) error {
+	fd := int(in.Fd())
+	for off := int64(0); off < size; {
+		data, err := unix.Seek(fd, off, unix.SEEK_DATA)
+		switch {
+		case errors.Is(err, unix.ENXIO):
+			data = size // Nothing but a hole up to the end.
+		case err != nil:
+			data = off
+		}
+		data = min(data, size)
+		if data > off {
+			if _, err := io.CopyN(seen, zeroReader{}, data-off); err != nil {
+				return err
+			}
+			off = data
+			continue
+		}
+
+		hole, err := unix.Seek(fd, off, unix.SEEK_HOLE)
+		if err != nil || hole <= off || hole > size {
+			hole = size
+		}
+		region := io.NewSectionReader(in, off, hole-off)
+		if _, err := io.Copy(io.MultiWriter(io.NewOffsetWriter(out, off), seen), region); err != nil {
+			return err
+		}
+		off = hole
+	}
+	// A trailing hole is never written, so set the length explicitly.
+	return out.Truncate(size)
+}
+
+// zeroReader reads endless zeros, standing in for the holes of a sparse file.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// progressWriter reports bytes written through an atomic counter.
+type progressWriter struct {
+	counter *int64
+}
+
+func (w progressWriter) Write(p []byte) (int, error) {
+	if w.counter != nil {
+		atomic.AddInt64(w.counter, int64(len(p)))
+	}
+	return len(p), nil
+}
+
+// relocatePath copies src into destDir on another volume, removes the original
+// and optionally leaves a symlink behind. Nothing is removed unless the copy
+// verified and the move is in the journal; until the stub is in place the
+// original is only renamed aside, so every failure before that puts it back.
+func relocatePath(src, destDir string, size int64, symlink bool, counter *int64) (moveRecord, error) {
+	record := moveRecord{Source: src, Size: size, Symlink: symlink}
+
+	srcDev, ok := deviceID(src)
+	if !ok {
+		return record, fmt.Errorf("cannot read %s", displayPath(src))
+	}
+	if destDev, ok := deviceID(destDir); ok && destDev == srcDev {
+		return record, fmt.Errorf("%s is on the same volume", displayPath(destDir))
+	}
+
+	dst := filepath.Join(destDir, filepath.Base(src))
+	record.Destination = dst
+	if _, err := os.Lstat(dst); err == nil {
+		return record, fmt.Errorf("%s already exists", displayPath(dst))
+	}
+	aside := src + ".mole-move"
+	if _, err := os.Lstat(aside); err == nil {
+		return record, fmt.Errorf("%s already exists", displayPath(aside))
+	}
+	if err := copyTreeVerified(src, dst, counter); err != nil {
+		_ = os.RemoveAll(dst)
+		return record, fmt.Errorf("copy to %s: %w", displayPath(destDir), err)
+	}
+
+	record.MovedAt = time.Now()
+	if err := updateMoveJournal(src, &record); err != nil {
+		_ = os.RemoveAll(dst)
+		return record, fmt.Errorf("journal not saved, original kept: %w", err)
+	}
+	// undo drops the copy and the journal entry once the original is back.
+	undo := func(err error) (moveRecord, error) {
+		_ = updateMoveJournal(src, nil)
+		_ = os.RemoveAll(dst)
+		return record, fmt.Errorf("original kept: %w", err)
+	}
+
+	if err := os.Rename(src, aside); err != nil {
+		return undo(err)
+	}
+	if symlink {
+		if err := os.Symlink(dst, src); err != nil {
+			if renameErr := os.Rename(aside, src); renameErr != nil {
+				return record, fmt.Errorf("symlink failed and original left at %s: %w", displayPath(aside), renameErr)
+			}
+			return undo(fmt.Errorf("symlink failed: %w", err))
+		}
+	}
+
+	detail := fmt.Sprintf("%s -> %s", oplogSize(size), displayPath(dst))
+	if record.Symlink {
+		detail += ", symlink"
+	}
+	logOperation("MOVED", src, detail)
+
+	if err := os.RemoveAll(aside); err != nil {
+		return record, fmt.Errorf("moved to %s but leftovers remain at %s: %w", displayPath(dst), displayPath(aside), err)
+	}
+	return record, nil
+}
+
+// restoreMovedPath copies a relocated directory back and removes the stub and the moved copy.
+func restoreMovedPath(record moveRecord, counter *int64) error {
+	if info, err := os.Lstat(record.Source); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			return fmt.Errorf("%s exists and is not a symlink", displayPath(record.Source))
+		}
+	}
+	if _, err := os.Stat(record.Destination); err != nil {
+		return fmt.Errorf("moved copy missing: %w", err)
+	}
+
+	staging := record.Source + ".mole-restore"
+	if _, err := os.Lstat(staging); err == nil {
+		return fmt.Errorf("%s already exists", displayPath(staging))
+	}
+	if err := copyTreeVerified(record.Destination, staging, counter); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+	if err := os.Remove(record.Source); err != nil && !os.IsNotExist(err) {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+	if err := os.Rename(staging, record.Source); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(record.Destination); err != nil {
+		return fmt.Errorf("restored, but moved copy kept at %s: %w", displayPath(record.Destination), err)
+	}
+
+	logOperation("RESTORED", record.Source, fmt.Sprintf("%s <- %s", oplogSize(record.Size), displayPath(record.Destination)))
+	return updateMoveJournal(record.Source, nil)
+}
+
+func relocatePathCmd(src, destDir string, size int64, symlink bool, counter *int64) tea.Cmd {
+	return func() tea.Msg {
+		record, err := relocatePath(src, destDir, size, symlink, counter)
+		return moveResultMsg{record: record, err: err}
+	}
+}
+
+func restoreMovedPathCmd(record moveRecord, counter *int64) tea.Cmd {
+	return func() tea.Msg {
+		err := restoreMovedPath(record, counter)
+		return moveResultMsg{record: record, restore: true, err: err}
+	}
+}
+
+// openMoveConfirm starts a move for the highlighted entry, or offers to undo
+// one when the entry is a symlink left by an earlier move.
+func (m *model) openMoveConfirm() {
+	if m.inOverviewMode() || m.showLargeFiles || m.selected >= len(m.entries) {
+		return
+	}
+	if m.policy.readOnly {
+		m.status = "Read-only mode, moving is disabled"
+		return
+	}
+	entry := m.entries[m.selected]
+	if record, ok := findMoveRecord(entry.Path); ok {
+		m.openMoveRestore(record)
+		return
+	}
+	if !entry.IsDir {
+		m.status = "Select a folder to move"
+		return
+	}
+	if m.virtual != nil && m.virtual.partial(entry.Path) {
+		// Moving it would take files that were never listed.
+		m.status = "Only listed files can be moved here, this folder holds others too"
+		return
+	}
+	if err := m.policy.check(entry.Path); err != nil {
+		m.status = err.Error()
+		return
+	}
+	m.moveConfirm = true
+	m.moveRestore = nil
+	m.moveSource = entry
+	m.moveDest = ""
+	m.moveSymlink = true
+}
+
+// openMoveRestore asks to undo the move in record.
+func (m *model) openMoveRestore(record moveRecord) {
+	m.moveConfirm = true
+	m.moveRestore = &record
+	m.moveSource = dirEntry{Name: filepath.Base(record.Source), Path: record.Source, Size: record.Size}
+}
+
+// openMovesView lists the move journal, newest first. Moves made without a
+// symlink leave nothing at the original path to press M on, so this is the
+// way back for them.
+func (m *model) openMovesView() {
+	moveJournalMu.Lock()
+	records, err := loadMoveJournal()
+	moveJournalMu.Unlock()
+	if err != nil {
+		m.status = fmt.Sprintf("Cannot read moves: %v", err)
+		return
+	}
+	if len(records) == 0 {
+		m.status = "No moved folders"
+		return
+	}
+	slices.Reverse(records)
+	m.moveRows = records
+	m.moveRowSelected = 0
+	m.showMoves = true
+}
+
+func (m model) updateMovesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.closesView(msg, actionMoves) {
+		m.showMoves = false
+		return m, nil
+	}
+	switch m.keyAction(msg) {
+	case actionUp:
+		if m.moveRowSelected > 0 {
+			m.moveRowSelected--
+		}
+	case actionDown:
+		if m.moveRowSelected < len(m.moveRows)-1 {
+			m.moveRowSelected++
+		}
+	case actionEnter:
+		if m.moveRowSelected >= len(m.moveRows) {
+			return m, nil
+		}
+		if m.policy.readOnly {
+			m.status = "Read-only mode, moving is disabled"
+			return m, nil
+		}
+		m.showMoves = false
+		m.openMoveRestore(m.moveRows[m.moveRowSelected])
+	}
+	return m, nil
+}
+
+func (m model) updateMoveConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.moveConfirm = false
+		m.moveRestore = nil
+		m.moveDest = ""
+		m.status = "Cancelled"
+		return m, nil
+	case "enter":
+		var moveBytes int64
+		m.moveBytes = &moveBytes
+		if m.moveRestore != nil {
+			record := *m.moveRestore
+			m.moveConfirm = false
+			m.moveRestore = nil
+			m.moving = true
+			m.status = fmt.Sprintf("Restoring %s...", filepath.Base(record.Source))
+			return m, tea.Batch(restoreMovedPathCmd(record, m.moveBytes), tickCmd())
+		}
+		dest, err := resolveDestDir(m.moveDest)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		if dest == "" {
+			m.status = "Type a destination folder on another volume"
+			return m, nil
+		}
+		m.moveConfirm = false
+		m.moving = true
+		m.status = fmt.Sprintf("Moving %s...", m.moveSource.Name)
+		return m, tea.Batch(relocatePathCmd(m.moveSource.Path, dest, m.moveSource.Size, m.moveSymlink, m.moveBytes), tickCmd())
+	}
+
+	if m.moveRestore != nil {
+		return m, nil
+	}
+	switch msg.String() {
+	case "tab":
+		m.moveSymlink = !m.moveSymlink
+	case "backspace":
+		if m.moveDest != "" {
+			runes := []rune(m.moveDest)
+			m.moveDest = string(runes[:len(runes)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.moveDest += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+func (m model) handleMoveResult(msg moveResultMsg) (tea.Model, tea.Cmd) {
+	m.moving = false
+	m.moveDest = ""
+	if msg.err != nil {
+		m.status = fmt.Sprintf("Move failed: %v", msg.err)
+	} else if msg.restore {
+		m.status = fmt.Sprintf("Restored %s", displayPath(msg.record.Source))
+	} else {
+		m.status = fmt.Sprintf("Moved %s to %s", humanizeBytes(msg.record.Size), displayPath(msg.record.Destination))
+	}
+	invalidateCache(msg.record.Source)
+	return m, m.rescanAfterChange()
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+func TestCopyTreeVerified(t *testing.T) {
+	base := t.TempDir()
+	src := filepath.Join(base, "dataset")
+	if err := os.MkdirAll(filepath.Join(src, "part"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFileWithSize(t, filepath.Join(src, "part", "a.bin"), 64*1024)
+	writeFileWithSize(t, filepath.Join(src, "b.bin"), 1024)
+	if err := os.Symlink("b.bin", filepath.Join(src, "latest")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	var copied int64
+	dst := filepath.Join(base, "copy")
+	if err := copyTreeVerified(src, dst, &copied); err != nil {
+		t.Fatalf("copyTreeVerified: %v", err)
+	}
+	if copied != 65*1024 {
+		t.Fatalf("expected %d bytes copied, got %d", 65*1024, copied)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "part", "a.bin")); err != nil || info.Size() != 64*1024 {
+		t.Fatalf("expected copied file, info=%v err=%v", info, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "latest")); err != nil || link != "b.bin" {
+		t.Fatalf("expected symlink to be recreated, link=%q err=%v", link, err)
+	}
+}
+
+func TestCopyTreeVerifiedKeepsLinksAndTimes(t *testing.T) {
+	base := t.TempDir()
+	src := filepath.Join(base, "dataset")
+	writeFileWithSize(t, filepath.Join(src, "part", "a.bin"), 4096)
+	if err := os.Link(filepath.Join(src, "part", "a.bin"), filepath.Join(src, "a-link.bin")); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	old := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	for _, dir := range []string{filepath.Join(src, "part"), src} {
+		if err := os.Chtimes(dir, old, old); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	var copied int64
+	dst := filepath.Join(base, "copy")
+	if err := copyTreeVerified(src, dst, &copied); err != nil {
+		t.Fatalf("copyTreeVerified: %v", err)
+	}
+	if copied != 4096 {
+		t.Fatalf("expected the linked file to be copied once, got %d bytes", copied)
+	}
+	first, err := os.Stat(filepath.Join(dst, "part", "a.bin"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	second, err := os.Stat(filepath.Join(dst, "a-link.bin"))
+	if err != nil || !os.SameFile(first, second) {
+		t.Fatalf("expected the hard link to be kept, err=%v", err)
+	}
+	for _, dir := range []string{filepath.Join(dst, "part"), dst} {
+		if info, err := os.Stat(dir); err != nil || !info.ModTime().Equal(old) {
+			t.Fatalf("expected %s to keep its mtime %v, info=%v err=%v", dir, old, info, err)
+		}
+	}
+}
+
+func TestCopyFileVerifiedKeepsHoles(t *testing.T) {
+	base := t.TempDir()
+	src := filepath.Join(base, "disk.img")
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// 64MB long with 4KB of data in the middle, and a hole at the end.
+	if _, err := f.WriteAt(bytes.Repeat([]byte{7}, 4096), 32<<20); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := f.Truncate(64 << 20); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	_ = f.Close()
+	info, err := os.Lstat(src)
+	if err != nil {
+		t.Fatalf("lstat: %v", err)
+	}
+	if scan.DiskUsage(info) >= info.Size() {
+		t.Skip("filesystem does not keep sparse files")
+	}
+
+	var copied int64
+	dst := filepath.Join(base, "copy.img")
+	if err := copyFileVerified(src, dst, info, &copied); err != nil {
+		t.Fatalf("copyFileVerified: %v", err)
+	}
+	out, err := os.Lstat(dst)
+	if err != nil {
+		t.Fatalf("lstat copy: %v", err)
+	}
+	if out.Size() != info.Size() || copied != info.Size() {
+		t.Fatalf("expected %d bytes, got %d on disk and %d reported", info.Size(), out.Size(), copied)
+	}
+	if usage := scan.DiskUsage(out); usage > 1<<20 {
+		t.Fatalf("expected the copy to stay sparse, it uses %d bytes", usage)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("read copy: %v", err)
+	}
+	if data[32<<20] != 7 || data[32<<20-1] != 0 {
+		t.Fatal("expected the data region at its original offset")
+	}
+}
+
+func TestRelocatePathRejectsSameVolume(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	base := t.TempDir()
+	src := filepath.Join(base, "vm")
+	dest := filepath.Join(base, "elsewhere")
+	for _, dir := range []string{src, dest} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+
+	if _, err := relocatePath(src, dest, 0, true, nil); err == nil {
+		t.Fatal("expected same-volume move to be rejected")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Fatalf("source should be untouched: %v", err)
+	}
+}
+
+func TestRestoreMovedPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	base := t.TempDir()
+	source := filepath.Join(base, "Projects", "dataset")
+	moved := filepath.Join(base, "External", "dataset")
+	if err := os.MkdirAll(moved, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(source), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFileWithSize(t, filepath.Join(moved, "data.csv"), 2048)
+	if err := os.Symlink(moved, source); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	record := moveRecord{Source: source, Destination: moved, Size: 2048, Symlink: true}
+	if err := updateMoveJournal(source, &record); err != nil {
+		t.Fatalf("updateMoveJournal: %v", err)
+	}
+	if found, ok := findMoveRecord(source); !ok || found.Destination != moved {
+		t.Fatalf("expected journal entry, got %+v ok=%v", found, ok)
+	}
+
+	if err := restoreMovedPath(record, nil); err != nil {
+		t.Fatalf("restoreMovedPath: %v", err)
+	}
+
+	info, err := os.Lstat(source)
+	if err != nil || !info.IsDir() {
+		t.Fatalf("expected restored directory, info=%v err=%v", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(source, "data.csv")); err != nil {
+		t.Fatalf("expected restored file: %v", err)
+	}
+	if _, err := os.Stat(moved); !os.IsNotExist(err) {
+		t.Fatalf("expected moved copy to be removed, err=%v", err)
+	}
+	if _, ok := findMoveRecord(source); ok {
+		t.Fatal("expected journal entry to be dropped after restore")
+	}
+}
+
+// otherVolume returns a scratch folder on a different device than dir, or
+// skips the test when there is none.
+func otherVolume(t *testing.T, dir string) string {
+	t.Helper()
+	other, err := os.MkdirTemp("/dev/shm", "mole-move")
+	if err != nil {
+		t.Skip("no second volume to move to")
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(other) })
+	dirDev, _ := deviceID(dir)
+	if otherDev, _ := deviceID(other); otherDev == dirDev {
+		t.Skip("no second volume to move to")
+	}
+	return other
+}
+
+func TestRelocatePathKeepsOriginalWithoutJournal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	// A file where ~/.config should be makes every journal write fail.
+	writeFileWithSize(t, filepath.Join(home, ".config"), 1)
+	src := filepath.Join(t.TempDir(), "vm")
+	writeFileWithSize(t, filepath.Join(src, "disk.img"), 4096)
+	dest := otherVolume(t, src)
+
+	if _, err := relocatePath(src, dest, 4096, true, nil); err == nil {
+		t.Fatal("expected the move to fail without a journal")
+	}
+	if info, err := os.Lstat(src); err != nil || !info.IsDir() {
+		t.Fatalf("expected the original in place, info=%v err=%v", info, err)
+	}
+	if _, err := os.Lstat(filepath.Join(dest, "vm")); !os.IsNotExist(err) {
+		t.Fatalf("expected the copy to be removed, err=%v", err)
+	}
+}
+
+func TestRelocatePathLeavesSymlink(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	src := filepath.Join(t.TempDir(), "vm")
+	writeFileWithSize(t, filepath.Join(src, "disk.img"), 4096)
+	dest := otherVolume(t, src)
+
+	record, err := relocatePath(src, dest, 4096, true, nil)
+	if err != nil {
+		t.Fatalf("relocatePath: %v", err)
+	}
+	if link, err := os.Readlink(src); err != nil || link != record.Destination {
+		t.Fatalf("expected a symlink to %s, got %q err=%v", record.Destination, link, err)
+	}
+	if _, err := os.Lstat(src + ".mole-move"); !os.IsNotExist(err) {
+		t.Fatalf("expected the original to be removed, err=%v", err)
+	}
+	if found, ok := findMoveRecord(src); !ok || found.Destination != record.Destination {
+		t.Fatalf("expected a journal entry, got %+v ok=%v", found, ok)
+	}
+}
+
+func TestMovesViewRestoresWithoutSymlink(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	base := t.TempDir()
+	older := moveRecord{Source: filepath.Join(base, "Projects", "old"), Destination: filepath.Join(base, "External", "old"), Size: 1024, Symlink: true}
+	record := moveRecord{Source: filepath.Join(base, "Projects", "dataset"), Destination: filepath.Join(base, "External", "dataset"), Size: 2048}
+	for _, r := range []moveRecord{older, record} {
+		if err := updateMoveJournal(r.Source, &r); err != nil {
+			t.Fatalf("updateMoveJournal: %v", err)
+		}
+	}
+
+	m := newModel(base, false)
+	m.scanning = false
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	m = next.(model)
+	if !m.showMoves || len(m.moveRows) != 2 || m.moveRows[0].Source != record.Source {
+		t.Fatalf("expected both moves listed newest first, got %+v (status %q)", m.moveRows, m.status)
+	}
+
+	// Nothing is left at the original path, so the list is the only way back.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(model)
+	if m.showMoves || !m.moveConfirm || m.moveRestore == nil || m.moveRestore.Source != record.Source {
+		t.Fatalf("expected a restore prompt for %s, got confirm=%v restore=%+v", record.Source, m.moveConfirm, m.moveRestore)
+	}
+	if m.moveSource.Size != record.Size {
+		t.Fatalf("expected the recorded size, got %d", m.moveSource.Size)
+	}
+}
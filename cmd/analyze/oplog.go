@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Operations log shared with lib/core/log.sh (log_operation).
+const (
+	oplogCommand = "analyze"
+	oplogMaxSize = 5 << 20 // Matches OPLOG_MAX_SIZE_DEFAULT.
+)
+
+var oplogMu sync.Mutex
+
+func oplogEnabled() bool {
+	return os.Getenv("MO_NO_OPLOG") != "1"
+}
+
+func getOperationsLogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "mole", "operations.log"), nil
+}
+
+// logOperation appends one line in the shell format:
+// [YYYY-MM-DD HH:MM:SS] [command] ACTION path (detail)
+// Failures are ignored; logging must never block a delete.
+func logOperation(action, path, detail string) {
+	if !oplogEnabled() || path == "" {
+		return
+	}
+
+	logPath, err := getOperationsLogPath()
+	if err != nil {
+		return
+	}
+
+	line := fmt.Sprintf("[%s] [%s] %s %s", time.Now().Format("2006-01-02 15:04:05"), oplogCommand, action, path)
+	if detail != "" {
+		line += " (" + detail + ")"
+	}
+
+	oplogMu.Lock()
+	defer oplogMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return
+	}
+	if info, err := os.Stat(logPath); err == nil && info.Size() > oplogMaxSize {
+		_ = os.Rename(logPath, logPath+".old")
+	}
+
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer file.Close() //nolint:errcheck
+	_, _ = file.WriteString(line + "\n")
+}
+
+// oplogSize formats bytes like bytes_to_human in the shell ("15.2MB").
+func oplogSize(size int64) string {
+	return strings.ReplaceAll(humanizeBytes(size), " ", "")
+}
+
+// logDeleteResult records the outcome of a trash or permanent delete.
+func logDeleteResult(path string, size, count int64, destination string, err error) {
+	if err != nil {
+		logOperation("FAILED", path, err.Error())
+		return
+	}
+	logOperation("REMOVED", path, fmt.Sprintf("%s, %d items, %s", oplogSize(size), count, destination))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLogDeleteResultFormat(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MO_NO_OPLOG", "")
+
+	logDeleteResult("/tmp/project/node_modules", 15<<20, 42, "trash: ~/.Trash", nil)
+	logDeleteResult("/tmp/project/dist", 0, 0, "permanent", errors.New("permission denied"))
+
+	data, err := os.ReadFile(filepath.Join(home, ".config", "mole", "operations.log"))
+	if err != nil {
+		t.Fatalf("read operations log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %q", len(lines), data)
+	}
+
+	removed := regexp.MustCompile(`^\[\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\] \[analyze\] REMOVED /tmp/project/node_modules \(15\.0MB, 42 items, trash: ~/\.Trash\)$`)
+	if !removed.MatchString(lines[0]) {
+		t.Errorf("unexpected REMOVED line: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "[analyze] FAILED /tmp/project/dist (permission denied)") {
+		t.Errorf("unexpected FAILED line: %q", lines[1])
+	}
+}
+
+func TestLogOperationDisabled(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MO_NO_OPLOG", "1")
+
+	logOperation("REMOVED", "/tmp/file", "1B")
+
+	if _, err := os.Stat(filepath.Join(home, ".config", "mole", "operations.log")); !os.IsNotExist(err) {
+		t.Fatalf("expected no operations log when MO_NO_OPLOG=1, err=%v", err)
+	}
+}
+
+func TestLogOperationRotates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MO_NO_OPLOG", "")
+
+	logPath := filepath.Join(home, ".config", "mole", "operations.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(logPath, make([]byte, oplogMaxSize+1), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	logOperation("REMOVED", "/tmp/file", "1B")
+
+	if _, err := os.Stat(logPath + ".old"); err != nil {
+		t.Fatalf("expected rotated log: %v", err)
+	}
+	info, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatalf("stat log: %v", err)
+	}
+	if info.Size() > 200 {
+		t.Fatalf("expected fresh log after rotation, size=%d", info.Size())
+	}
+}
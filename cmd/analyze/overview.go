@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+const (
+	overviewConfigFile = "analyze_overview"
+	overviewMountsKey  = "@mounts"
+)
+
+// overviewRoot is one location shown on the overview screen.
+type overviewRoot struct {
+	Label string
+	Path  string
+}
+
+// overviewConfig lists overview roots and whether mounted filesystems are appended.
+type overviewConfig struct {
+	Roots  []overviewRoot
+	Mounts bool
+}
+
+// mountInfo is a mounted filesystem with capacity from statfs.
+type mountInfo struct {
+	Path   string
+	FSType string
+	Total  int64
+	Used   int64
+}
+
+// Pseudo and system filesystems never worth listing as overview roots.
+var skipMountFSTypes = map[string]bool{
+	"autofs":     true,
+	"devfs":      true,
+	"devtmpfs":   true,
+	"tmpfs":      true,
+	"proc":       true,
+	"sysfs":      true,
+	"cgroup":     true,
+	"cgroup2":    true,
+	"overlay":    true,
+	"squashfs":   true,
+	"nullfs":     true,
+	"fuse.lxcfs": true,
+}
+
+func getOverviewConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "mole", overviewConfigFile), nil
+}
+
+// defaultOverviewRoots returns the built-in roots for the current platform.
+func defaultOverviewRoots(home string) []overviewRoot {
+	var roots []overviewRoot
+	if runtime.GOOS == "linux" {
+		if home != "" {
+			roots = append(roots,
+				overviewRoot{Label: "Home", Path: home},
+				overviewRoot{Label: "Cache", Path: filepath.Join(home, ".cache")},
+				overviewRoot{Label: "Local Share", Path: filepath.Join(home, ".local", "share")},
+			)
+		}
+		return append(roots,
+			overviewRoot{Label: "Var", Path: "/var"},
+			overviewRoot{Label: "Opt", Path: "/opt"},
+			overviewRoot{Label: "Usr", Path: "/usr"},
+		)
+	}
+
+	// Separate Home and ~/Library to avoid double counting.
+	if home != "" {
+		roots = append(roots,
+			overviewRoot{Label: "Home", Path: home},
+			overviewRoot{Label: "App Library", Path: filepath.Join(home, "Library")},
+		)
+	}
+	return append(roots,
+		overviewRoot{Label: "Applications", Path: "/Applications"},
+		overviewRoot{Label: "System Library", Path: "/Library"},
+	)
+}
+
+// loadOverviewConfig reads ~/.config/mole/analyze_overview, falling back to platform defaults.
+func loadOverviewConfig() overviewConfig {
+	home := os.Getenv("HOME")
+	if path, err := getOverviewConfigPath(); err == nil {
+		if file, err := os.Open(path); err == nil {
+			defer file.Close() //nolint:errcheck
+			if cfg := parseOverviewConfig(file, home); len(cfg.Roots) > 0 || cfg.Mounts {
+				return cfg
+			}
+		}
+	}
+	return overviewConfig{Roots: defaultOverviewRoots(home)}
+}
+
+// parseOverviewConfig reads one root per line: "/path", "~/path" or "Label = /path".
+// "@mounts" appends every mounted filesystem; "#" starts a comment.
+func parseOverviewConfig(r io.Reader, home string) overviewConfig {
+	var cfg overviewConfig
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == overviewMountsKey {
+			cfg.Mounts = true
+			continue
+		}
+
+		label, path := "", line
+		if idx := strings.Index(line, "="); idx >= 0 {
+			label = strings.TrimSpace(line[:idx])
+			path = strings.TrimSpace(line[idx+1:])
+		}
+		if path == "~" || strings.HasPrefix(path, "~/") {
+			if home == "" {
+				continue
+			}
+			path = home + strings.TrimPrefix(path, "~")
+		}
+		if !filepath.IsAbs(path) {
+			continue
+		}
+		path = filepath.Clean(path)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		if label == "" {
+			label = defaultOverviewLabel(path, home)
+		}
+		cfg.Roots = append(cfg.Roots, overviewRoot{Label: label, Path: path})
+	}
+	return cfg
+}
+
+func defaultOverviewLabel(path, home string) string {
+	switch {
+	case home != "" && path == home:
+		return "Home"
+	case path == "/":
+		return "Root"
+	}
+	return filepath.Base(path)
+}
+
+// listMounts returns real mounted filesystems with capacity and usage.
+func listMounts() []mountInfo {
+	partitions, err := disk.Partitions(false)
+	if err != nil {
+		return nil
+	}
+
+	var mounts []mountInfo
+	seen := make(map[string]bool)
+	for _, part := range partitions {
+		mountpoint := part.Mountpoint
+		if seen[mountpoint] || skipMountFSTypes[part.Fstype] {
+			continue
+		}
+		if strings.HasPrefix(mountpoint, "/System/Volumes/") || strings.HasPrefix(mountpoint, "/private/") ||
+			strings.HasPrefix(mountpoint, "/dev") || strings.HasPrefix(mountpoint, "/proc") ||
+			strings.HasPrefix(mountpoint, "/sys") || strings.HasPrefix(mountpoint, "/run") ||
+			strings.HasPrefix(mountpoint, "/snap/") || strings.HasPrefix(mountpoint, "/boot") {
+			continue
+		}
+		if strings.HasPrefix(part.Device, "/dev/loop") {
+			continue
+		}
+		usage, err := disk.Usage(mountpoint)
+		if err != nil || usage.Total == 0 {
+			continue
+		}
+		seen[mountpoint] = true
+		mounts = append(mounts, mountInfo{
+			Path:   mountpoint,
+			FSType: part.Fstype,
+			Total:  int64(usage.Total),
+			Used:   int64(usage.Used),
+		})
+	}
+	sort.Slice(mounts, func(i, j int) bool { return mounts[i].Path < mounts[j].Path })
+	return mounts
+}
+
+// mountLabel names a mount for the overview list.
+func mountLabel(path string) string {
+	if path == "/" {
+		return "Root"
+	}
+	return filepath.Base(path)
+}
+
+// overviewExcludePath returns the child measureOverviewSize leaves out of path, if any.
+// Home excludes ~/Library so the App Library row is not counted twice.
+func overviewExcludePath(path string) string {
+	home := os.Getenv("HOME")
+	if home != "" && path == home {
+		return filepath.Join(home, "Library")
+	}
+	return ""
+}
+
+// overviewTotal sums root sizes without counting nested roots twice.
+// Mount entries are left out; their used space overlaps the roots.
+func overviewTotal(entries []dirEntry, mounts map[string]mountInfo) int64 {
+	var total int64
+	for _, entry := range entries {
+		if entry.Size <= 0 {
+			continue
+		}
+		if _, ok := mounts[entry.Path]; ok {
+			continue
+		}
+		if isNestedOverviewRoot(entry.Path, entries) {
+			continue
+		}
+		total += entry.Size
+	}
+	return total
+}
+
+// isNestedOverviewRoot reports whether another root already counts path.
+func isNestedOverviewRoot(path string, entries []dirEntry) bool {
+	for _, other := range entries {
+		if other.Path == path {
+			continue
+		}
+		if !strings.HasPrefix(path, strings.TrimSuffix(other.Path, "/")+"/") {
+			continue
+		}
+		if overviewExcludePath(other.Path) == path {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// createOverviewEntries builds pending entries for the configured roots.
+// Missing roots are skipped so a fresh machine does not show empty rows.
+func createOverviewEntries() []dirEntry {
+	cfg := loadOverviewConfig()
+	entries := make([]dirEntry, 0, len(cfg.Roots))
+	for _, root := range cfg.Roots {
+		if _, err := os.Stat(root.Path); err != nil {
+			continue
+		}
+		entries = append(entries, dirEntry{Name: root.Label, Path: root.Path, IsDir: true, Size: -1})
+	}
+	return entries
+}
+
+// appendOverviewMounts adds mounted filesystems that are not already roots.
+// Their size is the used space reported by statfs, so they never need a walk.
+func (m *model) appendOverviewMounts() {
+	m.overviewMounts = nil
+	if !loadOverviewConfig().Mounts {
+		return
+	}
+	roots := make(map[string]bool, len(m.entries))
+	for _, entry := range m.entries {
+		roots[entry.Path] = true
+	}
+	m.overviewMounts = make(map[string]mountInfo)
+	for _, mount := range listMounts() {
+		if roots[mount.Path] {
+			continue
+		}
+		m.overviewMounts[mount.Path] = mount
+		m.entries = append(m.entries, dirEntry{Name: mountLabel(mount.Path), Path: mount.Path, IsDir: true, Size: mount.Used})
+	}
+}
+
+// isOverviewMount reports whether an overview entry comes from the mounts section.
+func (m model) isOverviewMount(path string) bool {
+	_, ok := m.overviewMounts[path]
+	return ok
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOverviewConfig(t *testing.T) {
+	home := "/Users/tester"
+	content := `# roots
+~
+Projects = ~/Projects
+/Volumes/Data
+/Volumes/Data/
+relative/path
+@mounts
+`
+	cfg := parseOverviewConfig(strings.NewReader(content), home)
+	if !cfg.Mounts {
+		t.Fatal("expected @mounts to enable the mounts section")
+	}
+	want := []overviewRoot{
+		{Label: "Home", Path: "/Users/tester"},
+		{Label: "Projects", Path: "/Users/tester/Projects"},
+		{Label: "Data", Path: "/Volumes/Data"},
+	}
+	if len(cfg.Roots) != len(want) {
+		t.Fatalf("expected %v, got %v", want, cfg.Roots)
+	}
+	for i := range want {
+		if cfg.Roots[i] != want[i] {
+			t.Errorf("root %d: expected %+v, got %+v", i, want[i], cfg.Roots[i])
+		}
+	}
+}
+
+func TestDefaultOverviewRoots(t *testing.T) {
+	roots := defaultOverviewRoots("/home/tester")
+	if len(roots) == 0 {
+		t.Fatal("expected default roots")
+	}
+	if roots[0].Path != "/home/tester" || roots[0].Label != "Home" {
+		t.Fatalf("expected Home first, got %+v", roots[0])
+	}
+	for _, root := range defaultOverviewRoots("") {
+		if strings.HasPrefix(root.Path, "/home") || root.Path == "" {
+			t.Fatalf("unexpected home-relative root without HOME: %+v", root)
+		}
+	}
+}
+
+func TestOverviewTotalSkipsNestedRootsAndMounts(t *testing.T) {
+	t.Setenv("HOME", "/Users/tester")
+	entries := []dirEntry{
+		{Path: "/Users/tester", Size: 100},
+		{Path: "/Users/tester/Library", Size: 50},  // Excluded from Home's measurement.
+		{Path: "/Users/tester/Projects", Size: 30}, // Already inside Home.
+		{Path: "/Applications", Size: 20},
+		{Path: "/Volumes/Data", Size: 1000},
+		{Path: "/opt", Size: -1},
+	}
+	mounts := map[string]mountInfo{"/Volumes/Data": {Path: "/Volumes/Data", Total: 2000, Used: 1000}}
+
+	if got := overviewTotal(entries, mounts); got != 170 {
+		t.Fatalf("expected total 170, got %d", got)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+// ownerUsage and ownershipReport come from the scanner; the view only filters and renders them.
+type (
+	ownerUsage      = scan.OwnerUsage
+	ownershipReport = scan.OwnershipReport
+)
+
+// filterEntriesByOwner keeps entries holding data of uid, sized to that user's share.
+func filterEntriesByOwner(entries []dirEntry, uid uint32) ([]dirEntry, int64) {
+	var filtered []dirEntry
+	var total int64
+	for _, entry := range entries {
+		size := entry.OwnerSizes[uid]
+		if size <= 0 {
+			continue
+		}
+		entry.Size = size
+		filtered = append(filtered, entry)
+		total += size
+	}
+	sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].Size > filtered[j].Size })
+	return filtered, total
+}
+
+// openOwnersView shows who holds the space under the current root.
+func (m *model) openOwnersView() {
+	if len(m.scanOwners.Users) == 0 {
+		m.status = "No ownership data for this scan"
+		return
+	}
+	m.showOwners = true
+	m.ownerSelected = 0
+}
+
+// applyOwnerFilter narrows the entry list to one user; the full list is kept to restore.
+func (m *model) applyOwnerFilter(owner ownerUsage) {
+	if m.ownerFilter == nil {
+		m.ownerAllEntries = m.entries
+		m.ownerAllTotal = m.totalSize
+	}
+	uid := owner.ID
+	m.ownerFilter = &uid
+	m.entries, m.totalSize = filterEntriesByOwner(m.ownerAllEntries, uid)
+	m.selected = 0
+	m.offset = 0
+	m.multiSelected = make(map[string]bool)
+	m.status = fmt.Sprintf("Showing %s only, %s", owner.Name, humanizeBytes(m.totalSize))
+}
+
+// clearOwnerFilter restores the unfiltered listing.
+func (m *model) clearOwnerFilter() {
+	if m.ownerFilter == nil {
+		return
+	}
+	m.entries = m.ownerAllEntries
+	m.totalSize = m.ownerAllTotal
+	m.ownerFilter = nil
+	m.ownerAllEntries = nil
+	m.clampEntrySelection()
+}
+
+// dropOwnerFilter forgets the filter when the listing is about to be replaced.
+func (m *model) dropOwnerFilter() {
+	m.ownerFilter = nil
+	m.ownerAllEntries = nil
+}
+
+// unfilteredEntries is what snapshots and caches should keep.
+func (m model) unfilteredEntries() ([]dirEntry, int64) {
+	if m.ownerFilter != nil {
+		return slices.Clone(m.ownerAllEntries), m.ownerAllTotal
+	}
+	return slices.Clone(m.entries), m.totalSize
+}
+
+func (m model) updateOwnersKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	users := m.scanOwners.Users
+	if m.closesView(msg, actionOwners) {
+		m.showOwners = false
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k", "K":
+		if m.ownerSelected > 0 {
+			m.ownerSelected--
+		}
+	case "down", "j", "J":
+		if m.ownerSelected < len(users)-1 {
+			m.ownerSelected++
+		}
+	case "enter", "right", "l", "L":
+		if m.ownerSelected < len(users) {
+			m.showOwners = false
+			m.applyOwnerFilter(users[m.ownerSelected])
+		}
+	case "a", "A":
+		m.showOwners = false
+		m.clearOwnerFilter()
+		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+	}
+	return m, nil
+}
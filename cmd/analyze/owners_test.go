@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestScanCollectsOwnership(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "dir"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, name := range []string{"dir/a.bin", "top.bin"} {
+		if err := os.WriteFile(filepath.Join(root, name), make([]byte, 8192), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	var files, dirs, bytes int64
+	current := &atomic.Value{}
+	current.Store("")
+	result, err := scanPathConcurrent(root, &files, &dirs, &bytes, current)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent: %v", err)
+	}
+
+	uid := uint32(os.Getuid())
+	if len(result.Owners.Users) != 1 || result.Owners.Users[0].ID != uid {
+		t.Fatalf("expected a single owner %d, got %+v", uid, result.Owners.Users)
+	}
+	if got := result.Owners.Users[0]; got.Size != result.TotalSize || got.Files != 2 {
+		t.Fatalf("expected owner to hold %d bytes in 2 files, got %+v", result.TotalSize, got)
+	}
+	if len(result.Owners.Groups) == 0 {
+		t.Fatal("expected group totals")
+	}
+	for _, entry := range result.Entries {
+		if entry.OwnerSizes[uid] != entry.Size {
+			t.Fatalf("entry %s: owner size %d, entry size %d", entry.Name, entry.OwnerSizes[uid], entry.Size)
+		}
+	}
+}
+
+func TestFilterEntriesByOwner(t *testing.T) {
+	entries := []dirEntry{
+		{Name: "shared", Size: 300, OwnerSizes: map[uint32]int64{501: 100, 502: 200}},
+		{Name: "mine", Size: 150, OwnerSizes: map[uint32]int64{501: 150}},
+		{Name: "theirs", Size: 50, OwnerSizes: map[uint32]int64{502: 50}},
+	}
+
+	filtered, total := filterEntriesByOwner(entries, 501)
+	if total != 250 || len(filtered) != 2 {
+		t.Fatalf("expected 2 entries totalling 250, got %d entries, %d", len(filtered), total)
+	}
+	if filtered[0].Name != "mine" || filtered[1].Size != 100 {
+		t.Fatalf("expected entries sized to the owner's share, got %+v", filtered)
+	}
+	if entries[0].Size != 300 {
+		t.Fatal("filtering must not modify the original entries")
+	}
+}
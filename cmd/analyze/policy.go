@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+// deletePolicy decides what the analyzer may delete and how much confirmation it needs.
+type deletePolicy struct {
+	readOnly  bool
+	home      string
+	whitelist []string // Expanded patterns from ~/.config/mole/whitelist.
+}
+
+// protectedPathError explains why a path cannot be deleted.
+type protectedPathError struct {
+	path   string
+	reason string
+}
+
+func (e *protectedPathError) Error() string {
+	return fmt.Sprintf("%s is protected: %s", displayPath(e.path), e.reason)
+}
+
+// Top-level directories that are never deleted as a whole.
+var protectedRoots = map[string]bool{
+	"/":             true,
+	"/Applications": true,
+	"/Library":      true,
+	"/System":       true,
+	"/Users":        true,
+	"/Volumes":      true,
+	"/Network":      true,
+	"/private":      true,
+	"/cores":        true,
+	"/bin":          true,
+	"/sbin":         true,
+	"/usr":          true,
+	"/etc":          true,
+	"/var":          true,
+	"/opt":          true,
+	"/dev":          true,
+	"/tmp":          true,
+	"/home":         true,
+	"/root":         true,
+	"/boot":         true,
+	"/lib":          true,
+	"/lib64":        true,
+	"/proc":         true,
+	"/sys":          true,
+	"/srv":          true,
+	"/mnt":          true,
+	"/media":        true,
+}
+
+// System trees whose contents are protected too (same list bin/clean.sh rejects).
+var protectedTrees = []string{
+	"/System",
+	"/bin",
+	"/sbin",
+	"/usr/bin",
+	"/usr/sbin",
+	"/etc",
+	"/var/db",
+}
+
+var whitelistPathPattern = regexp.MustCompile(`^[a-zA-Z0-9/_.@ *-]+$`)
+
+func getWhitelistPath(home string) string {
+	return filepath.Join(home, ".config", "mole", "whitelist")
+}
+
+func loadDeletePolicy(readOnly bool) deletePolicy {
+	home, _ := os.UserHomeDir()
+	policy := deletePolicy{readOnly: readOnly, home: home}
+	if home != "" {
+		policy.whitelist = loadWhitelistPatterns(getWhitelistPath(home), home)
+	}
+	return policy
+}
+
+// loadWhitelistPatterns parses the whitelist with the same rules as bin/clean.sh.
+// Invalid lines are skipped; the shell side reports them as warnings.
+func loadWhitelistPatterns(path, home string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close() //nolint:errcheck
+
+	var patterns []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "~") {
+			line = home + strings.TrimPrefix(line, "~")
+		}
+		line = strings.ReplaceAll(line, "${HOME}", home)
+		line = strings.ReplaceAll(line, "$HOME", home)
+
+		if strings.Contains(line, "..") || strings.Contains(line, "//") {
+			continue
+		}
+		if !whitelistPathPattern.MatchString(line) || !strings.HasPrefix(line, "/") {
+			continue
+		}
+
+		line = strings.TrimSuffix(line, "/")
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// isPathWhitelisted mirrors is_path_whitelisted in lib/core/app_protection.sh:
+// exact or glob match, parents of whitelisted paths, and children of plain paths.
+func isPathWhitelisted(path string, patterns []string) bool {
+	target := strings.TrimSuffix(path, "/")
+	if target == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		hasGlob := strings.Contains(pattern, "*")
+		if target == pattern || (hasGlob && matchShellGlob(pattern, target)) {
+			return true
+		}
+		if strings.HasPrefix(pattern, target+"/") {
+			return true
+		}
+		if !hasGlob && strings.HasPrefix(target, pattern+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchShellGlob matches like bash [[ == ]], where * also crosses slashes.
+func matchShellGlob(pattern, target string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(target)
+}
+
+// check returns an error when path must not be deleted. Read-only mode is
+// handled by the caller so the refusal can be reported once, not per path.
+func (p deletePolicy) check(path string) error {
+	clean := filepath.Clean(path)
+	if !filepath.IsAbs(clean) {
+		return &protectedPathError{path: path, reason: "not an absolute path"}
+	}
+	if protectedRoots[clean] {
+		return &protectedPathError{path: path, reason: "system root"}
+	}
+	for _, tree := range protectedTrees {
+		if clean == tree || strings.HasPrefix(clean, tree+"/") {
+			return &protectedPathError{path: path, reason: "system path"}
+		}
+	}
+	if p.home != "" && (clean == p.home || clean == filepath.Join(p.home, "Library")) {
+		return &protectedPathError{path: path, reason: "home folder"}
+	}
+	if isVolumeRoot(clean) {
+		return &protectedPathError{path: path, reason: "volume root"}
+	}
+	if isPathWhitelisted(clean, p.whitelist) {
+		return &protectedPathError{path: path, reason: "listed in ~/.config/mole/whitelist"}
+	}
+	return nil
+}
+
+// checkAll validates every path and returns the first refusal.
+func (p deletePolicy) checkAll(paths []string) error {
+	for _, path := range paths {
+		if err := p.check(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// needsTypedConfirm reports whether a delete must be confirmed by typing.
+func needsTypedConfirm(totalSize int64, permanent bool) bool {
+	return permanent || totalSize >= typedConfirmThreshold
+}
+
+func deviceID(path string) (uint64, bool) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true
+}
+
+// isVolumeRoot reports whether path is the mount point of a filesystem.
+func isVolumeRoot(path string) bool {
+	if filepath.Dir(path) == "/Volumes" {
+		return true
+	}
+	dev, ok := deviceID(path)
+	if !ok {
+		return false
+	}
+	parentDev, ok := deviceID(filepath.Dir(path))
+	return ok && dev != parentDev
+}
+
+// volumeRoot walks up from path to the mount point of its filesystem.
+func volumeRoot(path string) string {
+	dev, ok := deviceID(path)
+	if !ok {
+		return "/"
+	}
+	current := path
+	for current != "/" {
+		parent := filepath.Dir(current)
+		parentDev, ok := deviceID(parent)
+		if !ok || parentDev != dev {
+			return current
+		}
+		current = parent
+	}
+	return "/"
+}
+
+// trashDirFor returns the Trash folder a path would be moved to, or "" when
+// its volume cannot have one (network shares, read-only media). Finder creates
+// the folder on first use, so a local volume without one yet still counts.
+func trashDirFor(path, home string) string {
+	root := volumeRoot(path)
+	if home != "" && root == volumeRoot(home) {
+		return filepath.Join(home, ".Trash")
+	}
+	trash := filepath.Join(root, ".Trashes", fmt.Sprint(os.Getuid()))
+	if _, err := os.Stat(filepath.Dir(trash)); err == nil {
+		return trash
+	}
+	if scan.DetectDevice(root).Kind == scan.DeviceNetwork || unix.Access(root, unix.W_OK) != nil {
+		return ""
+	}
+	return trash
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLoadWhitelistPatterns(t *testing.T) {
+	home := "/Users/tester"
+	path := filepath.Join(t.TempDir(), "whitelist")
+	content := `# comment
+
+~/Library/Caches/ms-playwright*
+$HOME/.m2/repository/
+${HOME}/Projects/keep
+/tmp/../etc
+relative/path
+/bad|chars
+~/Library/Caches/ms-playwright*
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write whitelist: %v", err)
+	}
+
+	got := loadWhitelistPatterns(path, home)
+	want := []string{
+		"/Users/tester/Library/Caches/ms-playwright*",
+		"/Users/tester/.m2/repository",
+		"/Users/tester/Projects/keep",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pattern %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	if patterns := loadWhitelistPatterns(filepath.Join(t.TempDir(), "missing"), home); patterns != nil {
+		t.Fatalf("expected no patterns for missing file, got %v", patterns)
+	}
+}
+
+func TestIsPathWhitelisted(t *testing.T) {
+	patterns := []string{
+		"/Users/tester/Library/Caches/ms-playwright*",
+		"/Users/tester/Projects/keep",
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/Users/tester/Projects/keep", true},
+		{"/Users/tester/Projects/keep/src", true},
+		{"/Users/tester/Projects", true}, // Parent of a protected path.
+		{"/Users/tester/Projects/other", false},
+		{"/Users/tester/Library/Caches/ms-playwright", true},
+		{"/Users/tester/Library/Caches/ms-playwright/chromium-1091", true},
+		{"/Users/tester/Library/Caches/other", false},
+	}
+	for _, tt := range tests {
+		if got := isPathWhitelisted(tt.path, patterns); got != tt.want {
+			t.Errorf("isPathWhitelisted(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDeletePolicyCheck(t *testing.T) {
+	base := t.TempDir()
+	home := filepath.Join(base, "home")
+	project := filepath.Join(home, "project")
+	kept := filepath.Join(home, "kept")
+	for _, dir := range []string{project, kept} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+
+	policy := deletePolicy{home: home, whitelist: []string{kept}}
+
+	refused := []string{"/", "/System", "/System/Library", "/usr/bin/ls", "/Users", "/Volumes/External", home, filepath.Join(home, "Library"), kept, "relative"}
+	for _, path := range refused {
+		if err := policy.check(path); err == nil {
+			t.Errorf("expected %q to be protected", path)
+		}
+	}
+
+	if err := policy.check(project); err != nil {
+		t.Errorf("expected %q to be deletable, got %v", project, err)
+	}
+	if err := policy.checkAll([]string{project, kept}); err == nil {
+		t.Error("expected checkAll to refuse when any path is protected")
+	}
+}
+
+func TestNeedsTypedConfirm(t *testing.T) {
+	if needsTypedConfirm(1<<20, false) {
+		t.Error("small trash delete should not need typed confirmation")
+	}
+	if !needsTypedConfirm(typedConfirmThreshold, false) {
+		t.Error("delete at threshold should need typed confirmation")
+	}
+	if !needsTypedConfirm(0, true) {
+		t.Error("permanent delete should always need typed confirmation")
+	}
+}
+
+func TestRemovePathWithProgress(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := filepath.Join(t.TempDir(), "target")
+	if err := os.MkdirAll(filepath.Join(target, "nested"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, name := range []string{"a.txt", "nested/b.txt"} {
+		if err := os.WriteFile(filepath.Join(target, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	var counter int64
+	count, err := removePathWithProgress(target, &counter)
+	if err != nil {
+		t.Fatalf("removePathWithProgress returned error: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 files removed, got %d", count)
+	}
+	if _, err := os.Lstat(target); !os.IsNotExist(err) {
+		t.Fatalf("expected target to be removed, err=%v", err)
+	}
+}
+
+func TestTrashDirForVolumeWithoutTrashes(t *testing.T) {
+	home := t.TempDir()
+	if got := trashDirFor(filepath.Join(home, "file"), home); got != filepath.Join(home, ".Trash") {
+		t.Fatalf("expected the home Trash, got %q", got)
+	}
+
+	// Finder creates .Trashes on first use, so its absence is not a reason to skip Trash.
+	other := otherVolume(t, home)
+	if got := trashDirFor(filepath.Join(other, "file"), home); got == "" {
+		t.Fatal("expected a writable local volume to count as trashable")
+	}
+}
+
+func TestTrashFailureOffersPermanentDelete(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "big.bin")
+	writeFileWithSize(t, target, 100)
+
+	m := model{path: root, entries: []dirEntry{{Name: "big.bin", Path: target, Size: 4096}}, deleteTarget: &dirEntry{Name: "big.bin", Path: target, Size: 4096}}
+	next, _ := m.Update(deleteProgressMsg{done: true, err: errors.New("failed to move to Trash"), path: target, untrashed: []string{target}})
+	m = next.(model)
+	if !m.deleteConfirm || !m.deletePermanent || !m.deleteNoTrash {
+		t.Fatal("expected a failed trash to ask for a permanent delete")
+	}
+	if paths := m.pendingDeletePaths(); len(paths) != 1 || paths[0] != target || m.pendingDeleteSize() != 4096 {
+		t.Fatalf("expected the failed file to stay pending, got %v", paths)
+	}
+
+	// The confirm flow clears deletePermanent, but progress must still say where files go.
+	for _, r := range typedConfirmWord {
+		next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(model)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = next.(model); !m.deleting {
+		t.Fatalf("expected the permanent delete to start, status %q", m.status)
+	}
+	atomic.StoreInt64(m.deleteCount, 3)
+	next, _ = m.Update(tickMsg(time.Now()))
+	if m = next.(model); !strings.HasPrefix(m.status, "Permanently deleting") {
+		t.Fatalf("expected progress to mention a permanent delete, got %q", m.status)
+	}
+}
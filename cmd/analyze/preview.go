@@ -0,0 +1,730 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif" // Decoders for image.DecodeConfig.
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// preview describes one entry for the side pane. Everything is read with the
+// standard library; formats it cannot parse fall back to size and type.
+type preview struct {
+	Path      string
+	Title     string // What the entry is, e.g. "ZIP archive"
+	Facts     []previewFact
+	BodyTitle string   // Heading over Body
+	Body      []string // File head, archive members or largest children
+	Err       error
+}
+
+type previewFact struct {
+	Label string
+	Value string
+}
+
+type previewMsg struct {
+	preview preview
+}
+
+func (p *preview) fact(label, value string) {
+	p.Facts = append(p.Facts, previewFact{Label: label, Value: value})
+}
+
+// previewCmd reads path in the background. children are the entries of an
+// already scanned folder, nil to look in the disk cache.
+func previewCmd(path string, children []dirEntry) tea.Cmd {
+	return func() tea.Msg {
+		return previewMsg{preview: buildPreview(path, children)}
+	}
+}
+
+func buildPreview(path string, children []dirEntry) preview {
+	p := preview{Path: path}
+	info, err := os.Lstat(path)
+	if err != nil {
+		p.Err = err
+		return p
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		p.Title = "Symlink"
+		if target, err := os.Readlink(path); err == nil {
+			p.fact("Target", target)
+		}
+		return p
+	case info.IsDir():
+		p.Err = previewDir(&p, children)
+		return p
+	case !info.Mode().IsRegular():
+		p.Title = "Special file"
+		return p
+	}
+
+	p.fact("Size", humanizeBytes(info.Size()))
+	p.fact("Modified", formatPreviewTime(info.ModTime()))
+	f, err := os.Open(path)
+	if err != nil {
+		p.Err = err
+		return p
+	}
+	defer f.Close() //nolint:errcheck
+
+	head := make([]byte, previewHeadBytes)
+	n, _ := f.ReadAt(head, 0)
+	p.Err = previewFile(&p, f, info.Size(), head[:n])
+	return p
+}
+
+// previewFile picks a parser from the file's leading bytes, then its name.
+func previewFile(p *preview, f *os.File, size int64, head []byte) error {
+	name := strings.ToLower(p.Path)
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")) || bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		return previewZip(p, f, size)
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		if strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz") {
+			gz, err := gzip.NewReader(io.LimitReader(f, previewTarBudget))
+			if err != nil {
+				return err
+			}
+			p.Title = "Gzipped tar archive"
+			previewTar(p, gz, size)
+			return nil
+		}
+		return previewGzip(p, f, size)
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		p.Title = "Tar archive"
+		previewTar(p, f, 0)
+		return nil
+	case len(head) >= 12 && string(head[4:8]) == "ftyp",
+		len(head) >= 8 && strings.HasSuffix(name, ".mov") && slices.Contains([]string{"moov", "mdat", "wide", "free"}, string(head[4:8])):
+		return previewBMFF(p, f, size, head)
+	case len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == "WAVE":
+		return previewWAV(p, f, size)
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return previewFLAC(p, head)
+	}
+
+	if config, format, err := image.DecodeConfig(io.NewSectionReader(f, 0, size)); err == nil {
+		p.Title = strings.ToUpper(format) + " image"
+		p.fact("Pixels", fmt.Sprintf("%d × %d", config.Width, config.Height))
+		return nil
+	}
+
+	switch {
+	case len(head) == 0:
+		p.Title = "Empty file"
+	case isText(head):
+		p.Title = "Text file"
+		p.Body = textHead(head)
+	default:
+		p.Title = "File"
+		p.fact("Type", strings.TrimSuffix(http.DetectContentType(head), "; charset=utf-8"))
+	}
+	return nil
+}
+
+// previewDir lists the folder's largest children from the last scan and the
+// newest and oldest of its direct entries.
+func previewDir(p *preview, children []dirEntry) error {
+	p.Title = "Folder"
+	dirents, err := os.ReadDir(p.Path)
+	if err != nil {
+		return err
+	}
+	p.fact("Items", formatNumber(int64(len(dirents))))
+
+	var newest, oldest os.FileInfo
+	for _, d := range dirents {
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		if newest == nil || info.ModTime().After(newest.ModTime()) {
+			newest = info
+		}
+		if oldest == nil || info.ModTime().Before(oldest.ModTime()) {
+			oldest = info
+		}
+	}
+	if newest != nil {
+		p.fact("Newest", fmt.Sprintf("%s, %s", formatPreviewTime(newest.ModTime()), newest.Name()))
+		p.fact("Oldest", fmt.Sprintf("%s, %s", formatPreviewTime(oldest.ModTime()), oldest.Name()))
+	}
+
+	if children == nil {
+		if cached, err := loadStaleCacheFromDisk(p.Path); err == nil {
+			children = cached.Entries
+		}
+	}
+	if len(children) == 0 {
+		p.BodyTitle = "Not scanned yet"
+		return nil
+	}
+	children = slices.Clone(children)
+	slices.SortStableFunc(children, func(a, b dirEntry) int { return cmp.Compare(b.Size, a.Size) })
+	p.BodyTitle = "Largest items"
+	for _, child := range children[:min(len(children), previewTopChildren)] {
+		name := child.Name
+		if child.IsDir {
+			name += "/"
+		}
+		p.Body = append(p.Body, fmt.Sprintf("%9s  %s", humanizeBytes(child.Size), name))
+	}
+	return nil
+}
+
+// previewZip reads the central directory, which holds every member's sizes.
+func previewZip(p *preview, f *os.File, size int64) error {
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return err
+	}
+	p.Title = "ZIP archive"
+
+	var packed, unpacked int64
+	var members []archiveMember
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+		packed += int64(zf.CompressedSize64)
+		unpacked += int64(zf.UncompressedSize64)
+		members = append(members, archiveMember{Name: zf.Name, Packed: int64(zf.CompressedSize64), Size: int64(zf.UncompressedSize64)})
+	}
+	p.fact("Members", formatNumber(int64(len(members))))
+	p.fact("Unpacked", fmt.Sprintf("%s from %s", humanizeBytes(unpacked), humanizeBytes(packed)))
+	p.listMembers(members)
+	return nil
+}
+
+// previewTar walks the tar headers. Compressed archives only record the
+// total, so packed is the archive size, or 0 for a plain tar; a .tar.gz stops
+// after previewTarBudget bytes.
+func previewTar(p *preview, r io.Reader, packed int64) {
+	tr := tar.NewReader(r)
+	var members []archiveMember
+	var count, unpacked int64
+	complete := true
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			complete = false
+			break
+		}
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		count++
+		unpacked += hdr.Size
+		members = append(members, archiveMember{Name: hdr.Name, Packed: -1, Size: hdr.Size})
+		// Keep only the largest so huge archives stay cheap.
+		if len(members) >= 4*previewMembers {
+			members = largestMembers(members)
+		}
+	}
+
+	if complete {
+		p.fact("Members", formatNumber(count))
+	} else {
+		p.fact("Members", fmt.Sprintf("%s+, listing stopped", formatNumber(count)))
+	}
+	if packed > 0 {
+		p.fact("Unpacked", fmt.Sprintf("%s from %s", humanizeBytes(unpacked), humanizeBytes(packed)))
+	} else {
+		p.fact("Unpacked", humanizeBytes(unpacked))
+	}
+	p.listMembers(members)
+}
+
+// previewGzip reports the original name and the uncompressed size from the
+// trailer, which holds it modulo 4GB.
+func previewGzip(p *preview, f *os.File, size int64) error {
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	p.Title = "Gzip file"
+	if gz.Name != "" {
+		p.fact("Contains", gz.Name)
+	}
+	var trailer [4]byte
+	if size < 4<<30 {
+		if _, err := f.ReadAt(trailer[:], size-4); err == nil {
+			p.fact("Unpacked", humanizeBytes(int64(binary.LittleEndian.Uint32(trailer[:]))))
+		}
+	}
+	return nil
+}
+
+type archiveMember struct {
+	Name   string
+	Packed int64 // -1 when the format does not record it
+	Size   int64
+}
+
+func largestMembers(members []archiveMember) []archiveMember {
+	slices.SortStableFunc(members, func(a, b archiveMember) int { return cmp.Compare(b.Size, a.Size) })
+	return members[:min(len(members), previewMembers)]
+}
+
+func (p *preview) listMembers(members []archiveMember) {
+	members = largestMembers(members)
+	if len(members) == 0 {
+		return
+	}
+	p.BodyTitle = fmt.Sprintf("%9s %9s  Largest members", "Packed", "Unpacked")
+	for _, member := range members {
+		packed := "-"
+		if member.Packed >= 0 {
+			packed = humanizeBytes(member.Packed)
+		}
+		p.Body = append(p.Body, fmt.Sprintf("%9s %9s  %s", packed, humanizeBytes(member.Size), member.Name))
+	}
+}
+
+// bmffBox is one box of an ISO base media file: MP4, MOV, HEIC or AVIF.
+type bmffBox struct {
+	Type       string
+	Start, End int64 // Payload, after the header
+}
+
+// bmffBoxes lists the boxes between start and end, stopping at the first
+// malformed one.
+func bmffBoxes(r io.ReaderAt, start, end int64) []bmffBox {
+	var boxes []bmffBox
+	var hdr [16]byte
+	for pos := start; pos+8 <= end; {
+		if _, err := r.ReadAt(hdr[:8], pos); err != nil {
+			break
+		}
+		size := int64(binary.BigEndian.Uint32(hdr[:4]))
+		payload := pos + 8
+		switch size {
+		case 0: // Runs to the end of the file.
+			size = end - pos
+		case 1: // 64-bit size follows the type.
+			if _, err := r.ReadAt(hdr[8:16], pos+8); err != nil {
+				return boxes
+			}
+			size = int64(binary.BigEndian.Uint64(hdr[8:16]))
+			payload += 8
+		}
+		if size < payload-pos || size > end-pos {
+			break
+		}
+		boxes = append(boxes, bmffBox{Type: string(hdr[4:8]), Start: payload, End: pos + size})
+		pos += size
+	}
+	return boxes
+}
+
+// bmffChildren lists the boxes inside box; meta has a version header first.
+func bmffChildren(r io.ReaderAt, box bmffBox) []bmffBox {
+	start := box.Start
+	if box.Type == "meta" {
+		start += 4
+	}
+	return bmffBoxes(r, start, box.End)
+}
+
+// bmffFind descends through the named boxes, taking the first match at each level.
+func bmffFind(r io.ReaderAt, boxes []bmffBox, path ...string) (bmffBox, bool) {
+	for i, typ := range path {
+		idx := slices.IndexFunc(boxes, func(b bmffBox) bool { return b.Type == typ })
+		if idx < 0 {
+			return bmffBox{}, false
+		}
+		if i == len(path)-1 {
+			return boxes[idx], true
+		}
+		boxes = bmffChildren(r, boxes[idx])
+	}
+	return bmffBox{}, false
+}
+
+// bmffRead returns up to n bytes of the box payload.
+func bmffRead(r io.ReaderAt, box bmffBox, n int) []byte {
+	buf := make([]byte, min(int64(n), box.End-box.Start))
+	read, _ := r.ReadAt(buf, box.Start)
+	return buf[:read]
+}
+
+var bmffCodecs = map[string]string{
+	"avc1": "H.264", "avc3": "H.264", "hvc1": "HEVC", "hev1": "HEVC", "av01": "AV1", "vp09": "VP9",
+	"apch": "ProRes 422 HQ", "apcn": "ProRes 422", "apcs": "ProRes 422 LT", "apco": "ProRes 422 Proxy",
+	"ap4h": "ProRes 4444", "mp4v": "MPEG-4 Visual", "jpeg": "Motion JPEG",
+	"mp4a": "AAC", "ac-3": "AC-3", "ec-3": "E-AC-3", "alac": "ALAC", "Opus": "Opus", "fLaC": "FLAC",
+	"lpcm": "PCM", "sowt": "PCM", "twos": "PCM",
+}
+
+// previewBMFF reads the movie header and track sample descriptions, or the
+// image size of HEIF and AVIF files.
+func previewBMFF(p *preview, f *os.File, size int64, head []byte) error {
+	top := bmffBoxes(f, 0, size)
+	brand := ""
+	if string(head[4:8]) == "ftyp" {
+		brand = string(head[8:12])
+	}
+
+	switch brand {
+	case "heic", "heix", "heim", "heis", "mif1", "msf1", "avif":
+		p.Title = "HEIF image"
+		if brand == "avif" {
+			p.Title = "AVIF image"
+		}
+		ipco, ok := bmffFind(f, top, "meta", "iprp", "ipco")
+		if !ok {
+			return nil
+		}
+		// Thumbnails have their own ispe; the primary image is the largest.
+		var width, height uint32
+		for _, box := range bmffChildren(f, ipco) {
+			if b := bmffRead(f, box, 12); box.Type == "ispe" && len(b) == 12 {
+				w, h := binary.BigEndian.Uint32(b[4:8]), binary.BigEndian.Uint32(b[8:12])
+				if uint64(w)*uint64(h) > uint64(width)*uint64(height) {
+					width, height = w, h
+				}
+			}
+		}
+		if width > 0 {
+			p.fact("Pixels", fmt.Sprintf("%d × %d", width, height))
+		}
+		return nil
+	}
+
+	moov, ok := bmffFind(f, top, "moov")
+	if !ok {
+		p.Title = "MPEG-4 file"
+		return nil
+	}
+	tracks := bmffChildren(f, moov)
+	if mvhd, ok := bmffFind(f, tracks, "mvhd"); ok {
+		if duration, ok := mvhdDuration(bmffRead(f, mvhd, 32)); ok {
+			p.fact("Duration", formatDuration(duration))
+		}
+	}
+
+	var video, audio []string
+	for _, trak := range tracks {
+		if trak.Type != "trak" {
+			continue
+		}
+		media := bmffChildren(f, trak)
+		hdlr, ok := bmffFind(f, media, "mdia", "hdlr")
+		if !ok {
+			continue
+		}
+		handler := bmffRead(f, hdlr, 12)
+		stsd, ok := bmffFind(f, media, "mdia", "minf", "stbl", "stsd")
+		if len(handler) < 12 || !ok {
+			continue
+		}
+		// Version and entry count, then the first sample entry's size and format.
+		entry := bmffRead(f, stsd, 44)
+		if len(entry) < 16 {
+			continue
+		}
+		format := string(entry[12:16])
+		codec := cmp.Or(bmffCodecs[format], format)
+		switch string(handler[8:12]) {
+		case "vide":
+			if len(entry) >= 44 {
+				codec += fmt.Sprintf(" %d × %d", binary.BigEndian.Uint16(entry[40:42]), binary.BigEndian.Uint16(entry[42:44]))
+			}
+			video = append(video, codec)
+		case "soun":
+			audio = append(audio, codec)
+		}
+	}
+
+	switch {
+	case brand == "qt  " || brand == "":
+		p.Title = "QuickTime movie"
+	case len(video) == 0 && len(audio) > 0:
+		p.Title = "MPEG-4 audio"
+	default:
+		p.Title = "MPEG-4 video"
+	}
+	if len(video) > 0 {
+		p.fact("Video", strings.Join(video, ", "))
+	}
+	if len(audio) > 0 {
+		p.fact("Audio", strings.Join(audio, ", "))
+	}
+	return nil
+}
+
+// mvhdDuration decodes the movie length from an mvhd payload.
+func mvhdDuration(b []byte) (time.Duration, bool) {
+	var scale uint32
+	var units uint64
+	switch {
+	case len(b) >= 20 && b[0] == 0:
+		scale, units = binary.BigEndian.Uint32(b[12:16]), uint64(binary.BigEndian.Uint32(b[16:20]))
+	case len(b) >= 32 && b[0] == 1:
+		scale, units = binary.BigEndian.Uint32(b[20:24]), binary.BigEndian.Uint64(b[24:32])
+	default:
+		return 0, false
+	}
+	if scale == 0 {
+		return 0, false
+	}
+	return time.Duration(float64(units) / float64(scale) * float64(time.Second)), true
+}
+
+// previewWAV reads the fmt chunk and works out the length from the data chunk.
+func previewWAV(p *preview, f *os.File, size int64) error {
+	p.Title = "WAV audio"
+	var format, channels, bits uint16
+	var rate, byteRate uint32
+	var chunk [24]byte
+	for pos := int64(12); pos+8 <= size; {
+		if _, err := f.ReadAt(chunk[:8], pos); err != nil {
+			return nil
+		}
+		length := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		switch string(chunk[:4]) {
+		case "fmt ":
+			if _, err := f.ReadAt(chunk[8:24], pos+8); err != nil {
+				return nil
+			}
+			format = binary.LittleEndian.Uint16(chunk[8:10])
+			channels = binary.LittleEndian.Uint16(chunk[10:12])
+			rate = binary.LittleEndian.Uint32(chunk[12:16])
+			byteRate = binary.LittleEndian.Uint32(chunk[16:20])
+			bits = binary.LittleEndian.Uint16(chunk[22:24])
+		case "data":
+			if byteRate > 0 {
+				p.fact("Duration", formatDuration(time.Duration(float64(length)/float64(byteRate)*float64(time.Second))))
+			}
+		}
+		pos += 8 + length + length%2
+	}
+
+	if rate == 0 {
+		return nil
+	}
+	codec := fmt.Sprintf("format 0x%04x", format)
+	switch format {
+	case 1, 0xfffe:
+		codec = fmt.Sprintf("PCM %d-bit", bits)
+	case 3:
+		codec = fmt.Sprintf("PCM %d-bit float", bits)
+	}
+	p.fact("Audio", fmt.Sprintf("%s, %s, %s", codec, formatSampleRate(rate), channelLabel(int(channels))))
+	return nil
+}
+
+// previewFLAC decodes STREAMINFO, which FLAC requires as the first block.
+func previewFLAC(p *preview, head []byte) error {
+	p.Title = "FLAC audio"
+	if len(head) < 26 || head[4]&0x7f != 0 {
+		return nil
+	}
+	s := head[8:]
+	rate := uint32(s[10])<<12 | uint32(s[11])<<4 | uint32(s[12])>>4
+	channels := int(s[12]>>1&0x07) + 1
+	bits := (int(s[12]&0x01)<<4 | int(s[13]>>4)) + 1
+	samples := uint64(s[13]&0x0f)<<32 | uint64(binary.BigEndian.Uint32(s[14:18]))
+	if rate == 0 {
+		return nil
+	}
+	if samples > 0 {
+		p.fact("Duration", formatDuration(time.Duration(float64(samples)/float64(rate)*float64(time.Second))))
+	}
+	p.fact("Audio", fmt.Sprintf("FLAC %d-bit, %s, %s", bits, formatSampleRate(rate), channelLabel(channels)))
+	return nil
+}
+
+// isText reports whether head looks like UTF-8 text. The read may end inside
+// a multi-byte character.
+func isText(head []byte) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	for i := 0; i < utf8.UTFMax-1 && !utf8.Valid(head); i++ {
+		head = head[:len(head)-1]
+	}
+	return utf8.Valid(head)
+}
+
+// textHead returns the first lines of head with tabs expanded and other
+// control characters dropped.
+func textHead(head []byte) []string {
+	var lines []string
+	for line := range strings.Lines(string(head)) {
+		if len(lines) == previewTextLines {
+			break
+		}
+		line = strings.ReplaceAll(strings.TrimRight(line, "\r\n"), "\t", "    ")
+		lines = append(lines, strings.Map(func(r rune) rune {
+			if r < ' ' || r == 0x7f || r == utf8.RuneError {
+				return -1
+			}
+			return r
+		}, line))
+	}
+	return lines
+}
+
+func formatPreviewTime(t time.Time) string {
+	return fmt.Sprintf("%s (%s)", t.Format("2006-01-02 15:04"), formatAge(t))
+}
+
+// formatDuration formats a media length as h:mm:ss or m:ss.
+func formatDuration(d time.Duration) string {
+	total := int64(d.Round(time.Second) / time.Second)
+	hours, minutes, seconds := total/3600, total/60%60, total%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
+func formatSampleRate(rate uint32) string {
+	if rate%1000 == 0 {
+		return fmt.Sprintf("%d kHz", rate/1000)
+	}
+	return fmt.Sprintf("%.1f kHz", float64(rate)/1000)
+}
+
+func channelLabel(channels int) string {
+	switch channels {
+	case 1:
+		return "mono"
+	case 2:
+		return "stereo"
+	default:
+		return fmt.Sprintf("%d channels", channels)
+	}
+}
+
+// previewWidth is the side pane's width, a third of the terminal.
+func previewWidth(termWidth int) int {
+	if termWidth <= 0 {
+		return previewMinWidth
+	}
+	return min(max(termWidth/3, previewMinWidth), previewMaxWidth)
+}
+
+// listWidth is the width left for the entry list.
+func (m model) listWidth() int {
+	if !m.showPreview {
+		return m.width
+	}
+	return m.width - previewWidth(m.width)
+}
+
+// previewTarget is the entry under the cursor in the list on screen.
+func (m model) previewTarget() (dirEntry, bool) {
+	if m.compareMode {
+		return dirEntry{}, false
+	}
+	if m.showLargeFiles {
+		if m.largeSelected >= 0 && m.largeSelected < len(m.largeFiles) {
+			file := m.largeFiles[m.largeSelected]
+			return dirEntry{Name: file.Name, Path: file.Path, Size: file.Size}, true
+		}
+		return dirEntry{}, false
+	}
+	if m.selected >= 0 && m.selected < len(m.entries) {
+		return m.entries[m.selected], true
+	}
+	return dirEntry{}, false
+}
+
+// togglePreview shows or hides the pane; syncPreview fills it in.
+func (m *model) togglePreview() {
+	m.showPreview = !m.showPreview
+	m.preview = nil
+	m.previewPath = ""
+}
+
+// syncPreview starts reading the selected entry when the pane is open and the
+// selection has moved since the last read.
+func syncPreview(next tea.Model, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	m, ok := next.(model)
+	if !ok || !m.showPreview {
+		return next, cmd
+	}
+	target, ok := m.previewTarget()
+	if !ok {
+		m.preview = nil
+		m.previewPath = ""
+		return m, cmd
+	}
+	if target.Path == m.previewPath {
+		return next, cmd
+	}
+	m.previewPath = target.Path
+	m.preview = nil
+	var children []dirEntry
+	if cached, ok := m.cache[target.Path]; ok {
+		children = cached.Entries
+	}
+	return m, tea.Batch(cmd, previewCmd(target.Path, children))
+}
+
+// handlePreview keeps a finished read if it is still for the selected entry.
+func (m model) handlePreview(msg previewMsg) (tea.Model, tea.Cmd) {
+	if !m.showPreview || msg.preview.Path != m.previewPath {
+		return m, nil
+	}
+	p := msg.preview
+	m.preview = &p
+	return m, nil
+}
+
+// previewLines renders the pane's rows, width columns wide.
+func (m model) previewLines(width int) []string {
+	if m.previewPath == "" {
+		return []string{colorGray + "Nothing selected" + colorReset}
+	}
+	lines := []string{colorBold + trimNameWithWidth(filepath.Base(m.previewPath), width) + colorReset}
+	p := m.preview
+	if p == nil {
+		return append(lines, colorGray+"Reading..."+colorReset)
+	}
+	if p.Title != "" {
+		lines = append(lines, colorCyan+p.Title+colorReset)
+	}
+	for _, f := range p.Facts {
+		lines = append(lines, fmt.Sprintf("%s%-9s%s %s", colorGray, f.Label, colorReset, trimNameWithWidth(f.Value, width-10)))
+	}
+	if p.Err != nil {
+		lines = append(lines, colorRed+trimNameWithWidth(p.Err.Error(), width)+colorReset)
+	}
+	if p.BodyTitle != "" || len(p.Body) > 0 {
+		lines = append(lines, "")
+	}
+	if p.BodyTitle != "" {
+		lines = append(lines, colorGray+trimNameWithWidth(p.BodyTitle, width)+colorReset)
+	}
+	for _, line := range p.Body {
+		lines = append(lines, trimNameWithWidth(line, width))
+	}
+	return lines
+}
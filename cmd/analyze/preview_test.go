@@ -0,0 +1,252 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func previewFactValue(p preview, label string) string {
+	for _, f := range p.Facts {
+		if f.Label == label {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+func TestPreviewZipListsMembers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundle.zip")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"logs/app.log": strings.Repeat("compressible line\n", 5000),
+		"notes.txt":    "hello",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("write zip: %v", err)
+	}
+
+	p := buildPreview(path, nil)
+	if p.Err != nil || p.Title != "ZIP archive" {
+		t.Fatalf("expected a ZIP archive, got %q, err=%v", p.Title, p.Err)
+	}
+	if got := previewFactValue(p, "Members"); got != "2" {
+		t.Fatalf("expected 2 members, got %q", got)
+	}
+	if len(p.Body) != 2 || !strings.HasSuffix(p.Body[0], "logs/app.log") {
+		t.Fatalf("expected the largest member first, got %q", p.Body)
+	}
+	if fields := strings.Fields(p.Body[0]); fields[0] == fields[1] {
+		t.Fatalf("expected different packed and unpacked sizes, got %q", p.Body[0])
+	}
+}
+
+func TestPreviewTarGzListsMembers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, size := range []int{100, 3000} {
+		name := filepath.Join("data", strings.Repeat("x", size%7+1))
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(size), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("header: %v", err)
+		}
+		if _, err := tw.Write(make([]byte, size)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("close gzip: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+
+	p := buildPreview(path, nil)
+	if p.Title != "Gzipped tar archive" || previewFactValue(p, "Members") != "2" {
+		t.Fatalf("unexpected preview: %q %+v", p.Title, p.Facts)
+	}
+	if !strings.HasPrefix(previewFactValue(p, "Unpacked"), humanizeBytes(3100)) {
+		t.Fatalf("expected the unpacked total, got %q", previewFactValue(p, "Unpacked"))
+	}
+}
+
+func TestPreviewImageAndText(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 64, 48))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "shot.png"), buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("write png: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("# Notes\n\tindented\nlast"), 0o644); err != nil {
+		t.Fatalf("write text: %v", err)
+	}
+
+	img := buildPreview(filepath.Join(dir, "shot.png"), nil)
+	if img.Title != "PNG image" || previewFactValue(img, "Pixels") != "64 × 48" {
+		t.Fatalf("unexpected image preview: %q %+v", img.Title, img.Facts)
+	}
+
+	text := buildPreview(filepath.Join(dir, "notes.md"), nil)
+	want := []string{"# Notes", "    indented", "last"}
+	if text.Title != "Text file" || strings.Join(text.Body, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected text preview: %q %q", text.Title, text.Body)
+	}
+}
+
+func bmffBoxBytes(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(box, typ...), body...)
+}
+
+func TestPreviewMP4DurationAndCodecs(t *testing.T) {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)   // Timescale
+	binary.BigEndian.PutUint32(mvhd[16:20], 125500) // 2:05.5
+	track := func(handler, format string, width, height uint16) []byte {
+		hdlr := make([]byte, 24)
+		copy(hdlr[8:12], handler)
+		entry := make([]byte, 78)
+		copy(entry[4:8], format)
+		binary.BigEndian.PutUint16(entry[32:34], width)
+		binary.BigEndian.PutUint16(entry[34:36], height)
+		binary.BigEndian.PutUint32(entry[:4], uint32(len(entry)))
+		stsd := append([]byte{0, 0, 0, 0, 0, 0, 0, 1}, entry...)
+		return bmffBoxBytes("trak", bmffBoxBytes("mdia", bmffBoxBytes("hdlr", hdlr),
+			bmffBoxBytes("minf", bmffBoxBytes("stbl", bmffBoxBytes("stsd", stsd)))))
+	}
+	data := bytes.Join([][]byte{
+		bmffBoxBytes("ftyp", []byte("isom\x00\x00\x02\x00")),
+		bmffBoxBytes("moov", bmffBoxBytes("mvhd", mvhd), track("vide", "hvc1", 1920, 1080), track("soun", "mp4a", 0, 0)),
+		bmffBoxBytes("mdat", make([]byte, 64)),
+	}, nil)
+	path := filepath.Join(t.TempDir(), "clip.mp4")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write mp4: %v", err)
+	}
+
+	p := buildPreview(path, nil)
+	if p.Title != "MPEG-4 video" {
+		t.Fatalf("expected MPEG-4 video, got %q, err=%v", p.Title, p.Err)
+	}
+	for label, want := range map[string]string{"Duration": "2:06", "Video": "HEVC 1920 × 1080", "Audio": "AAC"} {
+		if got := previewFactValue(p, label); got != want {
+			t.Errorf("%s = %q, want %q", label, got, want)
+		}
+	}
+}
+
+func TestPreviewWAV(t *testing.T) {
+	le := binary.LittleEndian
+	fmtChunk := le.AppendUint16(nil, 1) // PCM
+	fmtChunk = le.AppendUint16(fmtChunk, 2)
+	fmtChunk = le.AppendUint32(fmtChunk, 44100)
+	fmtChunk = le.AppendUint32(fmtChunk, 44100*4)
+	fmtChunk = le.AppendUint16(fmtChunk, 4)
+	fmtChunk = le.AppendUint16(fmtChunk, 16)
+	data := []byte("RIFF\x00\x00\x00\x00WAVEfmt ")
+	data = le.AppendUint32(data, uint32(len(fmtChunk)))
+	data = append(data, fmtChunk...)
+	data = append(data, "data"...)
+	data = le.AppendUint32(data, 44100*4*3)
+	data = append(data, make([]byte, 44100*4*3)...)
+	path := filepath.Join(t.TempDir(), "take.wav")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write wav: %v", err)
+	}
+
+	p := buildPreview(path, nil)
+	if got := previewFactValue(p, "Duration"); got != "0:03" {
+		t.Fatalf("expected 0:03, got %q", got)
+	}
+	if got := previewFactValue(p, "Audio"); got != "PCM 16-bit, 44.1 kHz, stereo" {
+		t.Fatalf("unexpected audio description %q", got)
+	}
+}
+
+func TestPreviewDirUsesScannedChildren(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	var children []dirEntry
+	for i := range 7 {
+		children = append(children, dirEntry{Name: string(rune('a' + i)), Size: int64(i * 100), IsDir: i == 6})
+	}
+
+	p := buildPreview(dir, children)
+	if p.Title != "Folder" || previewFactValue(p, "Items") != "2" {
+		t.Fatalf("unexpected folder preview: %q %+v", p.Title, p.Facts)
+	}
+	if previewFactValue(p, "Newest") == "" || previewFactValue(p, "Oldest") == "" {
+		t.Fatalf("expected newest and oldest entries, got %+v", p.Facts)
+	}
+	if len(p.Body) != previewTopChildren || !strings.HasSuffix(p.Body[0], " g/") {
+		t.Fatalf("expected the five largest children, got %q", p.Body)
+	}
+}
+
+func TestPreviewFollowsSelection(t *testing.T) {
+	m := model{
+		path: "/tmp/root",
+		entries: []dirEntry{
+			{Name: "a", Path: "/tmp/root/a", Size: 20},
+			{Name: "b", Path: "/tmp/root/b", Size: 10},
+		},
+	}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = next.(model)
+	if !m.showPreview || m.previewPath != "/tmp/root/a" || cmd == nil {
+		t.Fatalf("expected the pane to read the selected entry, got path %q", m.previewPath)
+	}
+
+	next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = next.(model)
+	if m.previewPath != "/tmp/root/b" || cmd == nil {
+		t.Fatalf("expected the pane to follow the cursor, got %q", m.previewPath)
+	}
+
+	next, _ = m.Update(previewMsg{preview: preview{Path: "/tmp/root/a", Title: "Folder"}})
+	if next.(model).preview != nil {
+		t.Fatal("expected a read for an earlier selection to be dropped")
+	}
+	next, _ = m.Update(previewMsg{preview: preview{Path: "/tmp/root/b", Title: "Folder"}})
+	m = next.(model)
+	if m.preview == nil || m.preview.Title != "Folder" {
+		t.Fatal("expected the read for the selected entry to be kept")
+	}
+	if view := m.View(); !strings.Contains(view, "│") || !strings.Contains(view, "Folder") {
+		t.Fatal("expected the pane beside the list")
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+// recentWindow is a span the recently written view can be narrowed to.
+type recentWindow struct {
+	label string
+	span  time.Duration
+}
+
+// recentWindows are cycled in order; the scan collects the widest.
+var recentWindows = []recentWindow{
+	{"hour", time.Hour},
+	{"day", 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+}
+
+// recentItem is a file modified within the window, or a folder holding several.
+// A folder's size counts only its recent files.
+type recentItem struct {
+	Path    string
+	Size    int64
+	Files   int
+	IsDir   bool
+	ModTime time.Time // Newest write
+}
+
+type recentResultMsg struct {
+	root  string
+	files []fileEntry
+	at    time.Time
+	err   error
+}
+
+// collectRecent scans root for files modified since since, largest first.
+// The size cache is left out so files rewritten in place are seen.
+func collectRecent(ctx context.Context, root string, since time.Time, checked *int64, currentPath *atomic.Value) ([]fileEntry, error) {
+	result, err := scan.New(scan.Options{
+		RecentSince: since,
+		OnProgress: func(p scan.Progress) {
+			if checked != nil {
+				atomic.StoreInt64(checked, p.Files)
+			}
+			if currentPath != nil && p.CurrentPath != "" {
+				currentPath.Store(p.CurrentPath)
+			}
+		},
+	}).ScanRoot(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return result.Recent, nil
+}
+
+// recentItems ranks the files modified since since and the folders under root
+// holding them. A folder is listed only when its recent files are not all in
+// one child, so a single runaway file is not repeated once per parent.
+func recentItems(root string, files []fileEntry, since time.Time) []recentItem {
+	var items []recentItem
+	dirs := make(map[string]*recentItem)
+	for _, f := range files {
+		if f.ModTime.Before(since) {
+			continue
+		}
+		items = append(items, recentItem{Path: f.Path, Size: f.Size, Files: 1, ModTime: f.ModTime})
+		for dir := filepath.Dir(f.Path); len(dir) > len(root) && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+			d, ok := dirs[dir]
+			if !ok {
+				d = &recentItem{Path: dir, IsDir: true}
+				dirs[dir] = d
+			}
+			d.Size += f.Size
+			d.Files++
+			if f.ModTime.After(d.ModTime) {
+				d.ModTime = f.ModTime
+			}
+		}
+	}
+
+	// A parent with exactly the files of one subfolder adds nothing.
+	covered := make(map[string]bool)
+	for path, d := range dirs {
+		if parent, ok := dirs[filepath.Dir(path)]; ok && parent.Files == d.Files {
+			covered[parent.Path] = true
+		}
+	}
+	for path, d := range dirs {
+		if d.Files > 1 && !covered[path] {
+			items = append(items, *d)
+		}
+	}
+
+	slices.SortFunc(items, func(a, b recentItem) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), strings.Compare(a.Path, b.Path))
+	})
+	return items
+}
+
+func recentTotal(items []recentItem) (int64, int) {
+	var bytes int64
+	var files int
+	for _, item := range items {
+		if !item.IsDir {
+			bytes += item.Size
+			files++
+		}
+	}
+	return bytes, files
+}
+
+// formatRecentAge formats how long ago a recent write was, in minutes or hours.
+func formatRecentAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+func scanRecentCmd(ctx context.Context, root string, checked *int64, currentPath *atomic.Value) tea.Cmd {
+	return func() tea.Msg {
+		at := time.Now()
+		files, err := collectRecent(ctx, root, at.Add(-recentWindows[len(recentWindows)-1].span), checked, currentPath)
+		return recentResultMsg{root: root, files: files, at: at, err: err}
+	}
+}
+
+func (m *model) openRecentView() tea.Cmd {
+	stopScan(&m.recentCancel)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.recentCancel = cancel
+	m.showRecent = true
+	m.showLargeFiles = false
+	m.recentScanning = true
+	m.recentFiles = nil
+	m.recentItems = nil
+	m.recentSelected = 0
+	m.recentOffset = 0
+	var checked int64
+	m.recentChecked = &checked
+	if m.currentPath != nil {
+		m.currentPath.Store("")
+	}
+	m.status = fmt.Sprintf("Looking for recent writes under %s...", displayPath(m.path))
+	return tea.Batch(scanRecentCmd(ctx, m.path, m.recentChecked, m.currentPath), tickCmd())
+}
+
+// setRecentFiles replaces the scanned files, dropping those no longer on disk,
+// and ranks them for the current window.
+func (m *model) setRecentFiles(files []fileEntry) {
+	m.recentFiles = slices.DeleteFunc(files, func(f fileEntry) bool {
+		_, err := os.Lstat(f.Path)
+		return err != nil
+	})
+	m.refreshRecentItems()
+}
+
+func (m *model) refreshRecentItems() {
+	window := recentWindows[m.recentWindow]
+	m.recentItems = recentItems(m.path, m.recentFiles, m.recentAt.Add(-window.span))
+	if m.recentSelected >= len(m.recentItems) {
+		m.recentSelected = max(len(m.recentItems)-1, 0)
+	}
+	viewport := calculateViewport(m.height)
+	maxOffset := max(len(m.recentItems)-viewport, 0)
+	if m.recentOffset > maxOffset {
+		m.recentOffset = maxOffset
+	}
+	if m.recentSelected < m.recentOffset {
+		m.recentOffset = m.recentSelected
+	}
+}
+
+func (m *model) updateRecentStatus() {
+	bytes, files := recentTotal(m.recentItems)
+	m.status = fmt.Sprintf("%s written in the last %s, %s files", humanizeBytes(bytes), recentWindows[m.recentWindow].label, formatNumber(int64(files)))
+}
+
+func (m model) updateRecentKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.closesView(msg, actionRecent) {
+		stopScan(&m.recentCancel)
+		m.showRecent = false
+		m.recentScanning = false
+		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+		return m, nil
+	}
+
+	if m.recentScanning {
+		return m, nil
+	}
+	switch m.keyAction(msg) {
+	case actionDelete:
+		return m.confirmRecentDelete()
+	case actionRefresh:
+		return m, m.openRecentView()
+	}
+
+	switch msg.String() {
+	case "up", "k", "K":
+		if m.recentSelected > 0 {
+			m.recentSelected--
+			if m.recentSelected < m.recentOffset {
+				m.recentOffset = m.recentSelected
+			}
+		}
+	case "down", "j", "J":
+		if m.recentSelected < len(m.recentItems)-1 {
+			m.recentSelected++
+			viewport := calculateViewport(m.height)
+			if m.recentSelected >= m.recentOffset+viewport {
+				m.recentOffset = m.recentSelected - viewport + 1
+			}
+		}
+	case "t", "T":
+		m.recentWindow = (m.recentWindow + 1) % len(recentWindows)
+		m.recentSelected = 0
+		m.recentOffset = 0
+		m.refreshRecentItems()
+		m.updateRecentStatus()
+	}
+	return m, nil
+}
+
+// confirmRecentDelete asks to trash the file under the cursor. Folders are left
+// to the main list, since here their size counts only recent files.
+func (m model) confirmRecentDelete() (tea.Model, tea.Cmd) {
+	if m.recentSelected >= len(m.recentItems) {
+		return m, nil
+	}
+	item := m.recentItems[m.recentSelected]
+	if item.IsDir {
+		m.status = "Trash folders from the main list, where their full size is shown"
+		return m, nil
+	}
+	m.deleteConfirm = true
+	m.deleteTarget = &dirEntry{Name: filepath.Base(item.Path), Path: item.Path, Size: item.Size}
+	m.prepareDeleteConfirm()
+	return m, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRecentItemsCountOnlyRecentChildren(t *testing.T) {
+	root := "/data"
+	now := time.Now()
+	files := []fileEntry{
+		{Path: "/data/logs/app/run.log", Size: 5000, ModTime: now.Add(-time.Minute)},
+		{Path: "/data/dl/part-1", Size: 1500, ModTime: now.Add(-2 * time.Minute)},
+		{Path: "/data/dl/part-2", Size: 1500, ModTime: now.Add(-3 * time.Hour)},
+		{Path: "/data/notes.txt", Size: 10, ModTime: now.Add(-30 * time.Minute)},
+	}
+
+	var got []string
+	for _, item := range recentItems(root, files, now.Add(-24*time.Hour)) {
+		rel, _ := filepath.Rel(root, item.Path)
+		got = append(got, rel)
+		if item.IsDir && (item.Size != 3000 || item.Files != 2) {
+			t.Fatalf("expected dl/ to hold 2 recent files and 3000 bytes, got %+v", item)
+		}
+	}
+	// logs/ and logs/app/ hold only run.log, so they are not listed apart from it.
+	want := []string{"logs/app/run.log", "dl", "dl/part-1", "dl/part-2", "notes.txt"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	got = got[:0]
+	for _, item := range recentItems(root, files, now.Add(-time.Hour)) {
+		got = append(got, filepath.Base(item.Path))
+	}
+	if want := []string{"run.log", "part-1", "notes.txt"}; !slices.Equal(got, want) {
+		t.Fatalf("expected the hour window to drop older writes, got %v", got)
+	}
+}
+
+func TestRecentViewCyclesWindow(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a.log"), 100)
+	writeFileWithSize(t, filepath.Join(root, "b.log"), 200)
+	now := time.Now()
+	files := []fileEntry{
+		{Path: filepath.Join(root, "a.log"), Size: 100, ModTime: now.Add(-time.Minute)},
+		{Path: filepath.Join(root, "b.log"), Size: 200, ModTime: now.Add(-2 * time.Hour)},
+		{Path: filepath.Join(root, "gone.log"), Size: 900, ModTime: now},
+	}
+
+	m := model{path: root}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	m = next.(model)
+	if !m.showRecent || !m.recentScanning {
+		t.Fatal("expected w to start the recent scan")
+	}
+	next, _ = m.Update(recentResultMsg{root: root, files: files, at: now})
+	m = next.(model)
+	if len(m.recentItems) != 1 || m.recentItems[0].Size != 100 {
+		t.Fatalf("expected only a.log in the last hour, got %+v", m.recentItems)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m = next.(model)
+	if recentWindows[m.recentWindow].label != "day" || len(m.recentItems) != 2 || m.recentItems[0].Size != 200 {
+		t.Fatalf("expected both files over a day, largest first, got %+v", m.recentItems)
+	}
+}
+
+func TestRecentCloseCancelsScan(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a.log"), 100)
+
+	m := model{path: root}
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	m = next.(model)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = next.(model); m.showRecent || m.recentCancel != nil {
+		t.Fatal("expected esc to close the view and stop its scan")
+	}
+
+	msg := cmd().(tea.BatchMsg)[0]().(recentResultMsg)
+	if !errors.Is(msg.err, context.Canceled) {
+		t.Fatalf("expected the walk to stop once the view closed, got %v", msg.err)
+	}
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/pkg/scan"
+)
+
+// reclaimContainerDirs are folded dirs that commonly hold whole projects, so the walk enters them.
+var reclaimContainerDirs = map[string]bool{
+	"tmp":  true,
+	"temp": true,
+}
+
+// reclaimArtifact is one cleanable directory found under the reclaim root.
+type reclaimArtifact struct {
+	Name  string
+	Path  string
+	Size  int64
+	Match cleanableMatch
+}
+
+// reclaimProject groups artifacts by the project directory that owns them.
+type reclaimProject struct {
+	Path      string
+	ModTime   time.Time
+	Artifacts []reclaimArtifact
+	Size      int64
+}
+
+// isStale reports whether the project has not been touched recently.
+func (p reclaimProject) isStale(now time.Time) bool {
+	return !p.ModTime.IsZero() && now.Sub(p.ModTime) >= reclaimStaleAge
+}
+
+// reclaimRow is a flattened list row: a project header (artifact == -1) or one artifact.
+type reclaimRow struct {
+	project  int
+	artifact int
+}
+
+type reclaimResultMsg struct {
+	root     string
+	projects []reclaimProject
+	err      error
+}
+
+// collectReclaimable walks root and returns every validated project artifact, grouped by project.
+func collectReclaimable(ctx context.Context, root string, found *int64, currentPath *atomic.Value) ([]reclaimProject, error) {
+	var artifacts []reclaimArtifact
+	isRootDir := root == "/"
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+
+		name := d.Name()
+		if scan.DefaultSkipDirs[name] || (isRootDir && filepath.Dir(path) == "/" && scan.DefaultSystemDirs[name]) {
+			return filepath.SkipDir
+		}
+
+		if match, ok := matchCleanableDir(path); ok {
+			artifacts = append(artifacts, reclaimArtifact{Name: name, Path: path, Match: match})
+			if found != nil {
+				atomic.AddInt64(found, 1)
+			}
+			return filepath.SkipDir
+		}
+
+		// VCS internals and tool caches never contain project artifacts worth listing.
+		if scan.DefaultFoldDirs[name] && !reclaimContainerDirs[name] {
+			return filepath.SkipDir
+		}
+
+		if currentPath != nil {
+			currentPath.Store(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sizeReclaimArtifacts(ctx, artifacts)
+	return groupReclaimArtifacts(artifacts), nil
+}
+
+// sizeReclaimArtifacts fills in artifact sizes, a few at a time.
+func sizeReclaimArtifacts(ctx context.Context, artifacts []reclaimArtifact) {
+	sem := make(chan struct{}, min(4, runtime.NumCPU()))
+	var wg sync.WaitGroup
+	for i := range artifacts {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(a *reclaimArtifact) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			a.Size, _ = scan.Size(ctx, a.Path, scan.Options{SizeCache: sizeCache})
+		}(&artifacts[i])
+	}
+	wg.Wait()
+}
+
+// groupReclaimArtifacts buckets artifacts by parent project, largest first.
+func groupReclaimArtifacts(artifacts []reclaimArtifact) []reclaimProject {
+	byProject := make(map[string]*reclaimProject)
+	var order []string
+	for _, a := range artifacts {
+		if a.Size <= 0 {
+			continue
+		}
+		projectPath := filepath.Dir(a.Path)
+		p, ok := byProject[projectPath]
+		if !ok {
+			p = &reclaimProject{Path: projectPath}
+			byProject[projectPath] = p
+			order = append(order, projectPath)
+		}
+		p.Artifacts = append(p.Artifacts, a)
+		p.Size += a.Size
+	}
+
+	projects := make([]reclaimProject, 0, len(order))
+	for _, path := range order {
+		p := byProject[path]
+		sort.Slice(p.Artifacts, func(i, j int) bool { return p.Artifacts[i].Size > p.Artifacts[j].Size })
+		p.ModTime = projectModTime(p.Path, p.Artifacts)
+		projects = append(projects, *p)
+	}
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].Size > projects[j].Size })
+	return projects
+}
+
+// projectModTime returns the newest mtime among the project's own entries,
+// ignoring the artifacts themselves since builds touch them constantly.
+func projectModTime(projectPath string, artifacts []reclaimArtifact) time.Time {
+	skip := make(map[string]bool, len(artifacts))
+	for _, a := range artifacts {
+		skip[a.Name] = true
+	}
+
+	var newest time.Time
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return newest
+	}
+	for _, entry := range entries {
+		if skip[entry.Name()] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return newest
+}
+
+func reclaimTotal(projects []reclaimProject) int64 {
+	var total int64
+	for _, p := range projects {
+		total += p.Size
+	}
+	return total
+}
+
+func buildReclaimRows(projects []reclaimProject) []reclaimRow {
+	var rows []reclaimRow
+	for pi, p := range projects {
+		rows = append(rows, reclaimRow{project: pi, artifact: -1})
+		for ai := range p.Artifacts {
+			rows = append(rows, reclaimRow{project: pi, artifact: ai})
+		}
+	}
+	return rows
+}
+
+// pruneReclaimProjects drops artifacts that no longer exist on disk (e.g. after trashing).
+func pruneReclaimProjects(projects []reclaimProject) []reclaimProject {
+	var kept []reclaimProject
+	for _, p := range projects {
+		var artifacts []reclaimArtifact
+		var size int64
+		for _, a := range p.Artifacts {
+			if _, err := os.Lstat(a.Path); err != nil {
+				continue
+			}
+			artifacts = append(artifacts, a)
+			size += a.Size
+		}
+		if len(artifacts) == 0 {
+			continue
+		}
+		p.Artifacts = artifacts
+		p.Size = size
+		kept = append(kept, p)
+	}
+	return kept
+}
+
+func scanReclaimCmd(ctx context.Context, root string, found *int64, currentPath *atomic.Value) tea.Cmd {
+	return func() tea.Msg {
+		projects, err := collectReclaimable(ctx, root, found, currentPath)
+		return reclaimResultMsg{root: root, projects: projects, err: err}
+	}
+}
+
+func (m *model) openReclaimView() tea.Cmd {
+	stopScan(&m.reclaimCancel)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.reclaimCancel = cancel
+	m.showReclaim = true
+	m.showLargeFiles = false
+	m.reclaimScanning = true
+	m.reclaimProjects = nil
+	m.reclaimRows = nil
+	m.reclaimSelected = 0
+	m.reclaimOffset = 0
+	m.reclaimMultiSelected = make(map[string]bool)
+	var found int64
+	m.reclaimFound = &found
+	if m.currentPath != nil {
+		m.currentPath.Store("")
+	}
+	m.status = fmt.Sprintf("Looking for project artifacts under %s...", displayPath(m.path))
+	return tea.Batch(scanReclaimCmd(ctx, m.path, m.reclaimFound, m.currentPath), tickCmd())
+}
+
+func (m *model) setReclaimProjects(projects []reclaimProject) {
+	m.reclaimProjects = projects
+	m.reclaimRows = buildReclaimRows(projects)
+	for path := range m.reclaimMultiSelected {
+		if _, err := os.Lstat(path); err != nil {
+			delete(m.reclaimMultiSelected, path)
+		}
+	}
+	if m.reclaimSelected >= len(m.reclaimRows) {
+		m.reclaimSelected = max(len(m.reclaimRows)-1, 0)
+	}
+	viewport := calculateViewport(m.height)
+	maxOffset := max(len(m.reclaimRows)-viewport, 0)
+	if m.reclaimOffset > maxOffset {
+		m.reclaimOffset = maxOffset
+	}
+	if m.reclaimSelected < m.reclaimOffset {
+		m.reclaimOffset = m.reclaimSelected
+	}
+}
+
+// reclaimSelectionSize sums the sizes of selected artifacts.
+func (m model) reclaimSelectionSize() int64 {
+	var total int64
+	for _, p := range m.reclaimProjects {
+		for _, a := range p.Artifacts {
+			if m.reclaimMultiSelected[a.Path] {
+				total += a.Size
+			}
+		}
+	}
+	return total
+}
+
+func (m *model) updateReclaimStatus() {
+	if count := len(m.reclaimMultiSelected); count > 0 {
+		m.status = fmt.Sprintf("%d selected, %s", count, humanizeBytes(m.reclaimSelectionSize()))
+		return
+	}
+	m.status = fmt.Sprintf("%s reclaimable in %d projects", humanizeBytes(reclaimTotal(m.reclaimProjects)), len(m.reclaimProjects))
+}
+
+func (m model) updateReclaimKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.closesView(msg, actionReclaim) {
+		stopScan(&m.reclaimCancel)
+		m.showReclaim = false
+		m.reclaimScanning = false
+		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+		return m, nil
+	}
+
+	if m.reclaimScanning {
+		return m, nil
+	}
+	switch m.keyAction(msg) {
+	case actionDelete:
+		return m.confirmReclaimDelete()
+	case actionRefresh:
+		return m, m.openReclaimView()
+	}
+
+	switch msg.String() {
+	case "up", "k", "K":
+		if m.reclaimSelected > 0 {
+			m.reclaimSelected--
+			if m.reclaimSelected < m.reclaimOffset {
+				m.reclaimOffset = m.reclaimSelected
+			}
+		}
+	case "down", "j", "J":
+		if m.reclaimSelected < len(m.reclaimRows)-1 {
+			m.reclaimSelected++
+			viewport := calculateViewport(m.height)
+			if m.reclaimSelected >= m.reclaimOffset+viewport {
+				m.reclaimOffset = m.reclaimSelected - viewport + 1
+			}
+		}
+	case " ":
+		if m.reclaimSelected >= len(m.reclaimRows) {
+			return m, nil
+		}
+		row := m.reclaimRows[m.reclaimSelected]
+		project := m.reclaimProjects[row.project]
+		if row.artifact >= 0 {
+			path := project.Artifacts[row.artifact].Path
+			if m.reclaimMultiSelected[path] {
+				delete(m.reclaimMultiSelected, path)
+			} else {
+				m.reclaimMultiSelected[path] = true
+			}
+		} else {
+			// Header row toggles the whole project.
+			allSelected := true
+			for _, a := range project.Artifacts {
+				if !m.reclaimMultiSelected[a.Path] {
+					allSelected = false
+					break
+				}
+			}
+			for _, a := range project.Artifacts {
+				if allSelected {
+					delete(m.reclaimMultiSelected, a.Path)
+				} else {
+					m.reclaimMultiSelected[a.Path] = true
+				}
+			}
+		}
+		m.updateReclaimStatus()
+	case "s", "S":
+		// Select every artifact of stale projects in one go.
+		now := time.Now()
+		m.reclaimMultiSelected = make(map[string]bool)
+		for _, p := range m.reclaimProjects {
+			if !p.isStale(now) {
+				continue
+			}
+			for _, a := range p.Artifacts {
+				m.reclaimMultiSelected[a.Path] = true
+			}
+		}
+		if len(m.reclaimMultiSelected) == 0 {
+			m.status = "No stale projects found"
+			return m, nil
+		}
+		m.updateReclaimStatus()
+	}
+	return m, nil
+}
+
+// confirmReclaimDelete asks to delete the selected artifacts, or the one under the cursor.
+func (m model) confirmReclaimDelete() (tea.Model, tea.Cmd) {
+	if len(m.reclaimMultiSelected) > 0 {
+		for _, p := range m.reclaimProjects {
+			for _, a := range p.Artifacts {
+				if m.reclaimMultiSelected[a.Path] {
+					m.deleteConfirm = true
+					m.deleteTarget = &dirEntry{Name: a.Name, Path: a.Path, Size: a.Size, IsDir: true}
+					m.prepareDeleteConfirm()
+					return m, nil
+				}
+			}
+		}
+	}
+	if m.reclaimSelected < len(m.reclaimRows) {
+		row := m.reclaimRows[m.reclaimSelected]
+		if row.artifact >= 0 {
+			a := m.reclaimProjects[row.project].Artifacts[row.artifact]
+			m.deleteConfirm = true
+			m.deleteTarget = &dirEntry{Name: a.Name, Path: a.Path, Size: a.Size, IsDir: true}
+			m.prepareDeleteConfirm()
+		}
+	}
+	return m, nil
+}
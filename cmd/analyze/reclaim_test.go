@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCollectReclaimableGroupsByProject(t *testing.T) {
+	root := t.TempDir()
+
+	writeFileWithSize(t, filepath.Join(root, "web", "package.json"), 10)
+	writeFileWithSize(t, filepath.Join(root, "web", "node_modules", "dep", "index.js"), 4096)
+	writeFileWithSize(t, filepath.Join(root, "web", ".next", "cache.bin"), 2048)
+	writeFileWithSize(t, filepath.Join(root, "rust", "Cargo.toml"), 10)
+	writeFileWithSize(t, filepath.Join(root, "rust", "target", "debug", "app"), 8192)
+	// Committed Go vendor tree must not be collected.
+	writeFileWithSize(t, filepath.Join(root, "gosvc", "go.mod"), 10)
+	writeFileWithSize(t, filepath.Join(root, "gosvc", "vendor", "lib.go"), 4096)
+	// Orphan target without a Cargo.toml/pom.xml.
+	writeFileWithSize(t, filepath.Join(root, "misc", "target", "file"), 4096)
+
+	projects, err := collectReclaimable(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("collectReclaimable: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("expected 2 projects, got %d: %+v", len(projects), projects)
+	}
+
+	byPath := make(map[string]reclaimProject)
+	for _, p := range projects {
+		byPath[p.Path] = p
+	}
+	web, ok := byPath[filepath.Join(root, "web")]
+	if !ok {
+		t.Fatalf("web project missing")
+	}
+	if len(web.Artifacts) != 2 {
+		t.Fatalf("expected 2 web artifacts, got %d", len(web.Artifacts))
+	}
+	if web.Size != web.Artifacts[0].Size+web.Artifacts[1].Size {
+		t.Fatalf("project size %d does not match artifact sum", web.Size)
+	}
+	if _, ok := byPath[filepath.Join(root, "rust")]; !ok {
+		t.Fatalf("rust project missing")
+	}
+	if reclaimTotal(projects) <= 0 {
+		t.Fatalf("expected positive reclaimable total")
+	}
+}
+
+func TestReclaimProjectStaleness(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "package.json"), 10)
+	writeFileWithSize(t, filepath.Join(root, "node_modules", "x.js"), 10)
+
+	old := time.Now().Add(-2 * reclaimStaleAge)
+	if err := os.Chtimes(filepath.Join(root, "package.json"), old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	artifacts := []reclaimArtifact{{Name: "node_modules", Path: filepath.Join(root, "node_modules"), Size: 10}}
+	projects := groupReclaimArtifacts(artifacts)
+	if len(projects) != 1 {
+		t.Fatalf("expected 1 project, got %d", len(projects))
+	}
+	if !projects[0].isStale(time.Now()) {
+		t.Fatalf("expected project to be stale, modtime %v", projects[0].ModTime)
+	}
+}
+
+func TestReclaimCloseCancelsScan(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "app", "node_modules", "x.js"), 100)
+
+	m := model{path: root}
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = next.(model)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = next.(model); m.showReclaim || m.reclaimCancel != nil {
+		t.Fatal("expected esc to close the view and stop its scan")
+	}
+
+	msg := cmd().(tea.BatchMsg)[0]().(reclaimResultMsg)
+	if !errors.Is(msg.err, context.Canceled) {
+		t.Fatalf("expected the walk to stop once the view closed, got %v", msg.err)
+	}
+}
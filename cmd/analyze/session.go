@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+const (
+	sessionFile       = "analyze_session.json"
+	maxSessionCursors = 200 // Directories whose selection is remembered.
+)
+
+// sessionLevel is one step of the back stack, without the listing itself.
+type sessionLevel struct {
+	Path       string `json:"path"`
+	IsOverview bool   `json:"is_overview,omitempty"`
+	Selected   int    `json:"selected"`
+	Offset     int    `json:"offset"`
+}
+
+// sessionCursor remembers which entry was selected inside a directory.
+type sessionCursor struct {
+	Dir   string `json:"dir"`
+	Entry string `json:"entry"`
+}
+
+// sessionState is what mo analyze restores on the next run.
+// Cursors are most recent first.
+type sessionState struct {
+	Path    string          `json:"path"`
+	History []sessionLevel  `json:"history,omitempty"`
+	Cursors []sessionCursor `json:"cursors,omitempty"`
+}
+
+func getSessionPath() (string, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, sessionFile), nil
+}
+
+func loadSession() (sessionState, error) {
+	path, err := getSessionPath()
+	if err != nil {
+		return sessionState{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return sessionState{}, err
+	}
+	var state sessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return sessionState{}, err
+	}
+	return state, nil
+}
+
+func saveSession(state sessionState) error {
+	path, err := getSessionPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// sessionFromModel captures where the user is, keeping cursors from earlier runs
+// for directories not visited this time.
+func sessionFromModel(m model, previous sessionState) sessionState {
+	var state sessionState
+	if !m.inOverviewMode() {
+		state.Path = m.path
+	}
+	for _, level := range m.history {
+		state.History = append(state.History, sessionLevel{
+			Path:       level.Path,
+			IsOverview: level.IsOverview,
+			Selected:   level.Selected,
+			Offset:     level.EntryOffset,
+		})
+	}
+
+	seen := make(map[string]bool)
+	addCursor := func(dir string, entries []dirEntry, selected int) {
+		if seen[dir] || selected < 0 || selected >= len(entries) {
+			return
+		}
+		seen[dir] = true
+		state.Cursors = append(state.Cursors, sessionCursor{Dir: dir, Entry: entries[selected].Path})
+	}
+	current, _ := m.unfilteredEntries()
+	if m.ownerFilter == nil {
+		addCursor(m.path, current, m.selected)
+	}
+	for i := len(m.history) - 1; i >= 0; i-- {
+		addCursor(m.history[i].Path, m.history[i].Entries, m.history[i].Selected)
+	}
+	for dir, cached := range m.cache {
+		addCursor(dir, cached.Entries, cached.Selected)
+	}
+	for _, cursor := range previous.Cursors {
+		if !seen[cursor.Dir] {
+			seen[cursor.Dir] = true
+			state.Cursors = append(state.Cursors, cursor)
+		}
+	}
+	if len(state.Cursors) > maxSessionCursors {
+		state.Cursors = state.Cursors[:maxSessionCursors]
+	}
+	return state
+}
+
+// restoreSession reopens the last path with its back stack. Levels whose folder
+// is gone are dropped, and the rest rescan when the user goes back to them.
+func (m *model) restoreSession(state sessionState) {
+	m.savedCursors = make(map[string]string, len(state.Cursors))
+	for _, cursor := range state.Cursors {
+		m.savedCursors[cursor.Dir] = cursor.Entry
+	}
+	if state.Path == "" || !isDir(state.Path) {
+		return
+	}
+	m.path = state.Path
+	m.isOverview = false
+	m.scanning = true
+	m.overviewScanning = false
+	m.entries = nil
+	m.totalSize = 0
+	m.selected = 0
+	m.offset = 0
+	m.status = "Preparing scan..."
+	if total, err := peekCacheTotalFiles(m.path); err == nil && total > 0 {
+		m.lastTotalFiles = total
+	}
+	m.history = nil
+	for _, level := range state.History {
+		if !level.IsOverview && !isDir(level.Path) {
+			continue
+		}
+		m.history = append(m.history, historyEntry{
+			Path:        level.Path,
+			IsOverview:  level.IsOverview,
+			Selected:    level.Selected,
+			EntryOffset: level.Offset,
+			Dirty:       true,
+		})
+	}
+}
+
+// applySavedCursor selects the entry remembered for the current directory, once.
+func (m *model) applySavedCursor() {
+	entry, ok := m.savedCursors[m.path]
+	if !ok {
+		return
+	}
+	delete(m.savedCursors, m.path)
+	for i := range m.entries {
+		if m.entries[i].Path != entry {
+			continue
+		}
+		m.selected = i
+		viewport := calculateViewport(m.height)
+		if m.selected < m.offset || m.selected >= m.offset+viewport {
+			m.offset = max(m.selected-viewport+1, 0)
+		}
+		return
+	}
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	parent := filepath.Join(home, "work")
+	child := filepath.Join(parent, "app")
+	if err := os.MkdirAll(child, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := model{
+		path:     child,
+		selected: 1,
+		entries:  []dirEntry{{Path: filepath.Join(child, "a")}, {Path: filepath.Join(child, "b")}},
+		history: []historyEntry{
+			{Path: "/", IsOverview: true},
+			{Path: parent, Selected: 0, Entries: []dirEntry{{Path: child}}},
+		},
+	}
+	previous := sessionState{Cursors: []sessionCursor{
+		{Dir: child, Entry: "stale"},
+		{Dir: "/elsewhere", Entry: "/elsewhere/x"},
+	}}
+	if err := saveSession(sessionFromModel(m, previous)); err != nil {
+		t.Fatalf("saveSession: %v", err)
+	}
+
+	state, err := loadSession()
+	if err != nil {
+		t.Fatalf("loadSession: %v", err)
+	}
+	if state.Path != child || len(state.History) != 2 {
+		t.Fatalf("unexpected session %+v", state)
+	}
+	if len(state.Cursors) != 3 || state.Cursors[0].Entry != filepath.Join(child, "b") {
+		t.Fatalf("current selection should win over the previous run, got %+v", state.Cursors)
+	}
+
+	restored := newModel("/", true)
+	restored.restoreSession(state)
+	if restored.path != child || restored.isOverview || !restored.scanning {
+		t.Fatalf("expected to resume in %s, got %s", child, restored.path)
+	}
+	if len(restored.history) != 2 || !restored.history[1].Dirty {
+		t.Fatalf("history should come back dirty, got %+v", restored.history)
+	}
+
+	next, _ := restored.Update(scanResultMsg{path: child, result: scanResult{
+		Entries: []dirEntry{{Path: filepath.Join(child, "a"), Size: 2}, {Path: filepath.Join(child, "b"), Size: 1}},
+	}})
+	if got := next.(model).selected; got != 1 {
+		t.Errorf("saved cursor should select b, got index %d", got)
+	}
+}
+
+func TestRestoreSessionSkipsMissingPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newModel("/", true)
+	m.restoreSession(sessionState{Path: "/does/not/exist"})
+	if !m.inOverviewMode() {
+		t.Fatal("a missing folder should leave the overview in place")
+	}
+}
+
+func TestBookmarks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	resetOverviewSnapshotForTest()
+	t.Cleanup(resetOverviewSnapshotForTest)
+
+	target := filepath.Join(home, "photos")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	measured := time.Now().Add(-2 * 24 * time.Hour)
+	if err := storeOverviewSizeAt(target, 4096, measured); err != nil {
+		t.Fatal(err)
+	}
+
+	m := model{path: target, entries: []dirEntry{{Path: filepath.Join(target, "x")}}}
+	next, _ := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'*'}})
+	m = next.(model)
+	if !m.bookmarkNaming || m.bookmarkName != "photos" {
+		t.Fatalf("expected name prompt with default name, got %v %q", m.bookmarkNaming, m.bookmarkName)
+	}
+	m.bookmarkName = "pics"
+	next, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(model)
+
+	marks, err := loadBookmarks()
+	if err != nil || len(marks) != 1 || marks[0].Name != "pics" {
+		t.Fatalf("bookmark not saved: %+v, %v", marks, err)
+	}
+
+	m.openBookmarksView()
+	if !m.showBookmarks || len(m.bookmarkRows) != 1 {
+		t.Fatalf("picker should list the bookmark, got %+v", m.bookmarkRows)
+	}
+	row := m.bookmarkRows[0]
+	if row.Size != 4096 || !row.Updated.Equal(measured) {
+		t.Errorf("expected cached size and age, got %d at %v", row.Size, row.Updated)
+	}
+
+	next, _ = m.updateBookmarksKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = next.(model)
+	if m.showBookmarks {
+		t.Error("removing the last bookmark should close the picker")
+	}
+	if marks, _ := loadBookmarks(); len(marks) != 0 {
+		t.Errorf("expected bookmark removed, got %+v", marks)
+	}
+}
@@ -4,8 +4,15 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tw93/mole/pkg/scan"
 )
 
 // View renders the TUI.
@@ -13,6 +20,11 @@ func (m model) View() string {
 	var b strings.Builder
 	fmt.Fprintln(&b)
 
+	if m.compareMode {
+		m.renderCompare(&b)
+		return b.String()
+	}
+
 	if m.inOverviewMode() {
 		fmt.Fprintf(&b, "%sAnalyze Disk%s\n", colorPurpleBold, colorReset)
 		if m.overviewScanning {
@@ -54,7 +66,14 @@ func (m model) View() string {
 		if !m.scanning {
 			fmt.Fprintf(&b, "  |  Total: %s", humanizeBytes(m.totalSize))
 		}
-		fmt.Fprintf(&b, "\n\n")
+		if m.ownerFilter != nil {
+			fmt.Fprintf(&b, "  |  %sOwner: %s%s", colorYellow, scan.UserName(*m.ownerFilter), colorReset)
+		}
+		fmt.Fprintln(&b)
+		if !m.scanning && m.scanIssues.Total > 0 && !m.showIssues {
+			fmt.Fprintf(&b, "%s⚠ %s, %s to list%s\n", colorYellow, issueSummary(m.scanIssues), m.hint(actionIssues, ""), colorReset)
+		}
+		fmt.Fprintln(&b)
 	}
 
 	if m.deleting {
@@ -72,6 +91,54 @@ func (m model) View() string {
 		return b.String()
 	}
 
+	if m.showReclaim {
+		m.renderReclaim(&b)
+		m.renderDeleteConfirm(&b)
+		return b.String()
+	}
+
+	if m.showJunk {
+		m.renderJunk(&b)
+		m.renderDeleteConfirm(&b)
+		return b.String()
+	}
+
+	if m.showRecent {
+		m.renderRecent(&b)
+		m.renderDeleteConfirm(&b)
+		return b.String()
+	}
+
+	if m.showGit {
+		m.renderGit(&b)
+		return b.String()
+	}
+
+	if m.showIssues {
+		m.renderIssues(&b)
+		return b.String()
+	}
+
+	if m.showOwners {
+		m.renderOwners(&b)
+		return b.String()
+	}
+
+	if m.showHelp {
+		m.renderHelp(&b)
+		return b.String()
+	}
+
+	if m.showBookmarks {
+		m.renderBookmarks(&b)
+		return b.String()
+	}
+
+	if m.showMoves {
+		m.renderMoves(&b)
+		return b.String()
+	}
+
 	if m.scanning {
 		filesScanned, dirsScanned, bytesScanned := m.getScanProgress()
 
@@ -89,14 +156,22 @@ func (m model) View() string {
 			progressPrefix = fmt.Sprintf(" %s%.0f%%%s", colorCyan, percent, colorReset)
 		}
 
-		fmt.Fprintf(&b, "%s%s%s%s Scanning%s: %s%s files%s, %s%s dirs%s, %s%s%s\n",
+		storage := ""
+		if m.scanStorage != nil {
+			if note, ok := m.scanStorage.Load().(storageNote); ok && note.path == m.path {
+				storage = fmt.Sprintf("  %s%s%s", colorGray, note.label, colorReset)
+			}
+		}
+
+		fmt.Fprintf(&b, "%s%s%s%s Scanning%s: %s%s files%s, %s%s dirs%s, %s%s%s%s\n",
 			colorCyan, colorBold,
 			spinnerFrames[m.spinner],
 			colorReset,
 			progressPrefix,
 			colorYellow, formatNumber(filesScanned), colorReset,
 			colorYellow, formatNumber(dirsScanned), colorReset,
-			colorGreen, humanizeBytes(bytesScanned), colorReset)
+			colorGreen, humanizeBytes(bytesScanned), colorReset,
+			storage)
 
 		if m.currentPath != nil {
 			currentPath := m.currentPath.Load().(string)
@@ -110,11 +185,13 @@ func (m model) View() string {
 		return b.String()
 	}
 
+	var list strings.Builder
 	if m.showLargeFiles {
 		if len(m.largeFiles) == 0 {
-			fmt.Fprintln(&b, "  No large files found")
+			fmt.Fprintln(&list, "  No large files found")
 		} else {
-			viewport := calculateViewport(m.height, true)
+			fmt.Fprintf(&list, "  %s%s%s\n", colorGray, m.largeFilesSummary(), colorReset)
+			viewport := calculateViewport(m.height)
 			start := max(m.largeOffset, 0)
 			end := min(start+viewport, len(m.largeFiles))
 			maxLargeSize := int64(1)
@@ -123,7 +200,8 @@ func (m model) View() string {
 					maxLargeSize = file.Size
 				}
 			}
-			nameWidth := calculateNameWidth(m.width)
+			nameWidth := calculateNameWidth(m.listWidth())
+			numWidth := max(2, len(strconv.Itoa(len(m.largeFiles))))
 			for idx := start; idx < end; idx++ {
 				file := m.largeFiles[idx]
 				shortPath := displayPath(file.Path)
@@ -151,28 +229,37 @@ func (m model) View() string {
 				}
 				size := humanizeBytes(file.Size)
 				bar := coloredProgressBar(file.Size, maxLargeSize, 0)
-				fmt.Fprintf(&b, "%s%s %s%2d.%s %s  |  📄 %s%s%s  %s%10s%s\n",
-					entryPrefix, selectIcon, numColor, idx+1, colorReset, bar, nameColor, paddedPath, colorReset, sizeColor, size, colorReset)
+				age := ""
+				if m.largeByAge {
+					age = fmt.Sprintf("  %s%s%s", colorGray, formatAge(file.ModTime), colorReset)
+				}
+				fmt.Fprintf(&list, "%s%s %s%*d.%s %s  |  📄 %s%s%s  %s%10s%s%s\n",
+					entryPrefix, selectIcon, numColor, numWidth, idx+1, colorReset, bar, nameColor, paddedPath, colorReset, sizeColor, size, colorReset, age)
 			}
 		}
 	} else {
 		if len(m.entries) == 0 {
-			fmt.Fprintln(&b, "  Empty directory")
+			fmt.Fprintln(&list, "  Empty directory")
 		} else {
 			if m.inOverviewMode() {
 				maxSize := int64(1)
 				for _, entry := range m.entries {
-					if entry.Size > maxSize {
+					if entry.Size > maxSize && !m.isOverviewMount(entry.Path) {
 						maxSize = entry.Size
 					}
 				}
 				totalSize := m.totalSize
 				// Overview paths are short; fixed width keeps layout stable.
 				nameWidth := 20
-				for idx, entry := range m.entries {
+				viewport := calculateViewport(m.height)
+				start := max(m.offset, 0)
+				end := min(start+viewport, len(m.entries))
+				for idx := start; idx < end; idx++ {
+					entry := m.entries[idx]
 					icon := "📁"
 					sizeVal := entry.Size
 					barValue := max(sizeVal, 0)
+					barMax := maxSize
 					var percent float64
 					if totalSize > 0 && sizeVal >= 0 {
 						percent = float64(sizeVal) / float64(totalSize) * 100
@@ -183,7 +270,15 @@ func (m model) View() string {
 					if totalSize == 0 || sizeVal < 0 {
 						percentStr = "  --  "
 					}
-					bar := coloredProgressBar(barValue, maxSize, percent)
+					mount, isMount := m.overviewMounts[entry.Path]
+					if isMount {
+						// Mounts show how full the volume is rather than a share of the roots.
+						icon = "💽"
+						barMax = mount.Total
+						percent = float64(mount.Used) / float64(mount.Total) * 100
+						percentStr = fmt.Sprintf("%5.1f%%", percent)
+					}
+					bar := coloredProgressBar(barValue, barMax, percent)
 					sizeText := "pending.."
 					if sizeVal >= 0 {
 						sizeText = humanizeBytes(sizeVal)
@@ -216,21 +311,17 @@ func (m model) View() string {
 					}
 					displayIndex := idx + 1
 
-					var hintLabel string
-					if entry.IsDir && isCleanableDir(entry.Path) {
-						hintLabel = fmt.Sprintf("%s🧹%s", colorYellow, colorReset)
-					} else {
-						if unusedTime := formatUnusedTime(entry.LastAccess); unusedTime != "" {
-							hintLabel = fmt.Sprintf("%s%s%s", colorGray, unusedTime, colorReset)
-						}
+					hintLabel := entryHintLabel(entry, idx == m.selected)
+					if isMount {
+						hintLabel = fmt.Sprintf("%sof %s, %s%s", colorGray, humanizeBytes(mount.Total), mount.FSType, colorReset)
 					}
 
 					if hintLabel == "" {
-						fmt.Fprintf(&b, "%s%s%2d.%s %s %s%s%s  |  %s %s%10s%s\n",
+						fmt.Fprintf(&list, "%s%s%2d.%s %s %s%s%s  |  %s %s%10s%s\n",
 							entryPrefix, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
 							nameSegment, sizeColor, sizeText, colorReset)
 					} else {
-						fmt.Fprintf(&b, "%s%s%2d.%s %s %s%s%s  |  %s %s%10s%s  %s\n",
+						fmt.Fprintf(&list, "%s%s%2d.%s %s %s%s%s  |  %s %s%10s%s  %s\n",
 							entryPrefix, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
 							nameSegment, sizeColor, sizeText, colorReset, hintLabel)
 					}
@@ -243,8 +334,8 @@ func (m model) View() string {
 					}
 				}
 
-				viewport := calculateViewport(m.height, false)
-				nameWidth := calculateNameWidth(m.width)
+				viewport := calculateViewport(m.height)
+				nameWidth := calculateNameWidth(m.listWidth())
 				start := max(m.offset, 0)
 				end := min(start+viewport, len(m.entries))
 
@@ -301,21 +392,14 @@ func (m model) View() string {
 
 					displayIndex := idx + 1
 
-					var hintLabel string
-					if entry.IsDir && isCleanableDir(entry.Path) {
-						hintLabel = fmt.Sprintf("%s🧹%s", colorYellow, colorReset)
-					} else {
-						if unusedTime := formatUnusedTime(entry.LastAccess); unusedTime != "" {
-							hintLabel = fmt.Sprintf("%s%s%s", colorGray, unusedTime, colorReset)
-						}
-					}
+					hintLabel := entryHintLabel(entry, idx == m.selected)
 
 					if hintLabel == "" {
-						fmt.Fprintf(&b, "%s%s %s%2d.%s %s %s%s%s  |  %s %s%10s%s\n",
+						fmt.Fprintf(&list, "%s%s %s%2d.%s %s %s%s%s  |  %s %s%10s%s\n",
 							entryPrefix, selectIcon, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
 							nameSegment, sizeColor, size, colorReset)
 					} else {
-						fmt.Fprintf(&b, "%s%s %s%2d.%s %s %s%s%s  |  %s %s%10s%s  %s\n",
+						fmt.Fprintf(&list, "%s%s %s%2d.%s %s %s%s%s  |  %s %s%10s%s  %s\n",
 							entryPrefix, selectIcon, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
 							nameSegment, sizeColor, size, colorReset, hintLabel)
 					}
@@ -323,89 +407,555 @@ func (m model) View() string {
 			}
 		}
 	}
+	m.writeList(&b, list.String())
 
 	fmt.Fprintln(&b)
-	if m.inOverviewMode() {
-		if len(m.history) > 0 {
-			fmt.Fprintf(&b, "%s↑↓←→ | Enter | R Refresh | O Open | F File | ← Back | Q Quit%s\n", colorGray, colorReset)
-		} else {
-			fmt.Fprintf(&b, "%s↑↓→ | Enter | R Refresh | O Open | F File | Q Quit%s\n", colorGray, colorReset)
+	fmt.Fprintf(&b, "%s%s%s\n", colorGray, m.mainFooter(), colorReset)
+	m.renderDeleteConfirm(&b)
+	m.renderCompressConfirm(&b)
+	m.renderMoveConfirm(&b)
+	m.renderBookmarkPrompt(&b)
+	return b.String()
+}
+
+// renderDeleteConfirm renders the pending delete prompt, if any.
+func (m model) renderDeleteConfirm(b *strings.Builder) {
+	if m.deleteConfirm && m.deleteTarget != nil {
+		fmt.Fprintln(b)
+		deleteCount := len(m.pendingDeletePaths())
+		totalDeleteSize := m.pendingDeleteSize()
+
+		label := "Delete:"
+		if m.deletePermanent {
+			label = "Delete permanently:"
 		}
-	} else if m.showLargeFiles {
-		selectCount := len(m.largeMultiSelected)
-		if selectCount > 0 {
-			fmt.Fprintf(&b, "%s↑↓← | Space Select | R Refresh | O Open | F File | ⌫ Del %d | ← Back | Q Quit%s\n", colorGray, selectCount, colorReset)
-		} else {
-			fmt.Fprintf(&b, "%s↑↓← | Space Select | R Refresh | O Open | F File | ⌫ Del | ← Back | Q Quit%s\n", colorGray, colorReset)
+		target := fmt.Sprintf("%s, %s", m.deleteTarget.Name, humanizeBytes(m.deleteTarget.Size))
+		if deleteCount > 1 {
+			target = fmt.Sprintf("%d items, %s", deleteCount, humanizeBytes(totalDeleteSize))
 		}
-	} else {
-		largeFileCount := len(m.largeFiles)
-		selectCount := len(m.multiSelected)
-		if selectCount > 0 {
-			if largeFileCount > 0 {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del %d | T Top %d | Q Quit%s\n", colorGray, selectCount, largeFileCount, colorReset)
-			} else {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del %d | Q Quit%s\n", colorGray, selectCount, colorReset)
+
+		hint := "Press Enter to confirm  |  Tab delete permanently  |  ESC cancel"
+		switch {
+		case m.deleteNoTrash:
+			hint = "Trash unavailable  |  ESC cancel"
+		case m.deletePermanent:
+			hint = "Tab move to Trash  |  ESC cancel"
+		}
+		if needsTypedConfirm(totalDeleteSize, m.deletePermanent) {
+			hint = fmt.Sprintf("Type %q: %s%s%s  |  %s", typedConfirmWord, colorReset, m.deleteTyped, colorGray, hint)
+		}
+
+		fmt.Fprintf(b, "%s%s%s %s  %s%s%s\n",
+			colorRed, label, colorReset,
+			target,
+			colorGray, hint, colorReset)
+	}
+}
+
+// renderCompressConfirm renders the archive destination prompt, if any.
+func (m model) renderCompressConfirm(b *strings.Builder) {
+	if !m.compressConfirm || len(m.compressPaths) == 0 {
+		return
+	}
+	fmt.Fprintln(b)
+	target := filepath.Base(m.compressPaths[0])
+	if len(m.compressPaths) > 1 {
+		target = fmt.Sprintf("%d items", len(m.compressPaths))
+	}
+	dest := m.compressDest
+	if dest == "" {
+		dest = colorGray + "next to original" + colorReset
+	}
+	fmt.Fprintf(b, "%sCompress:%s %s to zip, then move original to Trash  %sDestination:%s %s\n",
+		colorYellow, colorReset, target, colorGray, colorReset, dest)
+	fmt.Fprintf(b, "%sType a folder to change destination  |  Enter confirm  |  ESC cancel%s\n", colorGray, colorReset)
+}
+
+// renderMoveConfirm renders the relocate or restore prompt, if any.
+func (m model) renderMoveConfirm(b *strings.Builder) {
+	if !m.moveConfirm {
+		return
+	}
+	fmt.Fprintln(b)
+	if m.moveRestore != nil {
+		fmt.Fprintf(b, "%sRestore:%s %s from %s  %sEnter confirm  |  ESC cancel%s\n",
+			colorYellow, colorReset, displayPath(m.moveRestore.Source), displayPath(m.moveRestore.Destination), colorGray, colorReset)
+		return
+	}
+	dest := m.moveDest
+	if dest == "" {
+		dest = colorGray + "type a folder on another volume" + colorReset
+	}
+	stub := "on"
+	if !m.moveSymlink {
+		stub = "off"
+	}
+	fmt.Fprintf(b, "%sMove:%s %s, %s  %sTo:%s %s\n",
+		colorYellow, colorReset, m.moveSource.Name, humanizeBytes(m.moveSource.Size), colorGray, colorReset, dest)
+	fmt.Fprintf(b, "%sSymlink at original: %s  |  Tab toggle  |  Enter confirm  |  ESC cancel%s\n", colorGray, stub, colorReset)
+}
+
+// entryHintLabel renders the cleanable badge or unused-time hint for a row.
+// The rule's reason is only spelled out on the selected row to keep lines short.
+func entryHintLabel(entry dirEntry, selected bool) string {
+	if entry.IsDir {
+		if match, ok := matchCleanableDir(entry.Path); ok {
+			badgeColor := colorYellow
+			badge := "🧹"
+			if match.Confidence == confidenceLow {
+				badgeColor = colorGray
+				badge = "🧹?"
 			}
-		} else {
-			if largeFileCount > 0 {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del | T Top %d | Q Quit%s\n", colorGray, largeFileCount, colorReset)
-			} else {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del | Q Quit%s\n", colorGray, colorReset)
+			if selected {
+				return fmt.Sprintf("%s%s%s %s%s, %s confidence%s", badgeColor, badge, colorReset, colorGray, match.Reason, match.Confidence, colorReset)
 			}
+			return fmt.Sprintf("%s%s%s", badgeColor, badge, colorReset)
 		}
 	}
-	if m.deleteConfirm && m.deleteTarget != nil {
-		fmt.Fprintln(&b)
-		var deleteCount int
-		var totalDeleteSize int64
-		if m.showLargeFiles && len(m.largeMultiSelected) > 0 {
-			deleteCount = len(m.largeMultiSelected)
-			for path := range m.largeMultiSelected {
-				for _, file := range m.largeFiles {
-					if file.Path == path {
-						totalDeleteSize += file.Size
+	if unusedTime := formatUnusedTime(entry.LastAccess); unusedTime != "" {
+		return fmt.Sprintf("%s%s%s", colorGray, unusedTime, colorReset)
+	}
+	return ""
+}
+
+// renderReclaim renders reclaimable project artifacts grouped by project.
+func (m model) renderReclaim(b *strings.Builder) {
+	if m.reclaimScanning {
+		found := int64(0)
+		if m.reclaimFound != nil {
+			found = atomic.LoadInt64(m.reclaimFound)
+		}
+		fmt.Fprintf(b, "%s%s%s%s Finding project artifacts: %s%s found%s\n",
+			colorCyan, colorBold,
+			spinnerFrames[m.spinner],
+			colorReset,
+			colorYellow, formatNumber(found), colorReset)
+		if m.currentPath != nil {
+			if currentPath, ok := m.currentPath.Load().(string); ok && currentPath != "" {
+				fmt.Fprintf(b, "%s%s%s\n", colorGray, truncateMiddle(displayPath(currentPath), 50), colorReset)
+			}
+		}
+		return
+	}
+
+	total := reclaimTotal(m.reclaimProjects)
+	fmt.Fprintf(b, "%sReclaimable:%s %s%s%s in %d projects\n\n",
+		colorGray, colorReset, colorGreen, humanizeBytes(total), colorReset, len(m.reclaimProjects))
+
+	if len(m.reclaimRows) == 0 {
+		fmt.Fprintln(b, "  No project artifacts found")
+	} else {
+		now := time.Now()
+		viewport := calculateViewport(m.height)
+		nameWidth := calculateNameWidth(m.width)
+		start := max(m.reclaimOffset, 0)
+		end := min(start+viewport, len(m.reclaimRows))
+		for idx := start; idx < end; idx++ {
+			row := m.reclaimRows[idx]
+			project := m.reclaimProjects[row.project]
+			entryPrefix := "   "
+			nameColor := ""
+			if idx == m.reclaimSelected {
+				entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+				nameColor = colorCyan
+			}
+
+			if row.artifact < 0 {
+				allSelected := true
+				for _, a := range project.Artifacts {
+					if !m.reclaimMultiSelected[a.Path] {
+						allSelected = false
 						break
 					}
 				}
+				selectIcon := "○"
+				if allSelected {
+					selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
+				}
+				age := "modified " + formatAge(project.ModTime)
+				if project.isStale(now) {
+					age += fmt.Sprintf(" %sstale%s", colorYellow, colorGray)
+				}
+				name := padName(truncateMiddle(displayPath(project.Path), nameWidth+3), nameWidth+3)
+				fmt.Fprintf(b, "%s%s 📦 %s%s%s%s  %10s%s  %s%s%s\n",
+					entryPrefix, selectIcon, colorBold, nameColor, name, colorReset,
+					humanizeBytes(project.Size), colorReset, colorGray, age, colorReset)
+				continue
 			}
-		} else if !m.showLargeFiles && len(m.multiSelected) > 0 {
-			deleteCount = len(m.multiSelected)
-			for path := range m.multiSelected {
-				for _, entry := range m.entries {
-					if entry.Path == path {
-						totalDeleteSize += entry.Size
-						break
-					}
+
+			artifact := project.Artifacts[row.artifact]
+			selectIcon := "○"
+			if m.reclaimMultiSelected[artifact.Path] {
+				selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
+				if nameColor == "" {
+					nameColor = colorGreen
 				}
 			}
+			bar := coloredProgressBar(artifact.Size, max(project.Size, 1), 0)
+			name := padName(trimNameWithWidth(artifact.Name, nameWidth), nameWidth)
+			fmt.Fprintf(b, "%s   %s %s  %s%s%s  %10s  %s%s%s\n",
+				entryPrefix, selectIcon, bar, nameColor, name, colorReset,
+				humanizeBytes(artifact.Size), colorGray, artifact.Match.Reason, colorReset)
 		}
+	}
 
-		if deleteCount > 1 {
-			fmt.Fprintf(&b, "%sDelete:%s %d items, %s  %sPress Enter to confirm  |  ESC cancel%s\n",
-				colorRed, colorReset,
-				deleteCount, humanizeBytes(totalDeleteSize),
-				colorGray, colorReset)
+	fmt.Fprintln(b)
+	del := m.hint(actionDelete, "Del")
+	if selectCount := len(m.reclaimMultiSelected); selectCount > 0 && del != "" {
+		del = fmt.Sprintf("%s %d", del, selectCount)
+	}
+	fmt.Fprintf(b, "%s%s%s\n", colorGray, footer("↑↓", "Space Select", "S Stale", del, m.hint(actionRefresh, "Rescan"), "← Back", m.hint(actionQuit, "Quit")), colorReset)
+}
+
+// renderJunk renders junk found under the current root, grouped by kind.
+func (m model) renderJunk(b *strings.Builder) {
+	if m.junkScanning {
+		found := int64(0)
+		if m.junkFound != nil {
+			found = atomic.LoadInt64(m.junkFound)
+		}
+		fmt.Fprintf(b, "%s%s%s%s Finding junk: %s%s found%s\n",
+			colorCyan, colorBold,
+			spinnerFrames[m.spinner],
+			colorReset,
+			colorYellow, formatNumber(found), colorReset)
+		if m.currentPath != nil {
+			if currentPath, ok := m.currentPath.Load().(string); ok && currentPath != "" {
+				fmt.Fprintf(b, "%s%s%s\n", colorGray, truncateMiddle(displayPath(currentPath), 50), colorReset)
+			}
+		}
+		return
+	}
+
+	fmt.Fprintf(b, "%sJunk:%s %s%d items%s in %d groups\n\n",
+		colorGray, colorReset, colorYellow, junkCount(m.junkGroups), colorReset, len(m.junkGroups))
+
+	if len(m.junkRows) == 0 {
+		fmt.Fprintln(b, "  No junk found")
+	} else {
+		viewport := calculateViewport(m.height)
+		nameWidth := calculateNameWidth(m.width)
+		start := max(m.junkOffset, 0)
+		end := min(start+viewport, len(m.junkRows))
+		for idx := start; idx < end; idx++ {
+			row := m.junkRows[idx]
+			group := m.junkGroups[row.group]
+			entryPrefix := "   "
+			nameColor := ""
+			if idx == m.junkSelected {
+				entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+				nameColor = colorCyan
+			}
+
+			if row.item < 0 {
+				selectIcon := "○"
+				if m.junkGroupSelected(group) {
+					selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
+				}
+				fmt.Fprintf(b, "%s%s 🗑  %s%s%s (%d)%s  %10s\n",
+					entryPrefix, selectIcon, colorBold, nameColor, group.Kind, len(group.Items), colorReset,
+					humanizeBytes(group.Size))
+				continue
+			}
+
+			item := group.Items[row.item]
+			selectIcon := "○"
+			if m.junkMultiSelected[item.Path] {
+				selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
+				if nameColor == "" {
+					nameColor = colorGreen
+				}
+			}
+			rel, err := filepath.Rel(m.path, item.Path)
+			if err != nil {
+				rel = displayPath(item.Path)
+			}
+			if item.IsDir {
+				rel += "/"
+			}
+			name := padName(truncateMiddle(rel, nameWidth+3), nameWidth+3)
+			fmt.Fprintf(b, "%s   %s %s%s%s  %10s  %s%s%s\n",
+				entryPrefix, selectIcon, nameColor, name, colorReset,
+				humanizeBytes(item.Size), colorGray, item.Detail, colorReset)
+		}
+	}
+
+	fmt.Fprintln(b)
+	del := m.hint(actionDelete, "Del")
+	if selectCount := len(m.junkMultiSelected); selectCount > 0 && del != "" {
+		del = fmt.Sprintf("%s %d", del, selectCount)
+	}
+	fmt.Fprintf(b, "%s%s%s\n", colorGray, footer("↑↓", "Space Select", "A All", del, m.hint(actionRefresh, "Rescan"), "← Back", m.hint(actionQuit, "Quit")), colorReset)
+}
+
+// renderRecent renders files written within the chosen window and the folders
+// holding them, by bytes written.
+func (m model) renderRecent(b *strings.Builder) {
+	if m.recentScanning {
+		checked := int64(0)
+		if m.recentChecked != nil {
+			checked = atomic.LoadInt64(m.recentChecked)
+		}
+		fmt.Fprintf(b, "%s%s%s%s Finding recent writes: %s%s files checked%s\n",
+			colorCyan, colorBold,
+			spinnerFrames[m.spinner],
+			colorReset,
+			colorYellow, formatNumber(checked), colorReset)
+		if m.currentPath != nil {
+			if currentPath, ok := m.currentPath.Load().(string); ok && currentPath != "" {
+				fmt.Fprintf(b, "%s%s%s\n", colorGray, truncateMiddle(displayPath(currentPath), 50), colorReset)
+			}
+		}
+		return
+	}
+
+	bytes, files := recentTotal(m.recentItems)
+	fmt.Fprintf(b, "%sWritten in the last %s:%s %s%s%s in %s files\n\n",
+		colorGray, recentWindows[m.recentWindow].label, colorReset,
+		colorYellow, humanizeBytes(bytes), colorReset, formatNumber(int64(files)))
+
+	if len(m.recentItems) == 0 {
+		fmt.Fprintln(b, "  Nothing written in this window")
+	} else {
+		viewport := calculateViewport(m.height)
+		nameWidth := calculateNameWidth(m.width)
+		start := max(m.recentOffset, 0)
+		end := min(start+viewport, len(m.recentItems))
+		for idx := start; idx < end; idx++ {
+			item := m.recentItems[idx]
+			entryPrefix := "   "
+			nameColor := ""
+			if idx == m.recentSelected {
+				entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+				nameColor = colorCyan
+			}
+			icon := "📄"
+			detail := formatRecentAge(item.ModTime)
+			rel, err := filepath.Rel(m.path, item.Path)
+			if err != nil {
+				rel = displayPath(item.Path)
+			}
+			if item.IsDir {
+				icon = "📁"
+				rel += "/"
+				detail = fmt.Sprintf("%s files, newest %s", formatNumber(int64(item.Files)), detail)
+			}
+			name := padName(truncateMiddle(rel, nameWidth), nameWidth)
+			fmt.Fprintf(b, "%s%s %s%s%s  %10s  %s%s%s\n",
+				entryPrefix, icon, nameColor, name, colorReset,
+				humanizeBytes(item.Size), colorGray, detail, colorReset)
+		}
+	}
+
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "%s%s%s\n", colorGray, footer("↑↓", "T Hour/Day/Week", m.hint(actionDelete, "Del"), m.hint(actionRefresh, "Rescan"), "← Back", m.hint(actionQuit, "Quit")), colorReset)
+}
+
+// renderCompare renders the merged A/B tree of compare mode.
+func (m model) renderCompare(b *strings.Builder) {
+	level := m.compareLevel
+	sidePath := func(path string) string {
+		if path == "" {
+			return colorGray + "(missing)" + colorReset
+		}
+		return displayPath(path)
+	}
+	fmt.Fprintf(b, "%sCompare%s  %sA:%s %s  %sB:%s %s\n",
+		colorPurpleBold, colorReset, colorGray, colorReset, sidePath(level.PathA), colorGray, colorReset, sidePath(level.PathB))
+
+	if m.compareScanning {
+		filesScanned, _, bytesScanned := m.getScanProgress()
+		fmt.Fprintf(b, "\n%s%s%s%s Scanning both sides: %s%s files%s, %s\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset,
+			colorYellow, formatNumber(filesScanned), colorReset, humanizeBytes(bytesScanned))
+		return
+	}
+
+	deltaColor := colorGray
+	if total := level.TotalB - level.TotalA; total > 0 {
+		deltaColor = colorGreen
+	} else if total < 0 {
+		deltaColor = colorRed
+	}
+	fmt.Fprintf(b, "Total A: %s  |  B: %s  |  %s%s%s  |  %s\n\n",
+		humanizeBytes(level.TotalA), humanizeBytes(level.TotalB),
+		deltaColor, formatDelta(level.TotalB-level.TotalA), colorReset, m.status)
+
+	entries := m.visibleCompareEntries()
+	if len(entries) == 0 {
+		fmt.Fprintln(b, "  No differences")
+	} else {
+		viewport := calculateViewport(m.height)
+		nameWidth := calculateNameWidth(m.width)
+		start := max(level.Offset, 0)
+		end := min(start+viewport, len(entries))
+		for idx := start; idx < end; idx++ {
+			entry := entries[idx]
+			prefix := "   "
+			nameColor := ""
+			if idx == level.Selected {
+				prefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+				nameColor = colorCyan
+			}
+
+			marker := colorGray + "=" + colorReset
+			switch entry.Status {
+			case compareOnlyA:
+				marker = colorRed + "A" + colorReset
+			case compareOnlyB:
+				marker = colorGreen + "B" + colorReset
+			case compareDiffer:
+				marker = colorYellow + "≠" + colorReset
+			}
+
+			icon := "📄"
+			if entry.IsDir {
+				icon = "📁"
+			}
+			sizeA, sizeB := "-", "-"
+			if entry.PathA != "" {
+				sizeA = humanizeBytes(entry.SizeA)
+			}
+			if entry.PathB != "" {
+				sizeB = humanizeBytes(entry.SizeB)
+			}
+			deltaColor := colorGray
+			if d := entry.delta(); d > 0 {
+				deltaColor = colorGreen
+			} else if d < 0 {
+				deltaColor = colorRed
+			}
+			name := padName(trimNameWithWidth(entry.Name, nameWidth), nameWidth)
+			fmt.Fprintf(b, "%s%s %s %s%s%s  %10s  %10s  %s%11s%s\n",
+				prefix, marker, icon, nameColor, name, colorReset,
+				sizeA, sizeB, deltaColor, formatDelta(entry.delta()), colorReset)
+		}
+	}
+
+	fmt.Fprintln(b)
+	filter := "D Diff only"
+	if m.compareDiffOnly {
+		filter = "D Show all"
+	}
+	if len(m.compareHistory) > 0 {
+		fmt.Fprintf(b, "%s↑↓←→ | Enter | %s | R Refresh | ← Back | Q Quit%s\n", colorGray, filter, colorReset)
+	} else {
+		fmt.Fprintf(b, "%s↑↓→ | Enter | %s | R Refresh | Q Quit%s\n", colorGray, filter, colorReset)
+	}
+}
+
+// renderGit renders the repository breakdown panel.
+func (m model) renderGit(b *strings.Builder) {
+	if m.gitLoading || m.gitInsights == nil {
+		if m.gitLoading {
+			fmt.Fprintf(b, "%s%s%s%s Inspecting repository, reading packs and history...\n",
+				colorCyan, colorBold, spinnerFrames[m.spinner], colorReset)
 		} else {
-			fmt.Fprintf(&b, "%sDelete:%s %s, %s  %sPress Enter to confirm  |  ESC cancel%s\n",
-				colorRed, colorReset,
-				m.deleteTarget.Name, humanizeBytes(m.deleteTarget.Size),
-				colorGray, colorReset)
+			fmt.Fprintf(b, "  %s\n\n", m.status)
+			fmt.Fprintf(b, "%s%s%s\n", colorGray, footer(m.hint(actionRefresh, "Retry"), "← Back", m.hint(actionQuit, "Quit")), colorReset)
 		}
+		return
 	}
-	return b.String()
+
+	g := m.gitInsights
+	row := func(label string, size int64, detail string) {
+		if detail != "" {
+			detail = colorGray + detail + colorReset
+		}
+		fmt.Fprintf(b, "  %-16s %10s  %s\n", label, humanizeBytes(size), detail)
+	}
+
+	bloat := ""
+	if g.bloated() {
+		bloat = fmt.Sprintf("%s⚠ %.1f× the checkout, consider git gc or a shallow/partial clone%s",
+			colorYellow, float64(g.GitDirSize)/float64(g.CheckoutSize), colorReset)
+	}
+	fmt.Fprintf(b, "  %-16s %s%10s%s  %s\n", ".git total", colorBold, humanizeBytes(g.GitDirSize), colorReset, bloat)
+	if g.CheckoutSize > 0 {
+		row("Checkout", g.CheckoutSize, "working tree without .git")
+	}
+	row("Packs", g.PackSize, fmt.Sprintf("%d packs", g.PackCount))
+	row("Loose objects", g.LooseSize, fmt.Sprintf("%s objects", formatNumber(g.LooseCount)))
+	row("LFS cache", g.LFSSize, "")
+	row("Untracked", g.UntrackedSize, fmt.Sprintf("%s files", formatNumber(int64(g.UntrackedCount))))
+	row("Ignored", g.IgnoredSize, fmt.Sprintf("%s paths", formatNumber(int64(g.IgnoredCount))))
+
+	if len(g.Worktrees) > 0 {
+		fmt.Fprintf(b, "\n  %sWorktrees%s\n", colorPurpleBold, colorReset)
+		for _, wt := range g.Worktrees {
+			if wt.Missing {
+				fmt.Fprintf(b, "  %10s  %s %s(missing, git worktree prune)%s\n", "--", displayPath(wt.Path), colorYellow, colorReset)
+				continue
+			}
+			fmt.Fprintf(b, "  %10s  %s\n", humanizeBytes(wt.Size), displayPath(wt.Path))
+		}
+	}
+
+	if len(g.LargestBlobs) > 0 {
+		nameWidth := calculateNameWidth(m.width)
+		fmt.Fprintf(b, "\n  %sLargest blobs in history%s\n", colorPurpleBold, colorReset)
+		for idx, blob := range g.LargestBlobs {
+			name := blob.Path
+			if name == "" {
+				name = blob.ID[:min(len(blob.ID), 12)]
+			}
+			state := ""
+			if !blob.InCheckout {
+				state = colorYellow + "history only" + colorReset
+			}
+			fmt.Fprintf(b, "  %2d. %10s  %s  %s\n", idx+1, humanizeBytes(blob.Size), truncateMiddle(name, nameWidth), state)
+		}
+	}
+
+	for _, warning := range g.Warnings {
+		fmt.Fprintf(b, "\n  %s%s%s", colorGray, warning, colorReset)
+	}
+	if len(g.Warnings) > 0 {
+		fmt.Fprintln(b)
+	}
+
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "%s%s%s\n", colorGray, footer(m.hint(actionRefresh, "Refresh"), "← Back", m.hint(actionQuit, "Quit")), colorReset)
 }
 
 // calculateViewport returns visible rows for the current terminal height.
-func calculateViewport(termHeight int, isLargeFiles bool) int {
+// writeList writes the rendered list rows, with the preview pane to their
+// right when it is open.
+func (m model) writeList(b *strings.Builder, list string) {
+	if !m.showPreview {
+		b.WriteString(list)
+		return
+	}
+	rows := strings.Split(strings.TrimSuffix(list, "\n"), "\n")
+	width := m.listWidth()
+	if m.width <= 0 {
+		width = 0
+		for _, row := range rows {
+			width = max(width, lipgloss.Width(row)+1)
+		}
+	}
+	clip := lipgloss.NewStyle().MaxWidth(width - 1)
+	pane := m.previewLines(previewWidth(m.width) - 2)
+	pane = pane[:min(len(pane), max(len(rows), calculateViewport(m.height)+1))]
+	for i := range max(len(rows), len(pane)) {
+		row := ""
+		if i < len(rows) {
+			row = clip.Render(rows[i])
+		}
+		fmt.Fprintf(b, "%s%s%s│%s ", row, strings.Repeat(" ", max(width-lipgloss.Width(row), 1)), colorGray, colorReset)
+		if i < len(pane) {
+			b.WriteString(pane[i])
+		}
+		fmt.Fprintln(b)
+	}
+}
+
+func calculateViewport(termHeight int) int {
 	if termHeight <= 0 {
 		return defaultViewport
 	}
 
-	reserved := 6 // Header + footer
-	if isLargeFiles {
-		reserved = 5
-	}
+	reserved := 6 // Header + footer, and the summary line above large files
 
 	available := termHeight - reserved
 
@@ -418,3 +968,140 @@ func calculateViewport(termHeight int, isLargeFiles bool) int {
 
 	return available
 }
+
+// renderIssues lists the paths the last scan could not read.
+func (m model) renderIssues(b *strings.Builder) {
+	report := m.scanIssues
+	fmt.Fprintf(b, "%s⚠ %s%s\n", colorYellow, issueSummary(report), colorReset)
+	fmt.Fprintf(b, "%s%s", colorGray, issueKindCounts(report.Issues))
+	if shown := len(report.Issues); shown < report.Total {
+		fmt.Fprintf(b, ", first %d shown", shown)
+	}
+	fmt.Fprintf(b, "%s\n\n", colorReset)
+
+	viewport := calculateViewport(m.height)
+	start := max(m.issueOffset, 0)
+	end := min(start+viewport, len(report.Issues))
+	for idx := start; idx < end; idx++ {
+		issue := report.Issues[idx]
+		prefix := "   "
+		pathColor := ""
+		if idx == m.issueSelected {
+			prefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			pathColor = colorCyan
+		}
+		fmt.Fprintf(b, "%s%s%-60s%s  %s%s%s\n",
+			prefix, pathColor, truncateMiddle(displayPath(issue.Path), 60), colorReset, colorGray, issue.Kind, colorReset)
+	}
+
+	fmt.Fprintln(b)
+	if report.HasPermissionIssues() {
+		fmt.Fprintf(b, "%sSizes above exclude these paths. Run sudo mo analyze %s to include protected folders.%s\n",
+			colorGray, displayPath(m.path), colorReset)
+	}
+	fmt.Fprintf(b, "%s↑↓ | ← Back | %s%s\n", colorGray, m.hint(actionQuit, "Quit"), colorReset)
+}
+
+// renderOwners shows space per user and per group for the current root.
+func (m model) renderOwners(b *strings.Builder) {
+	report := m.scanOwners
+	var total int64
+	for _, owner := range report.Users {
+		total += owner.Size
+	}
+	maxSize := int64(1)
+	if len(report.Users) > 0 {
+		maxSize = max(report.Users[0].Size, 1)
+	}
+
+	fmt.Fprintf(b, "%sUsers%s\n", colorBold, colorReset)
+	for idx, owner := range report.Users {
+		prefix := "   "
+		nameColor := ""
+		if idx == m.ownerSelected {
+			prefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor = colorCyan
+		}
+		percent := 0.0
+		if total > 0 {
+			percent = float64(owner.Size) / float64(total) * 100
+		}
+		fmt.Fprintf(b, "%s%s %5.1f%%  |  %s%-16s%s %10s  %s%s files%s\n",
+			prefix, coloredProgressBar(owner.Size, maxSize, percent), percent,
+			nameColor, owner.Name, colorReset, humanizeBytes(owner.Size),
+			colorGray, formatNumber(owner.Files), colorReset)
+	}
+
+	if len(report.Groups) > 0 {
+		fmt.Fprintf(b, "\n%sGroups%s\n", colorBold, colorReset)
+		for _, group := range report.Groups {
+			fmt.Fprintf(b, "   %-16s %10s  %s%s files%s\n",
+				group.Name, humanizeBytes(group.Size), colorGray, formatNumber(group.Files), colorReset)
+		}
+	}
+
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "%s↑↓ | Enter Filter | A All | ← Back | %s%s\n", colorGray, m.hint(actionQuit, "Quit"), colorReset)
+}
+
+// renderBookmarks lists saved folders with their last known size and age.
+func (m model) renderBookmarks(b *strings.Builder) {
+	fmt.Fprintf(b, "%sBookmarks%s\n\n", colorBold, colorReset)
+	for idx, row := range m.bookmarkRows {
+		prefix := "   "
+		nameColor := ""
+		if idx == m.bookmarkSelected {
+			prefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor = colorCyan
+		}
+		size := "—"
+		age := "not scanned"
+		switch {
+		case row.Missing:
+			age = "missing"
+		case row.Size > 0:
+			size = humanizeBytes(row.Size)
+			age = formatAge(row.Updated)
+		}
+		fmt.Fprintf(b, "%s%s%s%s %s%-40s%s %10s  %s%s%s\n",
+			prefix, nameColor, padName(truncateMiddle(row.Name, 16), 16), colorReset,
+			colorGray, truncateMiddle(displayPath(row.Path), 40), colorReset,
+			size, colorGray, age, colorReset)
+	}
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "%s↑↓ | Enter Jump | %s | ← Back | %s%s\n", colorGray, m.hint(actionDelete, "Remove"), m.hint(actionQuit, "Quit"), colorReset)
+}
+
+// renderMoves lists recorded moves, newest first, with where each folder went.
+func (m model) renderMoves(b *strings.Builder) {
+	fmt.Fprintf(b, "%sMoved folders%s\n\n", colorBold, colorReset)
+	for idx, record := range m.moveRows {
+		prefix := "   "
+		nameColor := ""
+		if idx == m.moveRowSelected {
+			prefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor = colorCyan
+		}
+		stub := "no symlink"
+		if record.Symlink {
+			stub = "symlink"
+		}
+		fmt.Fprintf(b, "%s%s%s%s %s→ %-36s%s %10s  %s%s, %s%s\n",
+			prefix, nameColor, padName(truncateMiddle(displayPath(record.Source), 36), 36), colorReset,
+			colorGray, truncateMiddle(displayPath(record.Destination), 36), colorReset,
+			humanizeBytes(record.Size), colorGray, formatAge(record.MovedAt), stub, colorReset)
+	}
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "%s↑↓ | Enter Restore | ← Back | %s%s\n", colorGray, m.hint(actionQuit, "Quit"), colorReset)
+}
+
+// renderBookmarkPrompt renders the bookmark name prompt, if any.
+func (m model) renderBookmarkPrompt(b *strings.Builder) {
+	if !m.bookmarkNaming {
+		return
+	}
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "%sBookmark:%s %s  %sName:%s %s\n",
+		colorYellow, colorReset, displayPath(m.bookmarkTarget()), colorGray, colorReset, m.bookmarkName)
+	fmt.Fprintf(b, "%sType a name  |  Enter save  |  ESC cancel%s\n", colorGray, colorReset)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tw93/mole/internal/keymap"
+)
+
+const (
+	actionQuit      keymap.Action = "quit"
+	actionToggleCat keymap.Action = "toggle_cat"
+	actionHelp      keymap.Action = "help"
+)
+
+var statusBindings = []keymap.Binding{
+	{Action: actionToggleCat, Keys: []string{"k"}, Help: "Show or hide the cat"},
+	{Action: actionHelp, Keys: []string{"?"}, Help: "Show this help"},
+	{Action: actionQuit, Keys: []string{"q", "esc", "ctrl+c"}, Help: "Quit"},
+}
+
+// defaultKeys backs models built without loading the user's file, such as in tests.
+var defaultKeys = keymap.New(statusBindings)
+
+// loadKeys reads ~/.config/mole/keys. Lines it skips are printed to stderr,
+// where they are left on screen once the status view exits.
+func loadKeys() *keymap.Keymap {
+	keys, warnings := keymap.Load("status", statusBindings)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "keys: %s\n", warning)
+	}
+	return keys
+}
+
+func (m model) keymap() *keymap.Keymap {
+	if m.keys == nil {
+		return defaultKeys
+	}
+	return m.keys
+}
+
+// renderHelp lists the active bindings in place of the cards.
+func (m model) renderHelp() string {
+	lines := []string{titleStyle.Render("Status keys"), ""}
+	for _, binding := range m.keymap().Bindings(actionToggleCat, actionHelp, actionQuit) {
+		lines = append(lines, fmt.Sprintf("  %s %s", primaryStyle.Render(fmt.Sprintf("%-16s", keymap.KeysLabel(binding.Keys))), binding.Help))
+	}
+	lines = append(lines, "")
+	if path, err := keymap.DefaultPath(); err == nil {
+		lines = append(lines, subtleStyle.Render("Rebind in "+path+", e.g. status.toggle_cat = c"))
+	}
+	lines = append(lines, subtleStyle.Render("Any key to close"))
+	return strings.Join(lines, "\n")
+}
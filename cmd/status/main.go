@@ -10,6 +10,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tw93/mole/internal/keymap"
 )
 
 const refreshInterval = time.Second
@@ -38,6 +40,8 @@ type model struct {
 	collecting  bool
 	animFrame   int
 	catHidden   bool // true = hidden, false = visible
+	keys        *keymap.Keymap
+	showHelp    bool // Key binding overlay
 }
 
 // getConfigPath returns the path to the status preferences file.
@@ -84,6 +88,7 @@ func newModel() model {
 	return model{
 		collector: NewCollector(),
 		catHidden: loadCatHidden(),
+		keys:      loadKeys(),
 	}
 }
 
@@ -94,14 +99,22 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "esc", "ctrl+c":
+		action := m.keymap().Action(msg.String())
+		if m.showHelp && action != actionQuit {
+			m.showHelp = false
+			return m, nil
+		}
+		switch action {
+		case actionQuit:
 			return m, tea.Quit
-		case "k":
+		case actionToggleCat:
 			// Toggle cat visibility and persist preference
 			m.catHidden = !m.catHidden
 			saveCatHidden(m.catHidden)
 			return m, nil
+		case actionHelp:
+			m.showHelp = true
+			return m, nil
 		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -140,6 +153,9 @@ func (m model) View() string {
 	}
 
 	header, mole := renderHeader(m.metrics, m.errMessage, m.animFrame, m.width, m.catHidden)
+	if m.showHelp {
+		return lipgloss.JoinVertical(lipgloss.Left, header, "", m.renderHelp())
+	}
 	cardWidth := 0
 	if m.width > 80 {
 		cardWidth = max(24, m.width/2-4)
@@ -0,0 +1,285 @@
+// Package keymap maps key presses to named actions so users can rebind them
+// from ~/.config/mole/keys.
+package keymap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Action names something a screen can do, such as "delete" or "quit".
+type Action string
+
+// Binding is an action with its keys and a short description for the help overlay.
+type Binding struct {
+	Action Action
+	Keys   []string
+	Help   string
+}
+
+// Keymap resolves keys for one program. The zero value has no bindings.
+type Keymap struct {
+	bindings []Binding
+	byKey    map[string]Action
+}
+
+// DefaultPath returns ~/.config/mole/keys.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "mole", "keys"), nil
+}
+
+// New builds a keymap from defaults alone.
+func New(defaults []Binding) *Keymap {
+	k := &Keymap{}
+	for _, b := range defaults {
+		k.bindings = append(k.bindings, Binding{Action: b.Action, Keys: append([]string(nil), b.Keys...), Help: b.Help})
+	}
+	k.index()
+	return k
+}
+
+// Load reads the user's keys file for app on top of defaults. A missing file
+// leaves the defaults in place; an unreadable file or lines that cannot be
+// applied come back as warnings naming the file.
+func Load(app string, defaults []Binding) (*Keymap, []string) {
+	path, err := DefaultPath()
+	if err != nil {
+		return New(defaults), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return New(defaults), nil
+		}
+		return New(defaults), []string{err.Error()}
+	}
+	defer file.Close() //nolint:errcheck
+	k, warnings := Parse(file, app, defaults)
+	for i, warning := range warnings {
+		warnings[i] = path + ": " + warning
+	}
+	return k, warnings
+}
+
+// Parse applies overrides to defaults. Each line is "action = key[, key...]",
+// optionally scoped as "app.action"; scoped lines win over unscoped ones.
+// A rebound key is taken away from whichever action had it by default.
+// Lines that cannot be applied are returned as warnings.
+func Parse(r io.Reader, app string, defaults []Binding) (*Keymap, []string) {
+	k := New(defaults)
+	known := make(map[Action]bool, len(k.bindings))
+	for _, b := range k.bindings {
+		known[b.Action] = true
+	}
+
+	var warnings []string
+	unscoped := make(map[Action][]string)
+	scoped := make(map[Action][]string)
+	var order []Action
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("line %d: expected action = key", lineNo))
+			continue
+		}
+		name = strings.TrimSpace(name)
+		target, isScoped := unscoped, false
+		if scope, action, found := strings.Cut(name, "."); found {
+			if scope != app {
+				continue
+			}
+			name = action
+			target, isScoped = scoped, true
+		}
+		action := Action(name)
+		if !known[action] {
+			// Unscoped names may belong to the other program.
+			if isScoped {
+				warnings = append(warnings, fmt.Sprintf("line %d: unknown action %q", lineNo, name))
+			}
+			continue
+		}
+		keys := parseKeys(value)
+		if len(keys) == 0 {
+			warnings = append(warnings, fmt.Sprintf("line %d: no keys for %q", lineNo, name))
+			continue
+		}
+		if _, seen := unscoped[action]; !seen {
+			if _, seen := scoped[action]; !seen {
+				order = append(order, action)
+			}
+		}
+		target[action] = keys
+	}
+
+	for _, action := range order {
+		keys, ok := scoped[action]
+		if !ok {
+			keys = unscoped[action]
+		}
+		k.rebind(action, keys)
+	}
+	k.index()
+	return k, warnings
+}
+
+// parseKeys splits "x, ctrl+d" and normalizes names to bubbletea's KeyMsg strings.
+func parseKeys(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		switch strings.ToLower(key) {
+		case "":
+			continue
+		case "space":
+			key = " "
+		case "escape":
+			key = "esc"
+		case "return":
+			key = "enter"
+		}
+		if len(key) > 1 {
+			key = strings.ToLower(key)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (k *Keymap) rebind(action Action, keys []string) {
+	taken := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		taken[key] = true
+	}
+	for i := range k.bindings {
+		if k.bindings[i].Action == action {
+			k.bindings[i].Keys = keys
+			continue
+		}
+		kept := k.bindings[i].Keys[:0]
+		for _, key := range k.bindings[i].Keys {
+			if !taken[key] {
+				kept = append(kept, key)
+			}
+		}
+		k.bindings[i].Keys = kept
+	}
+}
+
+func (k *Keymap) index() {
+	k.byKey = make(map[string]Action)
+	for _, b := range k.bindings {
+		for _, key := range b.Keys {
+			if _, exists := k.byKey[key]; !exists {
+				k.byKey[key] = b.Action
+			}
+		}
+	}
+}
+
+// Action returns the action bound to key, or "" when the key is unbound.
+func (k *Keymap) Action(key string) Action {
+	if k == nil {
+		return ""
+	}
+	return k.byKey[key]
+}
+
+// Keys returns the keys bound to action.
+func (k *Keymap) Keys(action Action) []string {
+	if k == nil {
+		return nil
+	}
+	for _, b := range k.bindings {
+		if b.Action == action {
+			return b.Keys
+		}
+	}
+	return nil
+}
+
+// Label renders the first key of action for footers, such as "⌫" or "Ctrl+D".
+func (k *Keymap) Label(action Action) string {
+	keys := k.Keys(action)
+	if len(keys) == 0 {
+		return ""
+	}
+	return KeyLabel(keys[0])
+}
+
+// Bindings returns the bindings for actions, in that order, skipping unbound ones.
+func (k *Keymap) Bindings(actions ...Action) []Binding {
+	if k == nil {
+		return nil
+	}
+	var out []Binding
+	for _, action := range actions {
+		for _, b := range k.bindings {
+			if b.Action == action && len(b.Keys) > 0 {
+				out = append(out, b)
+			}
+		}
+	}
+	return out
+}
+
+// KeyLabel formats a key string for display.
+func KeyLabel(key string) string {
+	switch key {
+	case " ":
+		return "Space"
+	case "backspace", "delete":
+		return "⌫"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "esc":
+		return "Esc"
+	case "enter":
+		return "Enter"
+	case "tab":
+		return "Tab"
+	}
+	if mod, rest, ok := strings.Cut(key, "+"); ok && mod != "" && rest != "" {
+		return strings.ToUpper(mod[:1]) + mod[1:] + "+" + strings.ToUpper(rest)
+	}
+	return strings.ToUpper(key)
+}
+
+// KeysLabel joins every key of a binding for the help overlay.
+func KeysLabel(keys []string) string {
+	labels := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		label := KeyLabel(key)
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	return strings.Join(labels, " / ")
+}
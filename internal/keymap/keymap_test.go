@@ -0,0 +1,123 @@
+package keymap
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var testDefaults = []Binding{
+	{Action: "delete", Keys: []string{"delete", "backspace"}, Help: "Delete"},
+	{Action: "refresh", Keys: []string{"r", "R"}, Help: "Refresh"},
+	{Action: "quit", Keys: []string{"q", "ctrl+c"}, Help: "Quit"},
+}
+
+func TestDefaultsResolve(t *testing.T) {
+	k := New(testDefaults)
+	if got := k.Action("backspace"); got != "delete" {
+		t.Errorf("backspace = %q, want delete", got)
+	}
+	if got := k.Action("x"); got != "" {
+		t.Errorf("unbound key = %q, want empty", got)
+	}
+	if got := k.Label("delete"); got != "⌫" {
+		t.Errorf("delete label = %q, want ⌫", got)
+	}
+}
+
+func TestParseRebindsAndStealsKeys(t *testing.T) {
+	config := `
+# Multiplexer uses backspace.
+delete = x
+refresh = ctrl+r, Space
+analyze.quit = backspace
+`
+	k, warnings := Parse(strings.NewReader(config), "analyze", testDefaults)
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	if got := k.Action("x"); got != "delete" {
+		t.Errorf("x = %q, want delete", got)
+	}
+	if got := k.Action("backspace"); got != "quit" {
+		t.Errorf("backspace = %q, want quit", got)
+	}
+	if got := k.Action("r"); got != "" {
+		t.Errorf("old refresh key still bound to %q", got)
+	}
+	if got := k.Action(" "); got != "refresh" {
+		t.Errorf("space = %q, want refresh", got)
+	}
+	if got := k.Label("refresh"); got != "Ctrl+R" {
+		t.Errorf("refresh label = %q, want Ctrl+R", got)
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	config := `
+delete = x
+status.delete = y
+analyze.delete = z
+`
+	k, _ := Parse(strings.NewReader(config), "analyze", testDefaults)
+	if got := k.Keys("delete"); !slices.Equal(got, []string{"z"}) {
+		t.Errorf("scoped binding should win, got %v", got)
+	}
+
+	k, _ = Parse(strings.NewReader("analyze.delete = z\n"), "status", testDefaults)
+	if got := k.Keys("delete"); !slices.Equal(got, []string{"delete", "backspace"}) {
+		t.Errorf("other program's scope should be ignored, got %v", got)
+	}
+}
+
+func TestParseWarnings(t *testing.T) {
+	config := `
+no equals sign
+analyze.nope = x
+toggle_cat = c
+delete =
+`
+	k, warnings := Parse(strings.NewReader(config), "analyze", testDefaults)
+	if len(warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %v", warnings)
+	}
+	if got := k.Action("backspace"); got != "delete" {
+		t.Errorf("bad lines should keep defaults, backspace = %q", got)
+	}
+}
+
+func TestLoadReturnsWarnings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if _, warnings := Load("analyze", testDefaults); warnings != nil {
+		t.Fatalf("a missing keys file should not warn, got %v", warnings)
+	}
+
+	path := filepath.Join(home, ".config", "mole", "keys")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("delete = x\nanalyze.nope = y\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	k, warnings := Load("analyze", testDefaults)
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], path+": line 2") {
+		t.Fatalf("expected the bad line with the file named, got %v", warnings)
+	}
+	if got := k.Action("x"); got != "delete" {
+		t.Errorf("good lines should still apply, x = %q", got)
+	}
+}
+
+func TestBindingsSkipsUnbound(t *testing.T) {
+	k, _ := Parse(strings.NewReader("quit = q, ctrl+c, r, R\n"), "analyze", testDefaults)
+	got := k.Bindings("refresh", "quit")
+	if len(got) != 1 || got[0].Action != "quit" {
+		t.Fatalf("expected only quit, got %+v", got)
+	}
+	if label := KeysLabel(got[0].Keys); label != "Q / Ctrl+C / R" {
+		t.Errorf("KeysLabel = %q", label)
+	}
+}
@@ -0,0 +1,116 @@
+package scan
+
+import (
+	"os"
+	"sync"
+)
+
+// DeviceKind is the kind of storage behind a path.
+type DeviceKind int
+
+const (
+	DeviceUnknown DeviceKind = iota
+	DeviceSSD
+	DeviceHDD
+	DeviceRemovable // USB sticks and SD cards
+	DeviceNetwork
+)
+
+func (k DeviceKind) String() string {
+	switch k {
+	case DeviceSSD:
+		return "SSD"
+	case DeviceHDD:
+		return "HDD"
+	case DeviceRemovable:
+		return "removable"
+	case DeviceNetwork:
+		return "network"
+	default:
+		return "unknown"
+	}
+}
+
+// Device describes the storage holding a path.
+type Device struct {
+	Kind   DeviceKind
+	FSType string // Filesystem, such as "apfs", "ext4" or "nfs"; empty when unrecognized.
+	Name   string // Backing disk, such as "sda" or "disk3s1", when there is one.
+}
+
+// devices caches detection per filesystem id; a disk does not change kind while
+// it is mounted, and on macOS detection runs diskutil.
+var devices sync.Map
+
+// DetectDevice reports what kind of storage holds path. Anything it cannot
+// identify is DeviceUnknown, which scans like an SSD.
+func DetectDevice(path string) Device {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Device{}
+	}
+	dev, ok := deviceOf(info)
+	if !ok {
+		return detectDevice(path)
+	}
+	if cached, ok := devices.Load(dev); ok {
+		return cached.(Device)
+	}
+	d := detectDevice(path)
+	devices.Store(dev, d)
+	return d
+}
+
+// Strategy is how a scan spreads its work over workers.
+type Strategy int
+
+const (
+	StrategyAuto       Strategy = iota // Chosen from the root's device.
+	StrategyParallel                   // The CPU-based worker counts; for SSDs.
+	StrategySequential                 // One walker reading entries in inode order; for spinning disks.
+	StrategyGentle                     // A couple of workers; for USB sticks and SD cards.
+	StrategyNetwork                    // Enough requests in flight to hide latency without flooding the server.
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case StrategyParallel:
+		return "parallel"
+	case StrategySequential:
+		return "sequential"
+	case StrategyGentle:
+		return "gentle"
+	case StrategyNetwork:
+		return "network"
+	default:
+		return "auto"
+	}
+}
+
+// StrategyFor picks the strategy that suits d.
+func StrategyFor(d Device) Strategy {
+	switch d.Kind {
+	case DeviceHDD:
+		return StrategySequential
+	case DeviceRemovable:
+		return StrategyGentle
+	case DeviceNetwork:
+		return StrategyNetwork
+	default:
+		return StrategyParallel
+	}
+}
+
+// limits caps the default worker counts for the strategy.
+func (s Strategy) limits() Profile {
+	switch s {
+	case StrategySequential:
+		return Profile{Workers: 1, DirWorkers: 1, FoldWorkers: 1}
+	case StrategyGentle:
+		return Profile{Workers: 2, DirWorkers: 2, FoldWorkers: 1}
+	case StrategyNetwork:
+		return Profile{Workers: 8, DirWorkers: 4, FoldWorkers: 2}
+	default:
+		return Profile{}
+	}
+}
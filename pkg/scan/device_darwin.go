@@ -0,0 +1,59 @@
+package scan
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+var networkFSTypes = map[string]bool{
+	"nfs": true, "smbfs": true, "afpfs": true, "webdav": true, "cifs": true,
+}
+
+// detectDevice reads the filesystem type from statfs and the disk's medium
+// from diskutil.
+func detectDevice(path string) Device {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(path, &fs); err != nil {
+		return Device{}
+	}
+	d := Device{FSType: unix.ByteSliceToString(fs.Fstypename[:])}
+	if fs.Flags&unix.MNT_LOCAL == 0 || networkFSTypes[d.FSType] {
+		d.Kind = DeviceNetwork
+		return d
+	}
+	d.Name = strings.TrimPrefix(unix.ByteSliceToString(fs.Mntfromname[:]), "/dev/")
+
+	info := diskutilInfo(unix.ByteSliceToString(fs.Mntonname[:]))
+	switch {
+	case info["Removable Media"] == "Removable":
+		d.Kind = DeviceRemovable
+	case info["Solid State"] == "No":
+		d.Kind = DeviceHDD
+	case info["Protocol"] == "USB" || info["Protocol"] == "Secure Digital":
+		d.Kind = DeviceRemovable
+	case info["Solid State"] == "Yes":
+		d.Kind = DeviceSSD
+	}
+	return d
+}
+
+// diskutilInfo returns the "Key: Value" lines of diskutil info for a mount point.
+func diskutilInfo(mount string) map[string]string {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "diskutil", "info", mount).Output()
+	if err != nil {
+		return nil
+	}
+	info := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			info[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return info
+}
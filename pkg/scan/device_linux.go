@@ -0,0 +1,88 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+var linuxFSTypes = map[uint32]string{
+	unix.EXT4_SUPER_MAGIC:      "ext4",
+	unix.XFS_SUPER_MAGIC:       "xfs",
+	unix.BTRFS_SUPER_MAGIC:     "btrfs",
+	unix.F2FS_SUPER_MAGIC:      "f2fs",
+	unix.TMPFS_MAGIC:           "tmpfs",
+	unix.OVERLAYFS_SUPER_MAGIC: "overlay",
+	unix.MSDOS_SUPER_MAGIC:     "vfat",
+	unix.EXFAT_SUPER_MAGIC:     "exfat",
+	unix.FUSE_SUPER_MAGIC:      "fuse",
+	unix.NFS_SUPER_MAGIC:       "nfs",
+	unix.SMB_SUPER_MAGIC:       "smb",
+	unix.CIFS_SUPER_MAGIC:      "cifs",
+	unix.SMB2_SUPER_MAGIC:      "smb2",
+	unix.V9FS_MAGIC:            "9p",
+	unix.CEPH_SUPER_MAGIC:      "ceph",
+	unix.AFS_SUPER_MAGIC:       "afs",
+}
+
+var networkFSTypes = map[string]bool{
+	"nfs": true, "smb": true, "cifs": true, "smb2": true, "9p": true, "ceph": true, "afs": true,
+}
+
+// detectDevice reads the filesystem type from statfs and the disk's queue
+// attributes from sysfs.
+func detectDevice(path string) Device {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(path, &fs); err != nil {
+		return Device{}
+	}
+	d := Device{FSType: linuxFSTypes[uint32(fs.Type)]}
+	if networkFSTypes[d.FSType] {
+		d.Kind = DeviceNetwork
+		return d
+	}
+
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil {
+		return d
+	}
+	// Filesystems without a block device (tmpfs, overlay, btrfs subvolumes) have no entry.
+	sys, err := filepath.EvalSymlinks(fmt.Sprintf("/sys/dev/block/%d:%d", unix.Major(uint64(st.Dev)), unix.Minor(uint64(st.Dev))))
+	if err != nil {
+		return d
+	}
+	// Partitions sit inside their disk's directory, which holds the queue attributes.
+	disk := sys
+	if _, err := os.Stat(filepath.Join(sys, "partition")); err == nil {
+		disk = filepath.Dir(sys)
+	}
+	d.Name = filepath.Base(disk)
+
+	rotational, ok := readSysFlag(filepath.Join(disk, "queue", "rotational"))
+	removable, _ := readSysFlag(filepath.Join(disk, "removable"))
+	// Virtual disks report themselves as rotational whatever backs them.
+	virtual := strings.Contains(disk, "/virtio") || strings.Contains(disk, "/vbd-")
+	switch {
+	case removable:
+		d.Kind = DeviceRemovable
+	case virtual:
+	case rotational:
+		d.Kind = DeviceHDD
+	case strings.Contains(disk, "/usb"):
+		d.Kind = DeviceRemovable
+	case ok:
+		d.Kind = DeviceSSD
+	}
+	return d
+}
+
+func readSysFlag(path string) (value, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, false
+	}
+	return strings.TrimSpace(string(data)) == "1", true
+}
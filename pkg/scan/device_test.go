@@ -0,0 +1,34 @@
+package scan
+
+import "testing"
+
+func TestStrategyForDevice(t *testing.T) {
+	cases := map[DeviceKind]Strategy{
+		DeviceUnknown:   StrategyParallel,
+		DeviceSSD:       StrategyParallel,
+		DeviceHDD:       StrategySequential,
+		DeviceRemovable: StrategyGentle,
+		DeviceNetwork:   StrategyNetwork,
+	}
+	for kind, want := range cases {
+		if got := StrategyFor(Device{Kind: kind}); got != want {
+			t.Errorf("%v: expected %v, got %v", kind, want, got)
+		}
+	}
+}
+
+func TestStrategyCapsProfile(t *testing.T) {
+	p := LowImpact.capped(StrategySequential.limits())
+	if p.Workers != 1 || p.DirWorkers != 1 || p.FoldWorkers != 1 {
+		t.Fatalf("expected one worker of each kind, got %+v", p)
+	}
+	if p.FilesPerSec != LowImpact.FilesPerSec {
+		t.Fatalf("rate limits should be kept, got %+v", p)
+	}
+	if p := LowImpact.capped(StrategyNetwork.limits()); p.Workers != LowImpact.Workers {
+		t.Fatalf("a looser cap must not raise the profile, got %+v", p)
+	}
+	if p := FullSpeed.capped(StrategyParallel.limits()); p != FullSpeed {
+		t.Fatalf("parallel should leave full speed alone, got %+v", p)
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package scan
 
 import (
 	"container/heap"
@@ -11,26 +11,26 @@ func TestEntryHeap(t *testing.T) {
 		heap.Init(h)
 
 		// Push entries with varying sizes.
-		heap.Push(h, dirEntry{Name: "medium", Size: 500})
-		heap.Push(h, dirEntry{Name: "small", Size: 100})
-		heap.Push(h, dirEntry{Name: "large", Size: 1000})
+		heap.Push(h, Entry{Name: "medium", Size: 500})
+		heap.Push(h, Entry{Name: "small", Size: 100})
+		heap.Push(h, Entry{Name: "large", Size: 1000})
 
 		if h.Len() != 3 {
 			t.Errorf("Len() = %d, want 3", h.Len())
 		}
 
 		// Min-heap: smallest should come out first.
-		first := heap.Pop(h).(dirEntry)
+		first := heap.Pop(h).(Entry)
 		if first.Name != "small" || first.Size != 100 {
 			t.Errorf("first Pop() = %v, want {small, 100}", first)
 		}
 
-		second := heap.Pop(h).(dirEntry)
+		second := heap.Pop(h).(Entry)
 		if second.Name != "medium" || second.Size != 500 {
 			t.Errorf("second Pop() = %v, want {medium, 500}", second)
 		}
 
-		third := heap.Pop(h).(dirEntry)
+		third := heap.Pop(h).(Entry)
 		if third.Name != "large" || third.Size != 1000 {
 			t.Errorf("third Pop() = %v, want {large, 1000}", third)
 		}
@@ -53,8 +53,8 @@ func TestEntryHeap(t *testing.T) {
 		h := &entryHeap{}
 		heap.Init(h)
 
-		heap.Push(h, dirEntry{Name: "only", Size: 42})
-		popped := heap.Pop(h).(dirEntry)
+		heap.Push(h, Entry{Name: "only", Size: 42})
+		popped := heap.Pop(h).(Entry)
 
 		if popped.Name != "only" || popped.Size != 42 {
 			t.Errorf("Pop() = %v, want {only, 42}", popped)
@@ -65,13 +65,13 @@ func TestEntryHeap(t *testing.T) {
 		h := &entryHeap{}
 		heap.Init(h)
 
-		heap.Push(h, dirEntry{Name: "a", Size: 100})
-		heap.Push(h, dirEntry{Name: "b", Size: 100})
-		heap.Push(h, dirEntry{Name: "c", Size: 100})
+		heap.Push(h, Entry{Name: "a", Size: 100})
+		heap.Push(h, Entry{Name: "b", Size: 100})
+		heap.Push(h, Entry{Name: "c", Size: 100})
 
 		// All have same size, heap property still holds.
 		for i := 0; i < 3; i++ {
-			popped := heap.Pop(h).(dirEntry)
+			popped := heap.Pop(h).(Entry)
 			if popped.Size != 100 {
 				t.Errorf("Pop() size = %d, want 100", popped.Size)
 			}
@@ -85,26 +85,26 @@ func TestLargeFileHeap(t *testing.T) {
 		heap.Init(h)
 
 		// Push entries with varying sizes.
-		heap.Push(h, fileEntry{Name: "medium.bin", Size: 500})
-		heap.Push(h, fileEntry{Name: "small.txt", Size: 100})
-		heap.Push(h, fileEntry{Name: "large.iso", Size: 1000})
+		heap.Push(h, File{Name: "medium.bin", Size: 500})
+		heap.Push(h, File{Name: "small.txt", Size: 100})
+		heap.Push(h, File{Name: "large.iso", Size: 1000})
 
 		if h.Len() != 3 {
 			t.Errorf("Len() = %d, want 3", h.Len())
 		}
 
 		// Min-heap: smallest should come out first.
-		first := heap.Pop(h).(fileEntry)
+		first := heap.Pop(h).(File)
 		if first.Name != "small.txt" || first.Size != 100 {
 			t.Errorf("first Pop() = %v, want {small.txt, 100}", first)
 		}
 
-		second := heap.Pop(h).(fileEntry)
+		second := heap.Pop(h).(File)
 		if second.Name != "medium.bin" || second.Size != 500 {
 			t.Errorf("second Pop() = %v, want {medium.bin, 500}", second)
 		}
 
-		third := heap.Pop(h).(fileEntry)
+		third := heap.Pop(h).(File)
 		if third.Name != "large.iso" || third.Size != 1000 {
 			t.Errorf("third Pop() = %v, want {large.iso, 1000}", third)
 		}
@@ -116,7 +116,7 @@ func TestLargeFileHeap(t *testing.T) {
 		heap.Init(h)
 		maxSize := 3
 
-		files := []fileEntry{
+		files := []File{
 			{Name: "a", Size: 50},
 			{Name: "b", Size: 200},
 			{Name: "c", Size: 30},
@@ -138,7 +138,7 @@ func TestLargeFileHeap(t *testing.T) {
 		// Extract remaining (should be 3 largest: 150, 200, 300).
 		var sizes []int64
 		for h.Len() > 0 {
-			sizes = append(sizes, heap.Pop(h).(fileEntry).Size)
+			sizes = append(sizes, heap.Pop(h).(File).Size)
 		}
 
 		// Min-heap pops in ascending order.
@@ -0,0 +1,178 @@
+package scan
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const fileIndexVersion = 1
+
+// FileIndex holds every file at or above MinSize that scans have seen, across
+// roots. A scan with Options.FileIndex set adds what it finds and, when it runs
+// to completion, drops files under its root that are gone or have shrunk below
+// MinSize, so rescans keep the index current without starting over. Files in
+// folded directories are indexed too, though LargeFiles leaves them out.
+// It is safe for concurrent use.
+type FileIndex struct {
+	MinSize int64
+
+	mu    sync.Mutex
+	files map[string]File
+}
+
+// NewFileIndex returns an empty index for files of at least minSize bytes.
+func NewFileIndex(minSize int64) *FileIndex {
+	return &FileIndex{MinSize: minSize, files: make(map[string]File)}
+}
+
+// indexFile is the on-disk form of an indexed file.
+type indexFile struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+type indexData struct {
+	Version int         `json:"version"`
+	MinSize int64       `json:"min_size"`
+	Files   []indexFile `json:"files"`
+}
+
+// LoadFileIndex reads an index written by Save. A missing or outdated file
+// gives an empty index. Entries below minSize are dropped, so the threshold
+// may change between runs; a lower one fills in as roots are rescanned.
+func LoadFileIndex(path string, minSize int64) (*FileIndex, error) {
+	x := NewFileIndex(minSize)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return x, nil
+	}
+	if err != nil {
+		return x, err
+	}
+	var stored indexData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return x, err
+	}
+	if stored.Version != fileIndexVersion {
+		return x, nil
+	}
+	for _, f := range stored.Files {
+		if f.Size >= minSize {
+			x.files[f.Path] = File{Name: filepath.Base(f.Path), Path: f.Path, Size: f.Size, ModTime: f.ModTime}
+		}
+	}
+	return x, nil
+}
+
+// Save writes the index to path, replacing any earlier copy in one step.
+func (x *FileIndex) Save(path string) error {
+	x.mu.Lock()
+	stored := indexData{Version: fileIndexVersion, MinSize: x.MinSize, Files: make([]indexFile, 0, len(x.files))}
+	for _, f := range x.files {
+		stored.Files = append(stored.Files, indexFile{Path: f.Path, Size: f.Size, ModTime: f.ModTime})
+	}
+	x.mu.Unlock()
+	slices.SortFunc(stored.Files, func(a, b indexFile) int { return strings.Compare(a.Path, b.Path) })
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Len returns the number of indexed files.
+func (x *FileIndex) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.files)
+}
+
+// Under returns the indexed files below root, largest first.
+func (x *FileIndex) Under(root string) []File {
+	x.mu.Lock()
+	var files []File
+	for path, f := range x.files {
+		if inRoot(path, root) {
+			files = append(files, f)
+		}
+	}
+	x.mu.Unlock()
+	slices.SortFunc(files, func(a, b File) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), strings.Compare(a.Path, b.Path))
+	})
+	return files
+}
+
+// updateIndex merges what this scan found into Options.FileIndex. After a
+// complete scan, files under the root that were not seen are checked on disk:
+// parts of the tree that were not walked, such as known-size directories, keep
+// their entries.
+func (r *run) updateIndex() {
+	x := r.opts.FileIndex
+	if x == nil {
+		return
+	}
+	r.indexMu.Lock()
+	seen := r.indexed
+	r.indexed = nil
+	r.indexMu.Unlock()
+
+	found := make(map[string]bool, len(seen))
+	x.mu.Lock()
+	for _, f := range seen {
+		x.files[f.Path] = f
+		found[f.Path] = true
+	}
+	var unseen []string
+	if r.ctx.Err() == nil {
+		for path := range x.files {
+			if !found[path] && inRoot(path, r.root) {
+				unseen = append(unseen, path)
+			}
+		}
+	}
+	x.mu.Unlock()
+
+	for _, path := range unseen {
+		info, err := os.Lstat(path)
+		x.mu.Lock()
+		if err != nil || !info.Mode().IsRegular() || r.fileSize(info) < x.MinSize {
+			delete(x.files, path)
+		} else {
+			x.files[path] = File{Name: info.Name(), Path: path, Size: r.fileSize(info), ModTime: info.ModTime()}
+		}
+		x.mu.Unlock()
+	}
+}
+
+func inRoot(path, root string) bool {
+	if root == string(filepath.Separator) {
+		return strings.HasPrefix(path, root)
+	}
+	return strings.HasPrefix(path, root+string(filepath.Separator))
+}
@@ -0,0 +1,98 @@
+package scan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileIndexTracksRescans(t *testing.T) {
+	root := t.TempDir()
+	big := filepath.Join(root, "videos", "big.mov")
+	gone := filepath.Join(root, "disk.img")
+	shrinks := filepath.Join(root, "deep", "er", "cache.dat")
+	writeFileWithSize(t, big, 3<<20)
+	writeFileWithSize(t, gone, 2<<20)
+	writeFileWithSize(t, shrinks, 2<<20)
+	writeFileWithSize(t, filepath.Join(root, "small.txt"), 100)
+
+	index := NewFileIndex(1 << 20)
+	s := New(Options{SizeMode: SizeApparent, TopFiles: 1, FileIndex: index})
+	if _, err := s.ScanRoot(context.Background(), root); err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+	files := index.Under(root)
+	if len(files) != 3 || files[0].Path != big || files[0].ModTime.IsZero() {
+		t.Fatalf("expected every file over the threshold, largest first, got %+v", files)
+	}
+
+	if err := os.Remove(gone); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	writeFileWithSize(t, shrinks, 10)
+	added := filepath.Join(root, "new.bin")
+	writeFileWithSize(t, added, 5<<20)
+	if _, err := s.ScanRoot(context.Background(), root); err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+	files = index.Under(root)
+	if len(files) != 2 || files[0].Path != added || files[1].Path != big {
+		t.Fatalf("expected the rescan to add new.bin and drop the rest, got %+v", files)
+	}
+	if got := index.Under(filepath.Join(root, "videos")); len(got) != 1 {
+		t.Fatalf("expected one file under videos, got %+v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.json")
+	if err := index.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadFileIndex(path, 4<<20)
+	if err != nil {
+		t.Fatalf("LoadFileIndex: %v", err)
+	}
+	if got := loaded.Under(root); len(got) != 1 || got[0].Path != added {
+		t.Fatalf("expected a raised threshold to drop smaller files, got %+v", got)
+	}
+	if missing, err := LoadFileIndex(filepath.Join(t.TempDir(), "none.json"), 0); err != nil || missing.Len() != 0 {
+		t.Fatalf("expected an empty index for a missing file, got %v, %v", missing.Len(), err)
+	}
+}
+
+func TestFileIndexKeepsEntriesOnCancel(t *testing.T) {
+	root := t.TempDir()
+	index := NewFileIndex(1)
+	index.files[filepath.Join(root, "elsewhere.bin")] = File{Path: filepath.Join(root, "elsewhere.bin"), Size: 10}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := New(Options{FileIndex: index}).newRun(ctx, root)
+	r.updateIndex()
+	if index.Len() != 1 {
+		t.Fatal("a cancelled scan must not drop entries it did not get to")
+	}
+}
+
+func TestFileIndexCoversFoldedDirectories(t *testing.T) {
+	root := t.TempDir()
+	inFold := filepath.Join(root, "node_modules", "pkg", "model.bin")
+	writeFileWithSize(t, inFold, 2<<20)
+	writeFileWithSize(t, filepath.Join(root, "node_modules", "pkg", "index.js"), 100)
+
+	index := NewFileIndex(1 << 20)
+	s := New(Options{SizeMode: SizeApparent, TopFiles: 5, FileIndex: index, SizeCache: NewSizeCache()})
+	for range 2 { // The second scan reads node_modules from the size cache.
+		result, err := s.ScanRoot(context.Background(), root)
+		if err != nil {
+			t.Fatalf("ScanRoot: %v", err)
+		}
+		if len(result.LargeFiles) != 0 {
+			t.Fatalf("folded files should stay out of LargeFiles, got %+v", result.LargeFiles)
+		}
+		if files := index.Under(root); len(files) != 1 || files[0].Path != inFold {
+			t.Fatalf("expected the file inside the folded folder to be indexed, got %+v", files)
+		}
+		index.files = make(map[string]File)
+	}
+}
@@ -0,0 +1,107 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// maxIssues is how many unreadable paths a Result lists.
+const maxIssues = 500
+
+// IssueKind says why a path was left out of a scan.
+type IssueKind int
+
+const (
+	IssuePermission IssueKind = iota
+	IssueIO
+	IssueTimeout
+	IssueVanished
+)
+
+func (k IssueKind) String() string {
+	switch k {
+	case IssuePermission:
+		return "permission denied"
+	case IssueTimeout:
+		return "timed out"
+	case IssueVanished:
+		return "vanished"
+	default:
+		return "I/O error"
+	}
+}
+
+// Issue is one path whose size is missing from the totals.
+type Issue struct {
+	Path string
+	Kind IssueKind
+	Err  string
+}
+
+// IssueReport is what a finished scan knows about unreadable paths.
+// Issues is capped at 500; Total keeps counting past the cap.
+type IssueReport struct {
+	Issues []Issue
+	Total  int
+}
+
+// HasPermissionIssues reports whether rerunning with more privileges could fill the gaps.
+func (r IssueReport) HasPermissionIssues() bool {
+	for _, issue := range r.Issues {
+		if issue.Kind == IssuePermission {
+			return true
+		}
+	}
+	return false
+}
+
+// issueCollector gathers issues from concurrent workers. A nil collector drops them.
+type issueCollector struct {
+	mu     sync.Mutex
+	issues []Issue
+	total  int
+}
+
+func newIssueCollector() *issueCollector {
+	return &issueCollector{}
+}
+
+// add records err for path; nil errors are ignored.
+func (c *issueCollector) add(path string, err error) {
+	if c == nil || err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total++
+	if len(c.issues) < maxIssues {
+		c.issues = append(c.issues, Issue{Path: path, Kind: classifyError(err), Err: err.Error()})
+	}
+}
+
+func (c *issueCollector) report() IssueReport {
+	if c == nil {
+		return IssueReport{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return IssueReport{Issues: append([]Issue(nil), c.issues...), Total: c.total}
+}
+
+// classifyError maps a filesystem error to an issue kind.
+func classifyError(err error) IssueKind {
+	switch {
+	case errors.Is(err, fs.ErrPermission), errors.Is(err, syscall.EPERM):
+		return IssuePermission
+	case errors.Is(err, fs.ErrNotExist):
+		return IssueVanished
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return IssueTimeout
+	default:
+		return IssueIO
+	}
+}
@@ -0,0 +1,59 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want IssueKind
+	}{
+		{&fs.PathError{Op: "open", Path: "/x", Err: fs.ErrPermission}, IssuePermission},
+		{&fs.PathError{Op: "lstat", Path: "/x", Err: fs.ErrNotExist}, IssueVanished},
+		{context.DeadlineExceeded, IssueTimeout},
+		{fmt.Errorf("read: %w", errors.New("input/output error")), IssueIO},
+	}
+	for _, tt := range tests {
+		if got := classifyError(tt.err); got != tt.want {
+			t.Errorf("classifyError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestIssueCollectorCapsList(t *testing.T) {
+	c := newIssueCollector()
+	var wg sync.WaitGroup
+	for i := range maxIssues + 10 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.add(fmt.Sprintf("/p/%d", i), os.ErrPermission)
+		}(i)
+	}
+	wg.Wait()
+	c.add("/ignored", nil)
+
+	report := c.report()
+	if report.Total != maxIssues+10 {
+		t.Fatalf("expected total %d, got %d", maxIssues+10, report.Total)
+	}
+	if len(report.Issues) != maxIssues {
+		t.Fatalf("expected %d listed issues, got %d", maxIssues, len(report.Issues))
+	}
+	if !report.HasPermissionIssues() {
+		t.Fatal("expected permission issues to be reported")
+	}
+
+	var nilCollector *issueCollector
+	nilCollector.add("/x", os.ErrPermission)
+	if r := nilCollector.report(); r.Total != 0 {
+		t.Fatalf("nil collector should drop issues, got %+v", r)
+	}
+}
@@ -0,0 +1,232 @@
+package scan
+
+import (
+	"io/fs"
+	"os"
+	"os/user"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+// OwnerUsage is the space one user or group holds under a scan root.
+type OwnerUsage struct {
+	ID    uint32
+	Name  string
+	Size  int64
+	Files int64
+}
+
+// OwnershipReport breaks a scan's total down by uid and by gid, largest first.
+type OwnershipReport struct {
+	Users  []OwnerUsage
+	Groups []OwnerUsage
+}
+
+// ownerCount is one uid or gid tally inside ownerTally.
+type ownerCount struct {
+	size  int64
+	files int64
+}
+
+// ownerTally aggregates ownership from concurrent workers. A nil tally drops everything.
+// Workers fill a localOwners per directory and merge it once, so the lock is not per file.
+type ownerTally struct {
+	mu     sync.Mutex
+	users  map[uint32]ownerCount
+	groups map[uint32]ownerCount
+}
+
+func newOwnerTally() *ownerTally {
+	return &ownerTally{users: make(map[uint32]ownerCount), groups: make(map[uint32]ownerCount)}
+}
+
+// localOwners is an unsynchronized tally for one directory listing.
+// Most directories belong to a single owner, so a short slice beats a map.
+type localOwners struct {
+	users  []localOwner
+	groups []localOwner
+}
+
+type localOwner struct {
+	id uint32
+	ownerCount
+}
+
+func addLocalOwner(list []localOwner, id uint32, size, files int64) []localOwner {
+	for i := range list {
+		if list[i].id == id {
+			list[i].size += size
+			list[i].files += files
+			return list
+		}
+	}
+	return append(list, localOwner{id: id, ownerCount: ownerCount{size: size, files: files}})
+}
+
+// add counts info's owner; files is 1 for a file and 0 when size stands in for a whole folder.
+func (l *localOwners) add(info fs.FileInfo, size, files int64) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	l.addIDs(stat.Uid, stat.Gid, size, files)
+}
+
+// addIDs counts size against a uid and gid already known from a stat call.
+func (l *localOwners) addIDs(uid, gid uint32, size, files int64) {
+	l.users = addLocalOwner(l.users, uid, size, files)
+	l.groups = addLocalOwner(l.groups, gid, size, files)
+}
+
+// addPath counts size against the owner of path itself, for folders sized without a walk.
+func (l *localOwners) addPath(path string, size int64) {
+	if info, err := os.Lstat(path); err == nil {
+		l.add(info, size, 0)
+	}
+}
+
+func (t *ownerTally) merge(l *localOwners) {
+	if t == nil || (len(l.users) == 0 && len(l.groups) == 0) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, o := range l.users {
+		c := t.users[o.id]
+		c.size += o.size
+		c.files += o.files
+		t.users[o.id] = c
+	}
+	for _, o := range l.groups {
+		c := t.groups[o.id]
+		c.size += o.size
+		c.files += o.files
+		t.groups[o.id] = c
+	}
+}
+
+// addFile counts a single top-level file and returns its per-uid size for the entry.
+func (t *ownerTally) addFile(info fs.FileInfo, size int64) map[uint32]int64 {
+	var l localOwners
+	l.add(info, size, 1)
+	t.merge(&l)
+	if len(l.users) == 0 {
+		return nil
+	}
+	return map[uint32]int64{l.users[0].id: size}
+}
+
+// addFolder counts a folder sized by du or a cache against the folder's owner.
+func (t *ownerTally) addFolder(path string, size int64) {
+	if t == nil {
+		return
+	}
+	var l localOwners
+	l.addPath(path, size)
+	t.merge(&l)
+}
+
+// mergeTally folds a per-entry tally into the root tally.
+func (t *ownerTally) mergeTally(other *ownerTally) {
+	if t == nil || other == nil {
+		return
+	}
+	other.mu.Lock()
+	var l localOwners
+	for id, c := range other.users {
+		l.users = append(l.users, localOwner{id: id, ownerCount: c})
+	}
+	for id, c := range other.groups {
+		l.groups = append(l.groups, localOwner{id: id, ownerCount: c})
+	}
+	other.mu.Unlock()
+	t.merge(&l)
+}
+
+// userSizes returns bytes per uid, stored on each Entry for owner filtering.
+func (t *ownerTally) userSizes() map[uint32]int64 {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.users) == 0 {
+		return nil
+	}
+	sizes := make(map[uint32]int64, len(t.users))
+	for id, c := range t.users {
+		sizes[id] = c.size
+	}
+	return sizes
+}
+
+func (t *ownerTally) report() OwnershipReport {
+	if t == nil {
+		return OwnershipReport{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return OwnershipReport{
+		Users:  sortedOwners(t.users, UserName),
+		Groups: sortedOwners(t.groups, GroupName),
+	}
+}
+
+func sortedOwners(counts map[uint32]ownerCount, name func(uint32) string) []OwnerUsage {
+	owners := make([]OwnerUsage, 0, len(counts))
+	for id, c := range counts {
+		owners = append(owners, OwnerUsage{ID: id, Name: name(id), Size: c.size, Files: c.files})
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].Size != owners[j].Size {
+			return owners[i].Size > owners[j].Size
+		}
+		return owners[i].ID < owners[j].ID
+	})
+	return owners
+}
+
+var (
+	ownerNameMu    sync.Mutex
+	userNameCache  = make(map[uint32]string)
+	groupNameCache = make(map[uint32]string)
+)
+
+// UserName resolves a uid through os/user, falling back to the number.
+func UserName(uid uint32) string {
+	return cachedOwnerName(userNameCache, uid, func(id string) (string, error) {
+		u, err := user.LookupId(id)
+		if err != nil {
+			return "", err
+		}
+		return u.Username, nil
+	})
+}
+
+// GroupName resolves a gid like UserName.
+func GroupName(gid uint32) string {
+	return cachedOwnerName(groupNameCache, gid, func(id string) (string, error) {
+		g, err := user.LookupGroupId(id)
+		if err != nil {
+			return "", err
+		}
+		return g.Name, nil
+	})
+}
+
+func cachedOwnerName(cache map[uint32]string, id uint32, lookup func(string) (string, error)) string {
+	ownerNameMu.Lock()
+	defer ownerNameMu.Unlock()
+	if name, ok := cache[id]; ok {
+		return name
+	}
+	idStr := strconv.FormatUint(uint64(id), 10)
+	name, err := lookup(idStr)
+	if err != nil || name == "" {
+		name = idStr
+	}
+	cache[id] = name
+	return name
+}
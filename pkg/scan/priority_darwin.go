@@ -0,0 +1,15 @@
+package scan
+
+import "golang.org/x/sys/unix"
+
+const (
+	prioDarwinThread = 3      // PRIO_DARWIN_THREAD: the calling thread; who must be 0.
+	prioDarwinBG     = 0x1000 // PRIO_DARWIN_BG: background CPU band and throttled disk I/O.
+)
+
+// lowerThreadPriority moves the calling thread into the background band, the
+// per-thread form of taskpolicy -b. Errors are ignored; the scan still runs,
+// just at normal priority.
+func lowerThreadPriority() {
+	_ = unix.Setpriority(prioDarwinThread, 0, prioDarwinBG)
+}
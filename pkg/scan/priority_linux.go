@@ -0,0 +1,18 @@
+package scan
+
+import "golang.org/x/sys/unix"
+
+const (
+	ioprioWhoProcess = 1 // IOPRIO_WHO_PROCESS; with a thread ID it targets that thread.
+	ioprioClassIdle  = 3 // IOPRIO_CLASS_IDLE: disk time only when nobody else wants it.
+	ioprioClassShift = 13
+)
+
+// lowerThreadPriority sets the calling thread to nice 19 and the idle I/O
+// class, like nice -n 19 ionice -c 3. Both are per thread on Linux.
+// Errors are ignored; the scan still runs, just at normal priority.
+func lowerThreadPriority() {
+	tid := unix.Gettid()
+	_ = unix.Setpriority(unix.PRIO_PROCESS, tid, 19)
+	_, _, _ = unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), ioprioClassIdle<<ioprioClassShift)
+}
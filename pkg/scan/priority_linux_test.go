@@ -0,0 +1,48 @@
+package scan
+
+import (
+	"runtime"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestLowPriorityWorkerThread(t *testing.T) {
+	var nice, ioClass int
+	newThrottle(LowImpact).background(func() {
+		tid := unix.Gettid()
+		prio, err := unix.Getpriority(unix.PRIO_PROCESS, tid)
+		if err != nil {
+			t.Errorf("getpriority: %v", err)
+		}
+		nice = 20 - prio // The raw syscall returns 20 - nice.
+		r, _, errno := unix.Syscall(unix.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(tid), 0)
+		if errno != 0 {
+			t.Skipf("ioprio_get: %v", errno)
+		}
+		ioClass = int(r) >> ioprioClassShift
+	})
+	if nice != 19 || ioClass != ioprioClassIdle {
+		t.Fatalf("expected nice 19 and the idle I/O class, got nice %d class %d", nice, ioClass)
+	}
+
+	// The lowered thread leaves with the worker, so later goroutines never run on it.
+	for range 8 {
+		done := make(chan int)
+		go func() {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			prio, _ := unix.Getpriority(unix.PRIO_PROCESS, unix.Gettid())
+			done <- 20 - prio
+		}()
+		if got := <-done; got == 19 {
+			t.Fatal("expected a lowered thread not to be reused")
+		}
+	}
+
+	ran := false
+	newThrottle(Profile{FilesPerSec: 1}).background(func() { ran = true })
+	if !ran {
+		t.Fatal("expected background to run fn without LowPriority")
+	}
+}
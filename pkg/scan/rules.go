@@ -0,0 +1,291 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultFoldDirs are dependency, cache and build directories sized in one
+// step; their contents are rarely worth browsing.
+var DefaultFoldDirs = map[string]bool{
+	// VCS.
+	".git": true,
+	".svn": true,
+	".hg":  true,
+
+	// JavaScript/Node.
+	"node_modules":                  true,
+	".npm":                          true,
+	"_npx":                          true,
+	"_cacache":                      true,
+	"_logs":                         true,
+	"_locks":                        true,
+	"_quick":                        true,
+	"_libvips":                      true,
+	"_prebuilds":                    true,
+	"_update-notifier-last-checked": true,
+	".yarn":                         true,
+	".pnpm-store":                   true,
+	".next":                         true,
+	".nuxt":                         true,
+	"bower_components":              true,
+	".vite":                         true,
+	".turbo":                        true,
+	".parcel-cache":                 true,
+	".nx":                           true,
+	".rush":                         true,
+	"tnpm":                          true,
+	".tnpm":                         true,
+	".bun":                          true,
+	".deno":                         true,
+
+	// Python.
+	"__pycache__":   true,
+	".pytest_cache": true,
+	".mypy_cache":   true,
+	".ruff_cache":   true,
+	"venv":          true,
+	".venv":         true,
+	"virtualenv":    true,
+	".tox":          true,
+	"site-packages": true,
+	".eggs":         true,
+	"*.egg-info":    true,
+	".pyenv":        true,
+	".poetry":       true,
+	".pip":          true,
+	".pipx":         true,
+
+	// Ruby/Go/PHP (vendor), Java/Kotlin/Scala/Rust (target).
+	"vendor":        true,
+	".bundle":       true,
+	"gems":          true,
+	".rbenv":        true,
+	"target":        true,
+	".gradle":       true,
+	".m2":           true,
+	".ivy2":         true,
+	"out":           true,
+	"pkg":           true,
+	"composer.phar": true,
+	".composer":     true,
+	".cargo":        true,
+
+	// Build outputs.
+	"build":     true,
+	"dist":      true,
+	".output":   true,
+	"coverage":  true,
+	".coverage": true,
+
+	// IDE.
+	".idea":   true,
+	".vscode": true,
+	".vs":     true,
+	".fleet":  true,
+
+	// Cache directories.
+	".cache":                  true,
+	"__MACOSX":                true,
+	".DS_Store":               true,
+	".Trash":                  true,
+	"Caches":                  true,
+	".Spotlight-V100":         true,
+	".fseventsd":              true,
+	".DocumentRevisions-V100": true,
+	".TemporaryItems":         true,
+	"$RECYCLE.BIN":            true,
+	".temp":                   true,
+	".tmp":                    true,
+	"_temp":                   true,
+	"_tmp":                    true,
+	".Homebrew":               true,
+	".rustup":                 true,
+	".sdkman":                 true,
+	".nvm":                    true,
+
+	// macOS.
+	"Application Scripts":     true,
+	"Saved Application State": true,
+
+	// iCloud.
+	"Mobile Documents": true,
+
+	// Containers.
+	".docker":     true,
+	".containerd": true,
+
+	// Mobile development.
+	"Pods":        true,
+	"DerivedData": true,
+	".build":      true,
+	"xcuserdata":  true,
+	"Carthage":    true,
+	".dart_tool":  true,
+
+	// Web frameworks.
+	".angular":    true,
+	".svelte-kit": true,
+	".astro":      true,
+	".solid":      true,
+
+	// Databases.
+	".mysql":    true,
+	".postgres": true,
+	"mongodb":   true,
+
+	// Other.
+	".terraform": true,
+	".vagrant":   true,
+	"tmp":        true,
+	"temp":       true,
+}
+
+// DefaultSystemDirs are skipped directly under "/": virtual filesystems,
+// mounts and system data that is not the user's to clean.
+var DefaultSystemDirs = map[string]bool{
+	"dev":                     true,
+	"tmp":                     true,
+	"private":                 true,
+	"cores":                   true,
+	"net":                     true,
+	"home":                    true,
+	"System":                  true,
+	"sbin":                    true,
+	"bin":                     true,
+	"etc":                     true,
+	"var":                     true,
+	"opt":                     false,
+	"usr":                     false,
+	"Volumes":                 true,
+	"Network":                 true,
+	".vol":                    true,
+	".Spotlight-V100":         true,
+	".fseventsd":              true,
+	".DocumentRevisions-V100": true,
+	".TemporaryItems":         true,
+	".MobileBackups":          true,
+}
+
+// DefaultSkipDirs are never entered, mostly network and VM mounts that are
+// slow to walk and not stored on this disk.
+var DefaultSkipDirs = map[string]bool{
+	"nfs":         true,
+	"PHD":         true,
+	"Permissions": true,
+
+	// Virtualization/Container mounts (NFS, network filesystems).
+	"OrbStack":        true, // OrbStack NFS mounts
+	"Colima":          true, // Colima VM mounts
+	"Parallels":       true, // Parallels Desktop VMs
+	"VMware Fusion":   true, // VMware Fusion VMs
+	"VirtualBox VMs":  true, // VirtualBox VMs
+	"Rancher Desktop": true, // Rancher Desktop mounts
+	".lima":           true, // Lima VM mounts
+	".colima":         true, // Colima config/mounts
+	".orbstack":       true, // OrbStack config/mounts
+}
+
+// DefaultSkipLargeFileExts are source and data formats left out of the
+// large-file list; they are rarely the files worth removing.
+var DefaultSkipLargeFileExts = map[string]bool{
+	".go":     true,
+	".js":     true,
+	".ts":     true,
+	".tsx":    true,
+	".jsx":    true,
+	".json":   true,
+	".md":     true,
+	".txt":    true,
+	".yml":    true,
+	".yaml":   true,
+	".xml":    true,
+	".html":   true,
+	".css":    true,
+	".scss":   true,
+	".sass":   true,
+	".less":   true,
+	".py":     true,
+	".rb":     true,
+	".java":   true,
+	".kt":     true,
+	".rs":     true,
+	".swift":  true,
+	".m":      true,
+	".mm":     true,
+	".c":      true,
+	".cpp":    true,
+	".h":      true,
+	".hpp":    true,
+	".cs":     true,
+	".sql":    true,
+	".db":     true,
+	".lock":   true,
+	".gradle": true,
+	".mjs":    true,
+	".cjs":    true,
+	".coffee": true,
+	".dart":   true,
+	".svelte": true,
+	".vue":    true,
+	".nim":    true,
+	".hx":     true,
+}
+
+// foldDir reports whether the directory at path should be sized in one step.
+func (r *run) foldDir(name, path string) bool {
+	if r.opts.FoldDirs[name] {
+		return true
+	}
+
+	// Handle npm cache structure.
+	if strings.Contains(path, "/.npm/") || strings.Contains(path, "/.tnpm/") {
+		parent := filepath.Base(filepath.Dir(path))
+		if parent == ".npm" || parent == ".tnpm" || strings.HasPrefix(parent, "_") {
+			return true
+		}
+		if len(name) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipLargeFile reports whether path is left out of the large-file list.
+func (r *run) skipLargeFile(path string) bool {
+	return r.opts.SkipLargeFileExts[strings.ToLower(filepath.Ext(path))]
+}
+
+// excluded reports whether path is one of Options.ExcludePaths.
+func (r *run) excluded(path string) bool {
+	for _, exclude := range r.opts.ExcludePaths {
+		if path == exclude {
+			return true
+		}
+	}
+	return false
+}
+
+// excludesIn reports whether an excluded path sits directly in dir, which makes
+// dir's listing depend on the options and unfit for a shared SizeCache.
+func (r *run) excludesIn(dir string) bool {
+	for _, exclude := range r.opts.ExcludePaths {
+		if filepath.Dir(exclude) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// InFoldedDir reports whether any component of path is in folds, for filtering
+// file lists that come from outside a scan, such as Spotlight results.
+func InFoldedDir(path string, folds map[string]bool) bool {
+	for part := range strings.SplitSeq(path, string(os.PathSeparator)) {
+		if folds[part] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,339 @@
+// Package scan measures directory trees: the largest entries under a root, the
+// largest files, who owns the space and which paths could not be read.
+//
+// A Scanner is configured once with Options and can scan any number of roots.
+// It keeps no package-level state, so independent scanners may run side by side.
+// mo analyze is one consumer; other tools can import it directly.
+package scan
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SizeMode selects what a file's size means.
+type SizeMode int
+
+const (
+	// SizeAllocated counts blocks on disk, so sparse and cloud placeholder files
+	// count only what they occupy. It matches du.
+	SizeAllocated SizeMode = iota
+	// SizeApparent counts file lengths as ls reports them.
+	SizeApparent
+)
+
+// Entry is one direct child of a scanned root.
+type Entry struct {
+	Name       string
+	Path       string
+	Size       int64
+	IsDir      bool
+	LastAccess time.Time
+	OwnerSizes map[uint32]int64 // Bytes per uid, for filtering by owner
+}
+
+// File is one of the largest files found under a root.
+type File struct {
+	Name    string
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Result is everything known about one root after a scan.
+type Result struct {
+	Root       string
+	Entries    []Entry // Largest first
+	LargeFiles []File  // Largest first
+	Recent     []File  // Files modified since Options.RecentSince, largest first
+	TotalSize  int64
+	TotalFiles int64
+	Issues     IssueReport
+	Owners     OwnershipReport
+	Dropped    int64 // Entries and large-file candidates abandoned on cancellation; their bytes are in TotalSize.
+	Device     Device
+	Strategy   Strategy // What the scan ran with; never StrategyAuto.
+}
+
+// Progress is a snapshot of a running scan.
+type Progress struct {
+	Root        string
+	Files       int64
+	Dirs        int64
+	Bytes       int64
+	CurrentPath string // A recently visited directory, for display
+	Done        bool   // Set on the final report for a root
+	Device      Device
+	Strategy    Strategy
+}
+
+// Options configures a Scanner. The zero value scans at full speed with the
+// default rules and keeps every entry.
+type Options struct {
+	Roots []string // Directories Scan visits, in order.
+
+	// Worker limits; zero picks a default from the CPU count.
+	Workers     int // Top-level directories sized in parallel.
+	DirWorkers  int // Nested directories walked in parallel.
+	FoldWorkers int // Folded directories sized at once.
+
+	// Profile adds rate limits and pauses on top of the worker limits.
+	Profile Profile
+
+	// Strategy caps the worker limits to suit the storage. The zero value detects
+	// the root's device; StrategyParallel leaves the limits as they are.
+	Strategy Strategy
+
+	// Rules. A nil map uses the default table; an empty map disables the rule.
+	FoldDirs          map[string]bool // Directory names sized in one step, without listing their contents.
+	SkipDirs          map[string]bool // Directory names skipped among a root's children.
+	SkipRootDirs      map[string]bool // Directory names skipped directly under "/".
+	SkipLargeFileExts map[string]bool // Extensions left out of LargeFiles.
+	ExcludePaths      []string        // Absolute paths left out entirely.
+
+	TopEntries int // Largest entries kept per root; 0 keeps all.
+	TopFiles   int // Largest files kept per root; 0 disables large-file tracking.
+
+	SizeMode       SizeMode
+	FollowSymlinks bool // Size what links point to instead of the links themselves.
+	OneFilesystem  bool // Do not cross into other mounted filesystems.
+
+	// KnownSize may return a precomputed size for a top-level directory, such as
+	// one from a cache, to avoid walking it.
+	KnownSize func(path string) (int64, bool)
+
+	// FileIndex, when set, receives every file at or above its MinSize, not
+	// only the TopFiles largest.
+	FileIndex *FileIndex
+
+	// SizeCache keeps per-directory results for folded directories and Size
+	// across scans; nil sizes every directory afresh.
+	SizeCache *SizeCache
+
+	// RecentSince, when set, lists files modified at or after it in
+	// Result.Recent, folded directories included. The SizeCache is not used for
+	// such scans, as it would hide files rewritten in place.
+	RecentSince time.Time
+
+	// OnProgress is called from a single goroutine every ProgressInterval while a
+	// root is scanned, and once more with Done set before ScanRoot returns.
+	OnProgress       func(Progress)
+	ProgressInterval time.Duration // Default 100ms.
+}
+
+const (
+	defaultProgressInterval = 100 * time.Millisecond
+	largeFileWarmupMinSize  = 1 << 20 // Files below this are not tracked until the heap fills.
+	currentPathEvery        = 100     // Files between CurrentPath updates.
+	maxRecentFiles          = 1 << 18 // Recent files kept per root; the smallest go first.
+	walkTimeout             = 5 * time.Minute
+
+	minWorkers    = 16
+	maxWorkers    = 64
+	cpuMultiplier = 4
+	maxDirWorkers = 32
+)
+
+// Scanner walks roots according to its Options.
+type Scanner struct {
+	opts Options
+
+	mu     sync.Mutex
+	active *run // Scan in progress, for Progress
+}
+
+// New returns a Scanner for opts.
+func New(opts Options) *Scanner {
+	if opts.FoldDirs == nil {
+		opts.FoldDirs = DefaultFoldDirs
+	}
+	if opts.SkipDirs == nil {
+		opts.SkipDirs = DefaultSkipDirs
+	}
+	if opts.SkipRootDirs == nil {
+		opts.SkipRootDirs = DefaultSystemDirs
+	}
+	if opts.SkipLargeFileExts == nil {
+		opts.SkipLargeFileExts = DefaultSkipLargeFileExts
+	}
+	if opts.ProgressInterval <= 0 {
+		opts.ProgressInterval = defaultProgressInterval
+	}
+	return &Scanner{opts: opts}
+}
+
+// Scan scans every root in Options.Roots and stops at the first root that
+// cannot be read.
+func (s *Scanner) Scan(ctx context.Context) ([]Result, error) {
+	results := make([]Result, 0, len(s.opts.Roots))
+	for _, root := range s.opts.Roots {
+		result, err := s.ScanRoot(ctx, root)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ScanRoot scans one directory. It fails only when root itself cannot be
+// listed; everything else that cannot be read is listed in Result.Issues.
+func (s *Scanner) ScanRoot(ctx context.Context, root string) (Result, error) {
+	r := s.newRun(ctx, root)
+	s.mu.Lock()
+	s.active = r
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.active = nil
+		s.mu.Unlock()
+	}()
+
+	stop := r.reportProgress(s.opts.OnProgress, s.opts.ProgressInterval)
+	var result Result
+	var err error
+	r.throttle.background(func() { result, err = r.scanRoot() })
+	stop()
+	return result, err
+}
+
+// Progress returns the counters of the scan in progress, or a zero Progress
+// between scans.
+func (s *Scanner) Progress() Progress {
+	s.mu.Lock()
+	r := s.active
+	s.mu.Unlock()
+	if r == nil {
+		return Progress{}
+	}
+	return r.progress()
+}
+
+// run is the state of one ScanRoot call.
+type run struct {
+	opts     Options
+	ctx      context.Context
+	cancel   context.CancelFunc
+	root     string
+	rootDev  uint64
+	device   Device
+	strategy Strategy
+	throttle *throttle
+	issues   *issueCollector
+	owners   *ownerTally
+	visited  sync.Map // dev/inode of directories reached through symlinks
+
+	files, dirs, bytes atomic.Int64
+	dropped            atomic.Int64
+	currentPath        atomic.Value
+
+	indexMu sync.Mutex
+	indexed []File // Files for Options.FileIndex
+
+	recentMu sync.Mutex
+	recent   []File // Files for Result.Recent
+
+	dirSem       chan struct{}
+	foldSem      chan struct{} // Limits folded directories sized at once.
+	foldQueueSem chan struct{} // Limits goroutines waiting to size one.
+}
+
+func (s *Scanner) newRun(ctx context.Context, root string) *run {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &run{
+		opts:     s.opts,
+		ctx:      ctx,
+		cancel:   cancel,
+		root:     root,
+		device:   DetectDevice(root),
+		strategy: s.opts.Strategy,
+		issues:   newIssueCollector(),
+		owners:   newOwnerTally(),
+	}
+	if r.strategy == StrategyAuto {
+		r.strategy = StrategyFor(r.device)
+	}
+	r.throttle = newThrottle(s.opts.Profile.capped(r.strategy.limits()))
+	r.currentPath.Store("")
+	if info, err := os.Stat(root); err == nil {
+		r.rootDev, _ = deviceOf(info)
+	}
+
+	cpus := runtime.NumCPU()
+	r.dirSem = make(chan struct{}, r.throttle.dirWorkers(orDefault(s.opts.DirWorkers, min(cpus*2, maxDirWorkers))))
+	foldWorkers := r.throttle.foldWorkers(orDefault(s.opts.FoldWorkers, min(4, cpus)))
+	r.foldSem = make(chan struct{}, foldWorkers)
+	r.foldQueueSem = make(chan struct{}, foldWorkers*2)
+	return r
+}
+
+func orDefault(n, def int) int {
+	if n > 0 {
+		return n
+	}
+	return def
+}
+
+func (r *run) progress() Progress {
+	current, _ := r.currentPath.Load().(string)
+	return Progress{
+		Root:        r.root,
+		Files:       r.files.Load(),
+		Dirs:        r.dirs.Load(),
+		Bytes:       r.bytes.Load(),
+		CurrentPath: current,
+		Device:      r.device,
+		Strategy:    r.strategy,
+	}
+}
+
+// reportProgress calls fn on a ticker until the returned stop is called, which
+// sends the final report.
+func (r *run) reportProgress(fn func(Progress), interval time.Duration) (stop func()) {
+	if fn == nil {
+		return r.cancel
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fn(r.progress())
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+		r.cancel()
+		final := r.progress()
+		final.Done = true
+		fn(final)
+	}
+}
+
+// send hands item to a collector, waiting for room rather than dropping it.
+// Only cancellation gives up, and that is counted in Result.Dropped.
+func send[T any](r *run, ch chan<- T, item T) {
+	select {
+	case ch <- item:
+		return
+	default:
+	}
+	select {
+	case ch <- item:
+	case <-r.ctx.Done():
+		r.dropped.Add(1)
+	}
+}
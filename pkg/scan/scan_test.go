@@ -0,0 +1,258 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestScanRootOptions(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "big", "a.bin"), 3000)
+	writeFileWithSize(t, filepath.Join(root, "small", "b.bin"), 1000)
+	writeFileWithSize(t, filepath.Join(root, "skipped", "c.bin"), 5000)
+	writeFileWithSize(t, filepath.Join(root, "top.bin"), 2000)
+
+	var reports []Progress
+	s := New(Options{
+		TopEntries: 2,
+		TopFiles:   10,
+		SizeMode:   SizeApparent,
+		SkipDirs:   map[string]bool{"skipped": true},
+		OnProgress: func(p Progress) { reports = append(reports, p) },
+	})
+	result, err := s.ScanRoot(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+
+	if result.TotalSize != 6000 {
+		t.Fatalf("expected 6000 bytes without the skipped dir, got %d", result.TotalSize)
+	}
+	if len(result.Entries) != 2 || result.Entries[0].Name != "big" || result.Entries[1].Name != "top.bin" {
+		t.Fatalf("expected the two largest entries, got %+v", result.Entries)
+	}
+	if len(result.LargeFiles) != 0 {
+		t.Fatalf("files below the warm-up size should not be tracked, got %+v", result.LargeFiles)
+	}
+	if result.TotalFiles != 3 {
+		t.Fatalf("expected 3 files, got %d", result.TotalFiles)
+	}
+
+	if len(reports) == 0 || !reports[len(reports)-1].Done {
+		t.Fatal("expected a final progress report")
+	}
+	if last := reports[len(reports)-1]; last.Files != 3 || last.Bytes != 6000 {
+		t.Fatalf("final report should match the result, got %+v", last)
+	}
+	if p := s.Progress(); p != (Progress{}) {
+		t.Fatalf("expected no progress between scans, got %+v", p)
+	}
+}
+
+func TestScanFollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeFileWithSize(t, filepath.Join(outside, "data.bin"), 4000)
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	// A link back to the root must not loop.
+	if err := os.Symlink(root, filepath.Join(outside, "loop")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	plain, err := New(Options{SizeMode: SizeApparent}).ScanRoot(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+	if plain.TotalSize >= 4000 {
+		t.Fatalf("links should count only themselves by default, got %d", plain.TotalSize)
+	}
+
+	followed, err := New(Options{SizeMode: SizeApparent, FollowSymlinks: true}).ScanRoot(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+	if followed.TotalSize != 4000 {
+		t.Fatalf("expected the link target to be counted once, got %d", followed.TotalSize)
+	}
+	if len(followed.Entries) != 1 || !followed.Entries[0].IsDir || followed.Entries[0].Name != "link →" {
+		t.Fatalf("expected the link as a directory entry, got %+v", followed.Entries)
+	}
+}
+
+func TestScanKnownSizeAndCancel(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "cached", "a.bin"), 100)
+
+	s := New(Options{
+		Roots: []string{root},
+		KnownSize: func(path string) (int64, bool) {
+			return 1 << 20, filepath.Base(path) == "cached"
+		},
+	})
+	results, err := s.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(results) != 1 || results[0].TotalSize != 1<<20 {
+		t.Fatalf("expected the known size to be used, got %+v", results)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.ScanRoot(ctx, root); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestScanKeepsEveryEntry(t *testing.T) {
+	root := t.TempDir()
+	const count = 600
+	for i := range count {
+		writeFileWithSize(t, filepath.Join(root, fmt.Sprintf("dir-%03d", i), "data.bin"), i+1)
+	}
+
+	result, err := New(Options{SizeMode: SizeApparent, Workers: 64}).ScanRoot(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+	if len(result.Entries) != count || result.Dropped != 0 {
+		t.Fatalf("expected %d entries and nothing dropped, got %d entries, %d dropped", count, len(result.Entries), result.Dropped)
+	}
+	var sum int64
+	for _, entry := range result.Entries {
+		sum += entry.Size
+	}
+	if sum != result.TotalSize {
+		t.Fatalf("entries add up to %d, total is %d", sum, result.TotalSize)
+	}
+}
+
+func TestSendCountsDropsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := New(Options{}).newRun(ctx, t.TempDir())
+	cancel()
+
+	ch := make(chan Entry)
+	send(r, ch, Entry{Name: "lost"})
+	if got := r.dropped.Load(); got != 1 {
+		t.Fatalf("expected one dropped item, got %d", got)
+	}
+}
+
+func TestScanSequentialStrategy(t *testing.T) {
+	root := t.TempDir()
+	for i := range 20 {
+		writeFileWithSize(t, filepath.Join(root, fmt.Sprintf("dir-%02d", i), "nested", "data.bin"), 100*(i+1))
+	}
+
+	auto, err := New(Options{SizeMode: SizeApparent}).ScanRoot(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+	if auto.Strategy == StrategyAuto {
+		t.Fatal("expected the detected strategy to be reported")
+	}
+
+	sequential, err := New(Options{SizeMode: SizeApparent, Strategy: StrategySequential}).ScanRoot(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+	if sequential.Strategy != StrategySequential {
+		t.Fatalf("expected the forced strategy, got %v", sequential.Strategy)
+	}
+	if sequential.TotalSize != auto.TotalSize || sequential.TotalFiles != auto.TotalFiles {
+		t.Fatalf("sequential scan found %d bytes in %d files, auto %d in %d",
+			sequential.TotalSize, sequential.TotalFiles, auto.TotalSize, auto.TotalFiles)
+	}
+}
+
+func TestSortByInode(t *testing.T) {
+	root := t.TempDir()
+	for i := range 10 {
+		writeFileWithSize(t, filepath.Join(root, fmt.Sprintf("f-%02d", 9-i)), 10)
+	}
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+
+	var inodes []uint64
+	for _, entry := range sortByInode(root, entries) {
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatalf("Info: %v", err)
+		}
+		if entry.IsDir() != (entry.Name() == "sub") {
+			t.Fatalf("%s kept the wrong type", entry.Name())
+		}
+		inodes = append(inodes, info.Sys().(*syscall.Stat_t).Ino)
+	}
+	if len(inodes) != 11 || !slices.IsSorted(inodes) {
+		t.Fatalf("expected all entries in inode order, got %v", inodes)
+	}
+}
+
+func TestScanRecentFiles(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	for _, f := range []struct {
+		path  string
+		size  int
+		stale bool
+	}{
+		{"top.log", 300, false},
+		{"old.log", 900, true},
+		{"app/logs/run.log", 2000, false},
+		{"app/logs/archive.log", 5000, true},
+		{"web/node_modules/dep/cache.bin", 700, false},
+		{"web/node_modules/dep/stable.bin", 800, true},
+	} {
+		path := filepath.Join(root, f.path)
+		writeFileWithSize(t, path, f.size)
+		if f.stale {
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatalf("chtimes: %v", err)
+			}
+		}
+	}
+
+	// A warm cache must not hide the folded directory's files.
+	for _, dir := range []string{"web/node_modules/dep", "web/node_modules"} {
+		if err := os.Chtimes(filepath.Join(root, dir), old, old); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	cache := NewSizeCache()
+	if _, err := New(Options{SizeMode: SizeApparent, SizeCache: cache}).ScanRoot(context.Background(), root); err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+	result, err := New(Options{
+		SizeMode:    SizeApparent,
+		SizeCache:   cache,
+		RecentSince: time.Now().Add(-time.Hour),
+	}).ScanRoot(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ScanRoot: %v", err)
+	}
+
+	var got []string
+	for _, f := range result.Recent {
+		rel, _ := filepath.Rel(root, f.Path)
+		got = append(got, fmt.Sprintf("%s:%d", rel, f.Size))
+	}
+	want := []string{"app/logs/run.log:2000", "web/node_modules/dep/cache.bin:700", "top.log:300"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected recent files largest first, got %v, want %v", got, want)
+	}
+}
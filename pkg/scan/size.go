@@ -0,0 +1,311 @@
+package scan
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	maxCachedDirs = 1 << 20         // Past this the cache starts over rather than grow without bound.
+	racyWindow    = 2 * time.Second // Directories changed this recently are not cached.
+)
+
+// Size returns the size of the directory at path, leaving out Options.ExcludePaths.
+// Only Profile, ExcludePaths, SizeMode, FollowSymlinks, OneFilesystem and
+// SizeCache apply.
+func Size(ctx context.Context, path string, opts Options) (int64, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, err
+	}
+	opts.FileIndex = nil // Size never merges into an index.
+	r := New(opts).newRun(ctx, path)
+	defer r.cancel()
+
+	var size int64
+	r.throttle.background(func() { size = r.sizeTree(path, nil) })
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// SizeCache remembers what each directory directly contains, keyed by device and
+// inode and checked against the directory's mtime, so sizing a tree again reads
+// only the directories that changed. A file rewritten in place keeps its cached
+// size until an entry in its directory is added, removed or renamed.
+// It is safe for concurrent use and may be shared between scanners.
+type SizeCache struct {
+	mu   sync.Mutex
+	dirs map[fileID]cachedDir
+}
+
+// NewSizeCache returns an empty cache.
+func NewSizeCache() *SizeCache {
+	return &SizeCache{dirs: make(map[fileID]cachedDir)}
+}
+
+// cachedDir is one directory's own files and the names of its subdirectories.
+// Files with several hard links are kept apart so each sizing counts them once.
+// Files big enough for a FileIndex are kept by name, so cached directories
+// still feed the index.
+type cachedDir struct {
+	mtime    int64
+	mode     SizeMode
+	bytes    int64
+	files    int64
+	owners   localOwners
+	links    []linkedFile
+	subdirs  []string
+	indexMin int64 // MinSize big was collected for, -1 if it was not
+	big      []bigFile
+}
+
+// bigFile is a file at or above a FileIndex's MinSize.
+type bigFile struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+// linkedFile is a file with more than one hard link.
+type linkedFile struct {
+	id       fileID
+	size     int64
+	uid, gid uint32
+}
+
+// get returns the cached listing if it is current and, when indexMin is not
+// -1, holds every file of at least indexMin bytes.
+func (c *SizeCache) get(id fileID, mtime int64, mode SizeMode, indexMin int64) (cachedDir, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dir, ok := c.dirs[id]
+	if indexMin >= 0 && (dir.indexMin < 0 || dir.indexMin > indexMin) {
+		return dir, false
+	}
+	return dir, ok && dir.mtime == mtime && dir.mode == mode
+}
+
+func (c *SizeCache) put(id fileID, dir cachedDir) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.dirs) >= maxCachedDirs {
+		clear(c.dirs)
+	}
+	c.dirs[id] = dir
+}
+
+// treeSizer is the state of one sizeTree call.
+type treeSizer struct {
+	r        *run
+	ctx      context.Context
+	cache    *SizeCache
+	owners   *ownerTally
+	indexMin int64 // Options.FileIndex MinSize, -1 without an index
+	sem      chan struct{}
+	wg       sync.WaitGroup
+	total    atomic.Int64
+	links    sync.Map // fileID of hard-linked files already counted
+}
+
+// sizeTree totals a directory in-process without tracking large files or
+// listing entries, counting hard-linked files once as du does. Files big enough
+// for Options.FileIndex are still indexed. It gives up after walkTimeout on its
+// own, so one huge folded directory cannot hold up the scan.
+func (r *run) sizeTree(root string, owners *ownerTally) int64 {
+	ctx, cancel := context.WithTimeout(r.ctx, walkTimeout)
+	defer cancel()
+
+	s := &treeSizer{
+		r:        r,
+		ctx:      ctx,
+		cache:    r.opts.SizeCache,
+		owners:   owners,
+		indexMin: -1,
+		sem:      make(chan struct{}, r.throttle.dirWorkers(min(runtime.NumCPU()*4, 64))),
+	}
+	if r.opts.FileIndex != nil {
+		s.indexMin = r.opts.FileIndex.MinSize
+	}
+	if r.opts.FollowSymlinks {
+		s.cache = nil // Listings would depend on where links point.
+	}
+	if !r.opts.RecentSince.IsZero() {
+		s.cache = nil // Cached directories are not listed, so their files go unseen.
+	}
+	s.walk(root)
+	s.wg.Wait()
+	return s.total.Load()
+}
+
+func (s *treeSizer) walk(dirPath string) {
+	r := s.r
+	if err := s.ctx.Err(); err != nil {
+		if r.ctx.Err() == nil {
+			r.issues.add(dirPath, err)
+		}
+		return
+	}
+
+	if r.files.Load()%currentPathEvery == 0 {
+		r.currentPath.Store(dirPath)
+	}
+
+	var id fileID
+	var mtime int64
+	cacheable := s.cache != nil && !r.excludesIn(dirPath)
+	if cacheable {
+		info, err := os.Lstat(dirPath)
+		if err != nil {
+			r.issues.add(dirPath, err)
+			return
+		}
+		id, mtime, cacheable = dirKey(info)
+		if dir, ok := s.cache.get(id, mtime, r.opts.SizeMode, s.indexMin); ok {
+			s.count(dirPath, dir)
+			return
+		}
+	}
+
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		r.issues.add(dirPath, err)
+		return
+	}
+
+	dir := cachedDir{mtime: mtime, mode: r.opts.SizeMode, indexMin: s.indexMin}
+	complete := true
+	for _, entry := range entries {
+		fullPath := filepath.Join(dirPath, entry.Name())
+		if r.excluded(fullPath) {
+			continue
+		}
+
+		isDir := entry.IsDir()
+		var target fs.FileInfo
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if target = r.follow(fullPath); target != nil {
+				isDir = target.IsDir()
+			}
+		}
+		if isDir {
+			dir.subdirs = append(dir.subdirs, entry.Name())
+			continue
+		}
+
+		info := target
+		if info == nil {
+			if info, err = entry.Info(); err != nil {
+				r.issues.add(fullPath, err)
+				complete = false
+				continue
+			}
+		}
+		size := r.fileSize(info)
+		if info.Mode().IsRegular() {
+			r.offerRecent(entry.Name(), fullPath, size, info.ModTime())
+			if s.indexMin >= 0 && size >= s.indexMin {
+				dir.big = append(dir.big, bigFile{name: entry.Name(), size: size, modTime: info.ModTime()})
+			}
+		}
+		if link, ok := hardLink(info, size); ok {
+			dir.links = append(dir.links, link)
+			continue
+		}
+		dir.bytes += size
+		dir.files++
+		dir.owners.add(info, size, 1)
+	}
+
+	if cacheable && complete {
+		s.cache.put(id, dir)
+	}
+	files, bytes := s.count(dirPath, dir)
+	r.throttle.wait(files, bytes)
+}
+
+// count adds a directory's own files to the totals and descends into its
+// subdirectories. It returns what was added.
+func (s *treeSizer) count(dirPath string, dir cachedDir) (files, bytes int64) {
+	r := s.r
+	files, bytes = dir.files, dir.bytes
+	s.owners.merge(&dir.owners)
+
+	var linked localOwners
+	for _, link := range dir.links {
+		if _, seen := s.links.LoadOrStore(link.id, struct{}{}); seen {
+			continue
+		}
+		files++
+		bytes += link.size
+		linked.addIDs(link.uid, link.gid, link.size, 1)
+	}
+	s.owners.merge(&linked)
+
+	s.total.Add(bytes)
+	r.bytes.Add(bytes)
+	r.files.Add(files)
+
+	for _, f := range dir.big {
+		if f.size >= s.indexMin {
+			r.offerLargeFile(nil, nil, f.name, filepath.Join(dirPath, f.name), f.size, f.modTime)
+		}
+	}
+
+	for _, name := range dir.subdirs {
+		s.descend(filepath.Join(dirPath, name))
+	}
+	return files, bytes
+}
+
+func (s *treeSizer) descend(path string) {
+	if s.r.skipDir(path) {
+		return
+	}
+	s.r.dirs.Add(1)
+
+	select {
+	case s.sem <- struct{}{}:
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() { <-s.sem }()
+			s.r.throttle.lowerPriority()
+			s.walk(path)
+		}()
+	default:
+		// Fallback to synchronous traversal to avoid semaphore deadlock under high fan-out.
+		s.walk(path)
+	}
+}
+
+// dirKey identifies a directory in the cache. Directories changed within
+// racyWindow are left out, since a change later in the same mtime tick would
+// go unnoticed.
+func dirKey(info fs.FileInfo) (fileID, int64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || time.Since(info.ModTime()) < racyWindow {
+		return fileID{}, 0, false
+	}
+	return fileID{dev: uint64(stat.Dev), ino: stat.Ino}, info.ModTime().UnixNano(), true
+}
+
+func hardLink(info fs.FileInfo, size int64) (linkedFile, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || stat.Nlink <= 1 {
+		return linkedFile{}, false
+	}
+	return linkedFile{
+		id:   fileID{dev: uint64(stat.Dev), ino: stat.Ino},
+		size: size,
+		uid:  stat.Uid,
+		gid:  stat.Gid,
+	}, true
+}
@@ -0,0 +1,160 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFileWithSize(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", path, err)
+	}
+	content := make([]byte, size)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestSizeWithExclude(t *testing.T) {
+	base := t.TempDir()
+	homeFile := filepath.Join(base, "fileA")
+	libFile := filepath.Join(base, "Library", "fileB")
+	projectLibFile := filepath.Join(base, "Projects", "Library", "fileC")
+
+	writeFileWithSize(t, homeFile, 100)
+	writeFileWithSize(t, libFile, 200)
+	writeFileWithSize(t, projectLibFile, 300)
+
+	total, err := Size(context.Background(), base, Options{SizeMode: SizeApparent})
+	if err != nil {
+		t.Fatalf("Size (no exclude) error: %v", err)
+	}
+	if total != 600 {
+		t.Fatalf("expected total 600 bytes, got %d", total)
+	}
+
+	excluding, err := Size(context.Background(), base, Options{
+		SizeMode:     SizeApparent,
+		ExcludePaths: []string{filepath.Join(base, "Library")},
+	})
+	if err != nil {
+		t.Fatalf("Size (exclude Library) error: %v", err)
+	}
+	if excluding != 400 {
+		t.Fatalf("expected 400 bytes when excluding top-level Library, got %d", excluding)
+	}
+
+	if _, err := Size(context.Background(), filepath.Join(base, "missing"), Options{}); err == nil {
+		t.Fatal("expected an error for a missing path")
+	}
+}
+
+func TestSizeTreeHighFanoutCompletes(t *testing.T) {
+	root := t.TempDir()
+
+	// Reproduce high fan-out nested directory pattern that previously risked semaphore deadlock.
+	const fanout = 256
+	for i := 0; i < fanout; i++ {
+		nested := filepath.Join(root, fmt.Sprintf("dir-%03d", i), "nested")
+		if err := os.MkdirAll(nested, 0o755); err != nil {
+			t.Fatalf("create nested dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(nested, "data.bin"), []byte("x"), 0o644); err != nil {
+			t.Fatalf("write nested file: %v", err)
+		}
+	}
+
+	r := New(Options{}).newRun(context.Background(), root)
+	defer r.cancel()
+
+	done := make(chan int64, 1)
+	go func() {
+		done <- r.sizeTree(root, nil)
+	}()
+
+	select {
+	case total := <-done:
+		if total <= 0 {
+			t.Fatalf("expected positive total size, got %d", total)
+		}
+		if got := r.files.Load(); got < fanout {
+			t.Fatalf("expected at least %d files scanned, got %d", fanout, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("sizeTree did not complete under high fan-out")
+	}
+}
+
+func TestSizeCacheReusesUnchangedDirs(t *testing.T) {
+	base := t.TempDir()
+	grown := filepath.Join(base, "pkg", "grown.bin")
+	writeFileWithSize(t, grown, 100)
+	writeFileWithSize(t, filepath.Join(base, "pkg", "lib", "other.bin"), 200)
+	// Fresh directories are not cached, so age them past the racy window.
+	old := time.Now().Add(-time.Hour)
+	for _, dir := range []string{base, filepath.Join(base, "pkg"), filepath.Join(base, "pkg", "lib")} {
+		if err := os.Chtimes(dir, old, old); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	opts := Options{SizeMode: SizeApparent, SizeCache: NewSizeCache()}
+	size := func(opts Options) int64 {
+		t.Helper()
+		total, err := Size(context.Background(), base, opts)
+		if err != nil {
+			t.Fatalf("Size: %v", err)
+		}
+		return total
+	}
+	if got := size(opts); got != 300 {
+		t.Fatalf("expected 300 bytes, got %d", got)
+	}
+
+	// Rewriting a file in place leaves its directory's mtime alone.
+	writeFileWithSize(t, grown, 1000)
+	if got := size(opts); got != 300 {
+		t.Fatalf("expected the cached 300 bytes, got %d", got)
+	}
+	if got := size(Options{SizeMode: SizeApparent}); got != 1200 {
+		t.Fatalf("expected 1200 bytes without the cache, got %d", got)
+	}
+
+	// Adding an entry changes the mtime, so the directory is read again.
+	writeFileWithSize(t, filepath.Join(base, "pkg", "new.bin"), 50)
+	if got := size(opts); got != 1250 {
+		t.Fatalf("expected 1250 bytes after a change, got %d", got)
+	}
+
+	opts.ExcludePaths = []string{filepath.Join(base, "pkg", "lib")}
+	if got := size(opts); got != 1050 {
+		t.Fatalf("expected the excluded dir left out despite the cache, got %d", got)
+	}
+}
+
+func TestSizeCountsHardLinksOnce(t *testing.T) {
+	base := t.TempDir()
+	original := filepath.Join(base, "a", "data.bin")
+	writeFileWithSize(t, original, 4000)
+	if err := os.MkdirAll(filepath.Join(base, "b"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.Link(original, filepath.Join(base, "b", "data.bin")); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+
+	for _, cache := range []*SizeCache{nil, NewSizeCache()} {
+		total, err := Size(context.Background(), base, Options{SizeMode: SizeApparent, SizeCache: cache})
+		if err != nil {
+			t.Fatalf("Size: %v", err)
+		}
+		if total != 4000 {
+			t.Fatalf("expected the linked file counted once, got %d", total)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package scan
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// lastAccess returns the atime recorded in info, or zero when unavailable.
+func lastAccess(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec)
+}
@@ -0,0 +1,198 @@
+package scan
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/load"
+)
+
+// Profile bounds how hard a scan may push the machine. Zero values mean no limit.
+type Profile struct {
+	Workers        int     // Top-level directory workers.
+	DirWorkers     int     // Nested directory workers and fast-walk concurrency.
+	FoldWorkers    int     // Folded directories sized at once.
+	FilesPerSec    int64   // Files stat'ed per second.
+	BytesPerSec    int64   // Bytes accounted per second.
+	LowPriority    bool    // Run workers at background CPU and I/O priority.
+	PauseOnBattery bool    // Wait while running on battery.
+	MaxLoadPerCPU  float64 // Wait while the 1-minute load average per CPU is above this.
+}
+
+// FullSpeed is the interactive default.
+var FullSpeed = Profile{}
+
+// LowImpact suits background work: few workers, rate limits, and pauses on
+// battery or under load.
+var LowImpact = Profile{
+	Workers:        2,
+	DirWorkers:     2,
+	FoldWorkers:    1,
+	FilesPerSec:    5000,
+	BytesPerSec:    200 << 20,
+	LowPriority:    true,
+	PauseOnBattery: true,
+	MaxLoadPerCPU:  0.8,
+}
+
+const (
+	throttlePauseCheck = 5 * time.Second // How often battery and load are re-read.
+	throttleMaxPause   = 2 * time.Minute // Give up waiting and continue slowly.
+)
+
+// throttle rate-limits one scan and pauses it when the machine is busy.
+// A nil throttle never waits.
+type throttle struct {
+	profile   Profile
+	mu        sync.Mutex
+	start     time.Time
+	files     int64
+	bytes     int64
+	lastCheck time.Time
+}
+
+// newThrottle returns nil for profiles without limits so callers skip the bookkeeping.
+func newThrottle(profile Profile) *throttle {
+	if profile == FullSpeed {
+		return nil
+	}
+	return &throttle{profile: profile, start: time.Now()}
+}
+
+// capped lowers p's worker limits to those set in caps.
+func (p Profile) capped(caps Profile) Profile {
+	lower := func(limit, to int) int {
+		if to > 0 && (limit <= 0 || to < limit) {
+			return to
+		}
+		return limit
+	}
+	p.Workers = lower(p.Workers, caps.Workers)
+	p.DirWorkers = lower(p.DirWorkers, caps.DirWorkers)
+	p.FoldWorkers = lower(p.FoldWorkers, caps.FoldWorkers)
+	return p
+}
+
+// limit caps a default worker count by a profile limit.
+func (t *throttle) limit(def int, get func(Profile) int) int {
+	if t == nil {
+		return def
+	}
+	if n := get(t.profile); n > 0 && n < def {
+		return n
+	}
+	return def
+}
+
+func (t *throttle) workers(def int) int {
+	return t.limit(def, func(p Profile) int { return p.Workers })
+}
+
+func (t *throttle) dirWorkers(def int) int {
+	return t.limit(def, func(p Profile) int { return p.DirWorkers })
+}
+
+func (t *throttle) foldWorkers(def int) int {
+	return t.limit(def, func(p Profile) int { return p.FoldWorkers })
+}
+
+// background runs fn on a goroutine at background priority and waits for it.
+// Without LowPriority it just calls fn.
+func (t *throttle) background(fn func()) {
+	if t == nil || !t.profile.LowPriority {
+		fn()
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t.lowerPriority()
+		fn()
+	}()
+	<-done
+}
+
+// lowerPriority pins the calling goroutine to its OS thread and drops that
+// thread to background CPU and I/O priority. Worker goroutines call it first.
+// The thread is never unlocked, so the runtime discards it when the goroutine
+// exits instead of handing it, still throttled, to unrelated goroutines.
+func (t *throttle) lowerPriority() {
+	if t == nil || !t.profile.LowPriority {
+		return
+	}
+	runtime.LockOSThread()
+	lowerThreadPriority()
+}
+
+// wait accounts for work just done and sleeps long enough to stay under the rate limits.
+func (t *throttle) wait(files, bytes int64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.files += files
+	t.bytes += bytes
+	var due time.Duration
+	if t.profile.FilesPerSec > 0 {
+		due = max(due, time.Duration(float64(t.files)/float64(t.profile.FilesPerSec)*float64(time.Second)))
+	}
+	if t.profile.BytesPerSec > 0 {
+		due = max(due, time.Duration(float64(t.bytes)/float64(t.profile.BytesPerSec)*float64(time.Second)))
+	}
+	sleep := due - time.Since(t.start)
+	checkPause := time.Since(t.lastCheck) >= throttlePauseCheck
+	if checkPause {
+		t.lastCheck = time.Now()
+	}
+	t.mu.Unlock()
+
+	if sleep > 0 {
+		time.Sleep(sleep)
+	}
+	if checkPause {
+		t.pauseWhileBusy()
+	}
+}
+
+// pauseWhileBusy blocks while on battery or under high load, up to throttleMaxPause.
+func (t *throttle) pauseWhileBusy() {
+	deadline := time.Now().Add(throttleMaxPause)
+	for time.Now().Before(deadline) && t.machineBusy() {
+		time.Sleep(throttlePauseCheck)
+	}
+}
+
+func (t *throttle) machineBusy() bool {
+	if t.profile.PauseOnBattery && OnBatteryPower() {
+		return true
+	}
+	if t.profile.MaxLoadPerCPU > 0 {
+		if avg, err := load.Avg(); err == nil && avg.Load1/float64(runtime.NumCPU()) > t.profile.MaxLoadPerCPU {
+			return true
+		}
+	}
+	return false
+}
+
+// OnBatteryPower reports whether a laptop is discharging. Desktops report false.
+func OnBatteryPower() bool {
+	if runtime.GOOS == "darwin" {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, "pmset", "-g", "batt").Output()
+		return err == nil && strings.Contains(string(out), "'Battery Power'")
+	}
+	matches, _ := filepath.Glob("/sys/class/power_supply/BAT*/status")
+	for _, statusFile := range matches {
+		if data, err := os.ReadFile(statusFile); err == nil && strings.TrimSpace(string(data)) == "Discharging" {
+			return true
+		}
+	}
+	return false
+}
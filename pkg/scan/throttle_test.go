@@ -0,0 +1,37 @@
+package scan
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottleLimits(t *testing.T) {
+	var none *throttle
+	if got := none.workers(16); got != 16 {
+		t.Fatalf("nil throttle should keep defaults, got %d", got)
+	}
+	none.wait(1<<30, 1<<40) // Must not block.
+
+	if newThrottle(FullSpeed) != nil {
+		t.Fatal("full speed profile should not allocate a throttle")
+	}
+
+	throttle := newThrottle(LowImpact)
+	if got := throttle.workers(16); got != LowImpact.Workers {
+		t.Fatalf("expected %d workers, got %d", LowImpact.Workers, got)
+	}
+	if got := throttle.foldWorkers(1); got != 1 {
+		t.Fatalf("profile must not raise a smaller default, got %d", got)
+	}
+}
+
+func TestThrottleWaitsForRate(t *testing.T) {
+	throttle := newThrottle(Profile{FilesPerSec: 1000})
+	throttle.lastCheck = time.Now() // Skip the battery and load check.
+
+	start := time.Now()
+	throttle.wait(100, 0)
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Fatalf("expected about 100ms of throttling, got %v", elapsed)
+	}
+}
@@ -0,0 +1,533 @@
+package scan
+
+import (
+	"cmp"
+	"container/heap"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// scanRoot lists the root and sizes each child in parallel, keeping the largest
+// entries and files.
+func (r *run) scanRoot() (Result, error) {
+	children, err := os.ReadDir(r.root)
+	if err != nil {
+		return Result{}, err
+	}
+	if r.opts.FollowSymlinks {
+		r.skipDir(r.root) // Mark the root so links back to it are not followed.
+	}
+
+	var total int64
+	var localFilesScanned int64
+	var localBytesScanned int64
+
+	// Keep Top N heaps.
+	entriesHeap := &entryHeap{}
+	heap.Init(entriesHeap)
+
+	largeFilesHeap := &largeFileHeap{}
+	heap.Init(largeFilesHeap)
+	largeFileMinSize := int64(largeFileWarmupMinSize)
+
+	// Worker pool sized for I/O-bound scanning.
+	numWorkers := orDefault(r.opts.Workers, min(max(runtime.NumCPU()*cpuMultiplier, minWorkers), maxWorkers))
+	numWorkers = max(min(r.throttle.workers(numWorkers), len(children)), 1)
+	sem := make(chan struct{}, numWorkers)
+	var wg sync.WaitGroup
+
+	// Collect results via channels. Sends wait for the collectors, so nothing is lost;
+	// the buffer only smooths bursts and is capped to bound memory on huge directories.
+	entryChan := make(chan Entry, max(min(len(children), 4096), 1))
+	largeFileChan := make(chan File, max(r.opts.TopFiles*2, 1))
+
+	var collectorWg sync.WaitGroup
+	collectorWg.Add(2)
+	go func() {
+		defer collectorWg.Done()
+		for entry := range entryChan {
+			if r.opts.TopEntries <= 0 || entriesHeap.Len() < r.opts.TopEntries {
+				heap.Push(entriesHeap, entry)
+			} else if entry.Size > (*entriesHeap)[0].Size {
+				heap.Pop(entriesHeap)
+				heap.Push(entriesHeap, entry)
+			}
+		}
+	}()
+	go func() {
+		defer collectorWg.Done()
+		for file := range largeFileChan {
+			if largeFilesHeap.Len() < r.opts.TopFiles {
+				heap.Push(largeFilesHeap, file)
+				if largeFilesHeap.Len() == r.opts.TopFiles {
+					atomic.StoreInt64(&largeFileMinSize, (*largeFilesHeap)[0].Size)
+				}
+			} else if file.Size > (*largeFilesHeap)[0].Size {
+				heap.Pop(largeFilesHeap)
+				heap.Push(largeFilesHeap, file)
+				atomic.StoreInt64(&largeFileMinSize, (*largeFilesHeap)[0].Size)
+			}
+		}
+	}()
+
+	emitDir := func(name, path string, size int64, entryOwners *ownerTally) {
+		atomic.AddInt64(&total, size)
+		r.dirs.Add(1)
+		r.owners.mergeTally(entryOwners)
+
+		send(r, entryChan, Entry{
+			Name:       name,
+			Path:       path,
+			Size:       size,
+			IsDir:      true,
+			OwnerSizes: entryOwners.userSizes(),
+		})
+	}
+
+	isRootDir := r.root == "/"
+
+	for _, child := range children {
+		if r.ctx.Err() != nil {
+			break
+		}
+		name := child.Name()
+		fullPath := filepath.Join(r.root, name)
+		if r.excluded(fullPath) {
+			continue
+		}
+
+		isDir := child.IsDir()
+		var target fs.FileInfo
+		if child.Type()&fs.ModeSymlink != 0 {
+			if target = r.follow(fullPath); target == nil {
+				// Count link size only to avoid double-counting targets.
+				info, err := child.Info()
+				if err != nil {
+					r.issues.add(fullPath, err)
+					continue
+				}
+				size := r.fileSize(info)
+				atomic.AddInt64(&total, size)
+
+				send(r, entryChan, Entry{
+					Name:       name + " →",
+					Path:       fullPath,
+					Size:       size,
+					IsDir:      isDirTarget(fullPath),
+					LastAccess: lastAccess(info),
+					OwnerSizes: r.owners.addFile(info, size),
+				})
+				continue
+			}
+			isDir = target.IsDir()
+			name += " →"
+		}
+
+		if isDir {
+			if r.opts.SkipDirs[child.Name()] {
+				continue
+			}
+
+			// Skip system dirs at root.
+			if isRootDir && r.opts.SkipRootDirs[child.Name()] {
+				continue
+			}
+
+			if r.skipDir(fullPath) {
+				continue
+			}
+
+			// Folded dirs: fast size without expanding.
+			if r.foldDir(child.Name(), fullPath) {
+				r.foldQueueSem <- struct{}{}
+				wg.Add(1)
+				go func(name, path string) {
+					defer wg.Done()
+					defer func() { <-r.foldQueueSem }()
+					r.throttle.lowerPriority()
+
+					entryOwners := newOwnerTally()
+					emitDir(name, path, r.sizeFolded(path, entryOwners), entryOwners)
+				}(name, fullPath)
+				continue
+			}
+
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(name, path string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				r.throttle.lowerPriority()
+
+				entryOwners := newOwnerTally()
+				size, known := r.knownSize(path)
+				if known {
+					entryOwners.addFolder(path, size)
+				} else {
+					size = r.sizeDir(path, largeFileChan, &largeFileMinSize, entryOwners)
+				}
+				emitDir(name, path, size, entryOwners)
+			}(name, fullPath)
+			continue
+		}
+
+		info := target
+		if info == nil {
+			if info, err = child.Info(); err != nil {
+				r.issues.add(fullPath, err)
+				continue
+			}
+		}
+		size := r.fileSize(info)
+		atomic.AddInt64(&total, size)
+		localFilesScanned++
+		localBytesScanned += size
+
+		send(r, entryChan, Entry{
+			Name:       name,
+			Path:       fullPath,
+			Size:       size,
+			IsDir:      false,
+			LastAccess: lastAccess(info),
+			OwnerSizes: r.owners.addFile(info, size),
+		})
+
+		r.offerLargeFile(largeFileChan, &largeFileMinSize, child.Name(), fullPath, size, info.ModTime())
+		r.offerRecent(child.Name(), fullPath, size, info.ModTime())
+	}
+
+	r.files.Add(localFilesScanned)
+	r.bytes.Add(localBytesScanned)
+
+	wg.Wait()
+
+	// Close channels and wait for collectors.
+	close(entryChan)
+	close(largeFileChan)
+	collectorWg.Wait()
+
+	// Convert heaps to sorted slices (descending).
+	entries := make([]Entry, entriesHeap.Len())
+	for i := len(entries) - 1; i >= 0; i-- {
+		entries[i] = heap.Pop(entriesHeap).(Entry)
+	}
+
+	largeFiles := make([]File, largeFilesHeap.Len())
+	for i := len(largeFiles) - 1; i >= 0; i-- {
+		largeFiles[i] = heap.Pop(largeFilesHeap).(File)
+	}
+
+	r.updateIndex()
+
+	return Result{
+		Root:       r.root,
+		Entries:    entries,
+		LargeFiles: largeFiles,
+		Recent:     r.recentFiles(),
+		TotalSize:  total,
+		TotalFiles: r.files.Load(),
+		Issues:     r.issues.report(),
+		Owners:     r.owners.report(),
+		Dropped:    r.dropped.Load(),
+		Device:     r.device,
+		Strategy:   r.strategy,
+	}, r.ctx.Err()
+}
+
+// sizeDir totals a directory and reports large files, using the platform's
+// syscall walker where there is one.
+func (r *run) sizeDir(root string, largeFileChan chan<- File, largeFileMinSize *int64, owners *ownerTally) int64 {
+	if r.ctx.Err() != nil {
+		return 0
+	}
+	if size, ok := r.walkFast(root, largeFileChan, largeFileMinSize, owners); ok {
+		return size
+	}
+	return r.sizeDirPortable(root, largeFileChan, largeFileMinSize, owners)
+}
+
+// sizeDirPortable walks a directory with os.ReadDir in parallel, bounded by dirSem.
+func (r *run) sizeDirPortable(root string, largeFileChan chan<- File, largeFileMinSize *int64, owners *ownerTally) int64 {
+	if r.ctx.Err() != nil {
+		return 0
+	}
+	children, err := os.ReadDir(root)
+	if err != nil {
+		r.issues.add(root, err)
+		return 0
+	}
+	if r.strategy == StrategySequential {
+		children = sortByInode(root, children)
+	}
+
+	var total int64
+	var localFilesScanned int64
+	var localDirsScanned int64
+	var localBytesScanned int64
+	var local localOwners
+	var wg sync.WaitGroup
+
+	for _, child := range children {
+		fullPath := filepath.Join(root, child.Name())
+		if r.excluded(fullPath) {
+			continue
+		}
+
+		isDir := child.IsDir()
+		var target fs.FileInfo
+		if child.Type()&fs.ModeSymlink != 0 {
+			if target = r.follow(fullPath); target == nil {
+				info, err := child.Info()
+				if err != nil {
+					r.issues.add(fullPath, err)
+					continue
+				}
+				size := r.fileSize(info)
+				atomic.AddInt64(&total, size)
+				localFilesScanned++
+				localBytesScanned += size
+				local.add(info, size, 1)
+				continue
+			}
+			isDir = target.IsDir()
+		}
+
+		if isDir {
+			if r.skipDir(fullPath) {
+				continue
+			}
+			localDirsScanned++
+
+			if r.foldDir(child.Name(), fullPath) {
+				r.foldQueueSem <- struct{}{}
+				wg.Add(1)
+				go func(path string) {
+					defer wg.Done()
+					defer func() { <-r.foldQueueSem }()
+					r.throttle.lowerPriority()
+
+					atomic.AddInt64(&total, r.sizeFolded(path, owners))
+				}(fullPath)
+				continue
+			}
+
+			select {
+			case r.dirSem <- struct{}{}:
+				wg.Add(1)
+				go func(path string) {
+					defer wg.Done()
+					defer func() { <-r.dirSem }()
+					r.throttle.lowerPriority()
+
+					atomic.AddInt64(&total, r.sizeDirPortable(path, largeFileChan, largeFileMinSize, owners))
+				}(fullPath)
+			default:
+				atomic.AddInt64(&total, r.sizeDirPortable(fullPath, largeFileChan, largeFileMinSize, owners))
+			}
+			continue
+		}
+
+		info := target
+		if info == nil {
+			if info, err = child.Info(); err != nil {
+				r.issues.add(fullPath, err)
+				continue
+			}
+		}
+
+		size := r.fileSize(info)
+		atomic.AddInt64(&total, size)
+		localFilesScanned++
+		localBytesScanned += size
+		local.add(info, size, 1)
+
+		r.offerLargeFile(largeFileChan, largeFileMinSize, child.Name(), fullPath, size, info.ModTime())
+		r.offerRecent(child.Name(), fullPath, size, info.ModTime())
+
+		// Update current path occasionally to prevent UI jitter.
+		if localFilesScanned%currentPathEvery == 0 {
+			r.currentPath.Store(fullPath)
+		}
+	}
+
+	owners.merge(&local)
+	r.throttle.wait(localFilesScanned, localBytesScanned)
+	wg.Wait()
+
+	r.files.Add(localFilesScanned)
+	r.bytes.Add(localBytesScanned)
+	r.dirs.Add(localDirsScanned)
+
+	return atomic.LoadInt64(&total)
+}
+
+// sortByInode orders entries by inode, which roughly follows the layout on
+// disk, so a spinning disk seeks forward. os.ReadDir does not expose inodes, so
+// each entry is stat'ed here and carries that result on to Info.
+func sortByInode(dir string, entries []fs.DirEntry) []fs.DirEntry {
+	inodes := make(map[string]uint64, len(entries))
+	for i, entry := range entries {
+		info, err := os.Lstat(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue // Left as is, so the walk reports the error.
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			inodes[entry.Name()] = stat.Ino
+		}
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	slices.SortStableFunc(entries, func(a, b fs.DirEntry) int {
+		return cmp.Compare(inodes[a.Name()], inodes[b.Name()])
+	})
+	return entries
+}
+
+// sizeFolded sizes a folded directory in one step, without listing its entries.
+func (r *run) sizeFolded(path string, owners *ownerTally) int64 {
+	r.foldSem <- struct{}{}
+	defer func() { <-r.foldSem }()
+	return r.sizeTree(path, owners)
+}
+
+// offerLargeFile passes a file to the large-file collector when it could make
+// the list, and to the file index when it is big enough.
+func (r *run) offerLargeFile(ch chan<- File, minSize *int64, name, path string, size int64, modTime time.Time) {
+	indexed := r.opts.FileIndex != nil && size >= r.opts.FileIndex.MinSize
+	listed := ch != nil && r.opts.TopFiles > 0 && size >= atomic.LoadInt64(minSize)
+	if !indexed && !listed || r.skipLargeFile(path) {
+		return
+	}
+	file := File{Name: name, Path: path, Size: size, ModTime: modTime}
+	if indexed {
+		r.indexMu.Lock()
+		r.indexed = append(r.indexed, file)
+		r.indexMu.Unlock()
+	}
+	if listed {
+		send(r, ch, file)
+	}
+}
+
+// offerRecent keeps a file modified since Options.RecentSince for
+// Result.Recent. Past twice maxRecentFiles the smallest are dropped.
+func (r *run) offerRecent(name, path string, size int64, modTime time.Time) {
+	if r.opts.RecentSince.IsZero() || modTime.Before(r.opts.RecentSince) {
+		return
+	}
+	r.recentMu.Lock()
+	defer r.recentMu.Unlock()
+	r.recent = append(r.recent, File{Name: name, Path: path, Size: size, ModTime: modTime})
+	if len(r.recent) >= 2*maxRecentFiles {
+		r.recent = largestFiles(r.recent, maxRecentFiles)
+	}
+}
+
+// recentFiles returns the files offerRecent kept, largest first.
+func (r *run) recentFiles() []File {
+	r.recentMu.Lock()
+	defer r.recentMu.Unlock()
+	return largestFiles(r.recent, maxRecentFiles)
+}
+
+// largestFiles sorts files largest first and keeps at most n.
+func largestFiles(files []File, n int) []File {
+	slices.SortFunc(files, func(a, b File) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), strings.Compare(a.Path, b.Path))
+	})
+	return files[:min(len(files), n)]
+}
+
+// knownSize asks Options.KnownSize for a precomputed directory size.
+func (r *run) knownSize(path string) (int64, bool) {
+	if r.opts.KnownSize == nil {
+		return 0, false
+	}
+	return r.opts.KnownSize(path)
+}
+
+// follow returns what a symlink points to when links are followed, or nil to
+// count the link itself.
+func (r *run) follow(path string) fs.FileInfo {
+	if !r.opts.FollowSymlinks {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	return info
+}
+
+// skipDir reports whether a directory must not be entered: it sits on another
+// filesystem under OneFilesystem, or was already visited through a symlink.
+func (r *run) skipDir(path string) bool {
+	if !r.opts.OneFilesystem && !r.opts.FollowSymlinks {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	dev := uint64(stat.Dev)
+	if r.opts.OneFilesystem && r.rootDev != 0 && dev != r.rootDev {
+		return true
+	}
+	if r.opts.FollowSymlinks {
+		_, seen := r.visited.LoadOrStore(fileID{dev: dev, ino: stat.Ino}, struct{}{})
+		return seen
+	}
+	return false
+}
+
+// fileID identifies a directory across the different paths that reach it.
+type fileID struct {
+	dev, ino uint64
+}
+
+// deviceOf returns the filesystem id holding info.
+func deviceOf(info fs.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true
+}
+
+func (r *run) fileSize(info fs.FileInfo) int64 {
+	if r.opts.SizeMode == SizeApparent {
+		return info.Size()
+	}
+	return DiskUsage(info)
+}
+
+// DiskUsage returns the bytes info occupies on disk, which is less than its
+// length for sparse and cloud placeholder files.
+func DiskUsage(info fs.FileInfo) int64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.Size()
+	}
+
+	actualSize := stat.Blocks * 512
+	if actualSize < info.Size() {
+		return actualSize
+	}
+	return info.Size()
+}
+
+func isDirTarget(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
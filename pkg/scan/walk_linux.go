@@ -0,0 +1,365 @@
+package scan
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	direntBufSize = 256 << 10 // getdents64 buffer per worker.
+	maxQueuedDirs = 4096      // Directories a worker queues for stealing before keeping the rest to itself.
+	idleSpins     = 64        // Yields before an idle worker starts sleeping.
+	idleSleep     = 100 * time.Microsecond
+
+	// Only the fields the totals need; the kernel can skip the rest.
+	statxMask = unix.STATX_TYPE | unix.STATX_MODE | unix.STATX_UID | unix.STATX_GID | unix.STATX_SIZE | unix.STATX_BLOCKS | unix.STATX_MTIME
+)
+
+// statxMissing is set once the kernel reports statx as unavailable (before 4.11).
+var statxMissing atomic.Bool
+
+// walkFast sizes root with getdents64 and per-entry statx relative to each open
+// directory, spread over workers that steal directories from each other. The
+// calling goroutine is the first worker; helpers join while dirSem has room.
+// Followed symlinks need path-based stats, so those scans use the portable walk.
+func (r *run) walkFast(root string, largeFileChan chan<- File, largeFileMinSize *int64, owners *ownerTally) (int64, bool) {
+	if r.opts.FollowSymlinks {
+		return 0, false
+	}
+
+	w := &dirWalker{
+		r:                r,
+		owners:           owners,
+		largeFileChan:    largeFileChan,
+		largeFileMinSize: largeFileMinSize,
+		queues:           make([]*dirQueue, r.throttle.dirWorkers(min(runtime.NumCPU(), maxDirWorkers))),
+	}
+	for i := range w.queues {
+		w.queues[i] = &dirQueue{}
+	}
+	w.pending.Store(1)
+	w.queues[0].push(root)
+
+	var helpers sync.WaitGroup
+	for i := 1; i < len(w.queues); i++ {
+		select {
+		case r.dirSem <- struct{}{}:
+			helpers.Add(1)
+			go func(id int) {
+				defer helpers.Done()
+				defer func() { <-r.dirSem }()
+				r.throttle.lowerPriority()
+				w.work(id)
+			}(i)
+		default:
+			i = len(w.queues) // No room for more helpers.
+		}
+	}
+	w.work(0)
+	helpers.Wait()
+	w.folded.Wait()
+
+	return w.total.Load(), true
+}
+
+// dirWalker is the shared state of one walkFast call.
+type dirWalker struct {
+	r                *run
+	owners           *ownerTally
+	largeFileChan    chan<- File
+	largeFileMinSize *int64
+
+	queues  []*dirQueue
+	pending atomic.Int64 // Directories queued or being read; zero means done.
+	total   atomic.Int64
+	folded  sync.WaitGroup // Sizes of folded directories.
+}
+
+// dirQueue is one worker's deque: the owner pops the newest directory, thieves
+// take the oldest, which tends to be the largest remaining subtree.
+type dirQueue struct {
+	mu   sync.Mutex
+	dirs []string
+}
+
+func (q *dirQueue) push(path string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.dirs) >= maxQueuedDirs {
+		return false
+	}
+	q.dirs = append(q.dirs, path)
+	return true
+}
+
+func (q *dirQueue) pop() (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := len(q.dirs)
+	if n == 0 {
+		return "", false
+	}
+	path := q.dirs[n-1]
+	q.dirs = q.dirs[:n-1]
+	return path, true
+}
+
+func (q *dirQueue) steal() (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.dirs) == 0 {
+		return "", false
+	}
+	path := q.dirs[0]
+	q.dirs = q.dirs[1:]
+	return path, true
+}
+
+// work reads directories until every queue is empty and nothing is in flight.
+// Directories that do not fit in the queue stay on a private stack, so depth
+// never turns into recursion.
+func (w *dirWalker) work(id int) {
+	own := w.queues[id]
+	buf := make([]byte, direntBufSize)
+	var overflow []string
+	idle := 0
+
+	for {
+		var path string
+		ok := false
+		if n := len(overflow); n > 0 {
+			path, overflow, ok = overflow[n-1], overflow[:n-1], true
+		} else if path, ok = own.pop(); !ok {
+			path, ok = w.steal(id)
+		}
+
+		if !ok {
+			if w.pending.Load() == 0 {
+				return
+			}
+			if idle++; idle < idleSpins {
+				runtime.Gosched()
+			} else {
+				time.Sleep(idleSleep)
+			}
+			continue
+		}
+		idle = 0
+
+		w.readDir(path, buf, func(sub string) {
+			w.pending.Add(1)
+			if !own.push(sub) {
+				overflow = append(overflow, sub)
+			}
+		})
+		w.pending.Add(-1)
+	}
+}
+
+func (w *dirWalker) steal(id int) (string, bool) {
+	for i := 1; i < len(w.queues); i++ {
+		if path, ok := w.queues[(id+i)%len(w.queues)].steal(); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// readDir totals the files directly in dir and hands subdirectories to queue.
+// The sequential strategy reads entries in inode order.
+func (w *dirWalker) readDir(dir string, buf []byte, queue func(string)) {
+	r := w.r
+	if r.ctx.Err() != nil {
+		return
+	}
+
+	fd, err := unix.Open(dir, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC|unix.O_NOFOLLOW, 0)
+	if err != nil {
+		r.issues.add(dir, &fs.PathError{Op: "open", Path: dir, Err: err})
+		return
+	}
+	defer unix.Close(fd) //nolint:errcheck
+
+	if r.opts.OneFilesystem && r.rootDev != 0 {
+		var st unix.Stat_t
+		if unix.Fstat(fd, &st) == nil && uint64(st.Dev) != r.rootDev {
+			return
+		}
+	}
+
+	entries := w.list(fd, dir, buf)
+	sequential := r.strategy == StrategySequential
+	if sequential {
+		// Inode order roughly follows the layout on disk, so a spinning disk seeks forward.
+		slices.SortFunc(entries, func(a, b dirent) int { return cmp.Compare(a.ino, b.ino) })
+	}
+
+	var localFiles, localDirs, localBytes int64
+	var local localOwners
+	var subdirs []string
+
+	for _, entry := range entries {
+		name := entry.name
+		fullPath := dir + "/" + name
+		if r.excluded(fullPath) {
+			continue
+		}
+
+		if entry.typ != unix.DT_DIR {
+			st, err := statAt(fd, name)
+			if err != nil {
+				r.issues.add(fullPath, &fs.PathError{Op: "stat", Path: fullPath, Err: err})
+				continue
+			}
+			if st.mode&unix.S_IFMT != unix.S_IFDIR {
+				size := st.size
+				if r.opts.SizeMode != SizeApparent {
+					size = min(st.blocks*512, st.size)
+				}
+				localFiles++
+				localBytes += size
+				local.addIDs(st.uid, st.gid, size, 1)
+				if st.mode&unix.S_IFMT == unix.S_IFREG {
+					r.offerLargeFile(w.largeFileChan, w.largeFileMinSize, name, fullPath, size, st.mtime)
+					r.offerRecent(name, fullPath, size, st.mtime)
+				}
+				if localFiles%currentPathEvery == 0 {
+					r.currentPath.Store(fullPath)
+				}
+				continue
+			}
+			// DT_UNKNOWN on some filesystems; statx says it is a directory.
+		}
+
+		localDirs++
+		if r.foldDir(name, fullPath) {
+			r.foldQueueSem <- struct{}{}
+			w.folded.Add(1)
+			go func(path string) {
+				defer w.folded.Done()
+				defer func() { <-r.foldQueueSem }()
+				r.throttle.lowerPriority()
+				w.total.Add(r.sizeFolded(path, w.owners))
+			}(fullPath)
+			continue
+		}
+		subdirs = append(subdirs, fullPath)
+	}
+
+	if sequential {
+		// The newest queued directory is read first, so queue them last to first.
+		slices.Reverse(subdirs)
+	}
+	for _, sub := range subdirs {
+		queue(sub)
+	}
+
+	w.total.Add(localBytes)
+	w.owners.merge(&local)
+	r.files.Add(localFiles)
+	r.dirs.Add(localDirs)
+	r.bytes.Add(localBytes)
+	r.throttle.wait(localFiles, localBytes)
+}
+
+// dirent is one name from getdents64.
+type dirent struct {
+	name string
+	ino  uint64
+	typ  uint8
+}
+
+// list reads every entry of the open directory except "." and "..".
+func (w *dirWalker) list(fd int, dir string, buf []byte) []dirent {
+	var entries []dirent
+	for {
+		n, err := unix.Getdents(fd, buf)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			w.r.issues.add(dir, &fs.PathError{Op: "getdents", Path: dir, Err: err})
+			return entries
+		}
+		if n <= 0 {
+			return entries
+		}
+
+		// linux_dirent64: ino u64, off s64, reclen u16, type u8, then a NUL-terminated name.
+		for off := 0; off+19 <= n; {
+			reclen := int(binary.NativeEndian.Uint16(buf[off+16:]))
+			if reclen == 0 || off+reclen > n {
+				break
+			}
+			ino := binary.NativeEndian.Uint64(buf[off:])
+			typ := buf[off+18]
+			nameBytes := buf[off+19 : off+reclen]
+			off += reclen
+			if i := bytes.IndexByte(nameBytes, 0); i >= 0 {
+				nameBytes = nameBytes[:i]
+			}
+			if isDotEntry(nameBytes) {
+				continue
+			}
+			entries = append(entries, dirent{name: string(nameBytes), ino: ino, typ: typ})
+		}
+	}
+}
+
+// entryStat is the part of a stat result the walker uses.
+type entryStat struct {
+	mode     uint32
+	uid, gid uint32
+	size     int64
+	blocks   int64
+	mtime    time.Time
+}
+
+// statAt stats name inside the directory open as dirfd without following links.
+func statAt(dirfd int, name string) (entryStat, error) {
+	if !statxMissing.Load() {
+		var stx unix.Statx_t
+		err := unix.Statx(dirfd, name, unix.AT_SYMLINK_NOFOLLOW|unix.AT_STATX_DONT_SYNC, statxMask, &stx)
+		if err == nil {
+			return entryStat{
+				mode:   uint32(stx.Mode),
+				uid:    stx.Uid,
+				gid:    stx.Gid,
+				size:   int64(stx.Size),
+				blocks: int64(stx.Blocks),
+				mtime:  time.Unix(stx.Mtime.Sec, int64(stx.Mtime.Nsec)),
+			}, nil
+		}
+		if !errors.Is(err, unix.ENOSYS) {
+			return entryStat{}, err
+		}
+		statxMissing.Store(true)
+	}
+
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return entryStat{}, err
+	}
+	return entryStat{
+		mode:   st.Mode,
+		uid:    st.Uid,
+		gid:    st.Gid,
+		size:   st.Size,
+		blocks: st.Blocks,
+		mtime:  time.Unix(st.Mtim.Unix()),
+	}, nil
+}
+
+func isDotEntry(name []byte) bool {
+	return len(name) == 1 && name[0] == '.' || len(name) == 2 && name[0] == '.' && name[1] == '.'
+}
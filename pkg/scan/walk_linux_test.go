@@ -0,0 +1,138 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestWalkFastMatchesPortableWalk(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a.bin"), 2<<20)
+	writeFileWithSize(t, filepath.Join(root, "nested", "deeper", "b.bin"), 3000)
+	writeFileWithSize(t, filepath.Join(root, "node_modules", "pkg", "index.js"), 500)
+	if err := os.Symlink(filepath.Join(root, "a.bin"), filepath.Join(root, "nested", "link")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	// More subdirectories than one queue holds, to exercise the overflow stack.
+	for i := range maxQueuedDirs + 10 {
+		if err := os.Mkdir(filepath.Join(root, "nested", "wide-"+strconv.Itoa(i)), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+
+	measure := func(fast bool) (int64, *run, []File) {
+		r := New(Options{SizeMode: SizeApparent, TopFiles: 5}).newRun(context.Background(), root)
+		defer r.cancel()
+		ch := make(chan File, 16)
+		minSize := int64(0)
+		owners := newOwnerTally()
+		var size int64
+		if fast {
+			var ok bool
+			if size, ok = r.walkFast(root, ch, &minSize, owners); !ok {
+				t.Fatal("walkFast declined a plain scan")
+			}
+		} else {
+			size = r.sizeDirPortable(root, ch, &minSize, owners)
+		}
+		close(ch)
+		var files []File
+		for f := range ch {
+			files = append(files, f)
+		}
+		return size, r, files
+	}
+
+	wantSize, want, wantFiles := measure(false)
+	gotSize, got, gotFiles := measure(true)
+	if gotSize != wantSize {
+		t.Fatalf("walkFast total %d, portable %d", gotSize, wantSize)
+	}
+	if got.files.Load() != want.files.Load() || got.dirs.Load() != want.dirs.Load() {
+		t.Fatalf("walkFast counted %d files in %d dirs, portable %d in %d",
+			got.files.Load(), got.dirs.Load(), want.files.Load(), want.dirs.Load())
+	}
+	if len(gotFiles) != len(wantFiles) {
+		t.Fatalf("walkFast offered %d large files, portable %d", len(gotFiles), len(wantFiles))
+	}
+}
+
+func TestWalkFastReportsUnreadableDirs(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read every directory")
+	}
+	root := t.TempDir()
+	locked := filepath.Join(root, "locked")
+	writeFileWithSize(t, filepath.Join(locked, "x"), 10)
+	if err := os.Chmod(locked, 0o000); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(locked, 0o755) })
+
+	r := New(Options{}).newRun(context.Background(), root)
+	defer r.cancel()
+	r.walkFast(root, nil, nil, nil)
+	report := r.issues.report()
+	if report.Total != 1 || report.Issues[0].Kind != IssuePermission {
+		t.Fatalf("expected one permission issue, got %+v", report)
+	}
+}
+
+// benchTree builds a tree of MOLE_BENCH_FILES files (default 50000), 100 per
+// directory, in a temp dir removed after the benchmark. A 5000000 file tree
+// takes minutes to create, so with MOLE_BENCH_DIR set it is built there instead
+// and kept between runs; delete it to rebuild or to free the space.
+func benchTree(b *testing.B) (string, error) {
+	count := 50000
+	if v, err := strconv.Atoi(os.Getenv("MOLE_BENCH_FILES")); err == nil && v > 0 {
+		count = v
+	}
+	root := b.TempDir()
+	if dir := os.Getenv("MOLE_BENCH_DIR"); dir != "" {
+		root = filepath.Join(dir, fmt.Sprintf("mole-bench-%d", count))
+	}
+	done := filepath.Join(root, ".complete")
+	if _, err := os.Stat(done); err == nil {
+		return root, nil
+	}
+	for i := 0; i < count; i += 100 {
+		dir := filepath.Join(root, fmt.Sprintf("d%03d", i/100000), fmt.Sprintf("d%03d", i/100%1000))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", err
+		}
+		for j := 0; j < 100 && i+j < count; j++ {
+			if err := os.WriteFile(filepath.Join(dir, strconv.Itoa(j)), nil, 0o644); err != nil {
+				return "", err
+			}
+		}
+	}
+	return root, os.WriteFile(done, nil, 0o644)
+}
+
+func benchmarkSizeDir(b *testing.B, fast bool) {
+	root, err := benchTree(b)
+	if err != nil {
+		b.Fatalf("build tree: %v", err)
+	}
+	var files int64
+	b.ResetTimer()
+	for range b.N {
+		r := New(Options{}).newRun(context.Background(), root)
+		minSize := int64(largeFileWarmupMinSize)
+		if fast {
+			r.walkFast(root, nil, &minSize, newOwnerTally())
+		} else {
+			r.sizeDirPortable(root, nil, &minSize, newOwnerTally())
+		}
+		r.cancel()
+		files += r.files.Load()
+	}
+	b.ReportMetric(float64(files)/b.Elapsed().Seconds(), "files/s")
+}
+
+func BenchmarkSizeDirGetdents(b *testing.B) { benchmarkSizeDir(b, true) }
+func BenchmarkSizeDirReadDir(b *testing.B)  { benchmarkSizeDir(b, false) }
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package scan
+
+// walkFast has no syscall-level implementation here; sizeDir uses the portable walk.
+func (r *run) walkFast(string, chan<- File, *int64, *ownerTally) (int64, bool) {
+	return 0, false
+}
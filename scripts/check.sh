@@ -116,7 +116,7 @@ if command -v golangci-lint > /dev/null 2>&1; then
         echo -e "${RED}${ICON_ERROR} golangci-lint config invalid${NC}\n"
         exit 1
     fi
-    if golangci-lint run ./cmd/...; then
+    if golangci-lint run ./cmd/... ./pkg/... ./internal/...; then
         echo -e "${GREEN}${ICON_SUCCESS} golangci-lint passed${NC}\n"
     else
         echo -e "${RED}${ICON_ERROR} golangci-lint failed${NC}\n"
@@ -124,7 +124,7 @@ if command -v golangci-lint > /dev/null 2>&1; then
     fi
 elif command -v go > /dev/null 2>&1; then
     echo -e "${YELLOW}${ICON_WARNING} golangci-lint not installed, falling back to go vet${NC}"
-    if go vet ./cmd/...; then
+    if go vet ./cmd/... ./pkg/... ./internal/...; then
         echo -e "${GREEN}${ICON_SUCCESS} go vet passed${NC}\n"
     else
         echo -e "${RED}${ICON_ERROR} go vet failed${NC}\n"